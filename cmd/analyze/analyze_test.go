@@ -1,13 +1,17 @@
 package main
 
 import (
+	"context"
 	"encoding/gob"
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
 )
 
 func resetOverviewSnapshotForTest() {
@@ -48,7 +52,7 @@ func TestScanPathConcurrentBasic(t *testing.T) {
 	current := &atomic.Value{}
 	current.Store("")
 
-	result, err := scanPathConcurrent(root, &filesScanned, &dirsScanned, &bytesScanned, current)
+	result, err := scanPathConcurrent(context.Background(), root, nil, &filesScanned, &dirsScanned, &bytesScanned, current)
 	if err != nil {
 		t.Fatalf("scanPathConcurrent returned error: %v", err)
 	}
@@ -210,7 +214,7 @@ func TestMeasureOverviewSize(t *testing.T) {
 		t.Fatalf("write file: %v", err)
 	}
 
-	size, err := measureOverviewSize(target)
+	size, err := measureOverviewSize(context.Background(), target, nil, nil, nil)
 	if err != nil {
 		t.Fatalf("measureOverviewSize: %v", err)
 	}
@@ -233,7 +237,7 @@ func TestMeasureOverviewSize(t *testing.T) {
 	if err := os.WriteFile(filepath.Join(target, "data2.bin"), content, 0o644); err != nil {
 		t.Fatalf("write file: %v", err)
 	}
-	size2, err := measureOverviewSize(target)
+	size2, err := measureOverviewSize(context.Background(), target, nil, nil, nil)
 	if err != nil {
 		t.Fatalf("measureOverviewSize: %v", err)
 	}
@@ -415,6 +419,11 @@ func TestLoadCacheExpiresWhenDirectoryChanges(t *testing.T) {
 }
 
 func TestScanPathPermissionError(t *testing.T) {
+	// Root bypasses directory permissions, common in Linux CI containers.
+	if os.Geteuid() == 0 {
+		t.Skip("Skipping permission test when running as root")
+	}
+
 	root := t.TempDir()
 	lockedDir := filepath.Join(root, "locked")
 	if err := os.Mkdir(lockedDir, 0o755); err != nil {
@@ -440,7 +449,7 @@ func TestScanPathPermissionError(t *testing.T) {
 	current.Store("")
 
 	// Scanning the locked dir itself should fail.
-	_, err := scanPathConcurrent(lockedDir, &files, &dirs, &bytes, current)
+	_, err := scanPathConcurrent(context.Background(), lockedDir, nil, &files, &dirs, &bytes, current)
 	if err == nil {
 		t.Fatalf("expected error scanning locked directory, got nil")
 	}
@@ -448,3 +457,170 @@ func TestScanPathPermissionError(t *testing.T) {
 		t.Logf("unexpected error type: %v", err)
 	}
 }
+
+func TestStaleScanResultIsKeptAsPartial(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	m := newModel("/tmp/current", false)
+	_, staleID := m.scans.begin("/tmp/left")
+	_, currentID := m.scans.begin("/tmp/current")
+
+	stale := scanResultMsg{
+		path: "/tmp/left",
+		id:   staleID,
+		result: scanResult{
+			Entries:    []dirEntry{{Name: "big", Path: "/tmp/left/big", Size: 100, IsDir: true, Incomplete: true}},
+			TotalSize:  100,
+			Incomplete: true,
+		},
+	}
+	updated, _ := m.Update(stale)
+	m = updated.(model)
+	if !m.scanning {
+		t.Fatal("stale result must not end the current scan")
+	}
+	cached, ok := m.cache["/tmp/left"]
+	if !ok || !cached.Incomplete || len(cached.Entries) != 1 {
+		t.Fatalf("expected partial result cached for /tmp/left, got %+v", cached)
+	}
+
+	current := scanResultMsg{
+		path:   "/tmp/current",
+		id:     currentID,
+		result: scanResult{Entries: []dirEntry{{Name: "f", Path: "/tmp/current/f", Size: 10}}, TotalSize: 10},
+	}
+	updated, _ = m.Update(current)
+	m = updated.(model)
+	if m.scanning || m.incomplete || len(m.entries) != 1 {
+		t.Fatalf("expected current result applied, scanning=%v incomplete=%v entries=%d", m.scanning, m.incomplete, len(m.entries))
+	}
+}
+
+func TestOverviewWalksStopWithTheOverview(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	resetOverviewSnapshotForTest()
+	t.Cleanup(resetOverviewSnapshotForTest)
+
+	target := filepath.Join(home, "walked")
+	if err := os.MkdirAll(target, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	pending := func(m *model) context.Context {
+		m.entries = []dirEntry{{Name: "walked", Path: target, Size: -1, IsDir: true}}
+		m.overviewScanningSet = make(map[string]bool)
+		if m.scheduleOverviewScans() == nil {
+			t.Fatal("expected a size walk to start")
+		}
+		ctx, _ := m.overviewScans.join("")
+		return ctx
+	}
+
+	m := newModel("/", true)
+	walking := pending(&m)
+	_, id := m.overviewScans.join("")
+	updated, _ := m.updateKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	m = updated.(model)
+	if walking.Err() == nil {
+		t.Fatal("refresh should cancel the running walks")
+	}
+
+	// The cancelled walk reports back; it must not read as a failure.
+	m.entries = []dirEntry{{Name: "walked", Path: target, Size: -1, IsDir: true}}
+	updated, _ = m.Update(overviewSizeMsg{Path: target, Err: context.Canceled, ID: id})
+	m = updated.(model)
+	if m.entries[0].Size != -1 || strings.Contains(m.status, "Unable to measure") {
+		t.Fatalf("stale walk should be ignored, size=%d status=%q", m.entries[0].Size, m.status)
+	}
+
+	walking = pending(&m)
+	updated, _ = m.enterSelectedDir()
+	m = updated.(model)
+	if walking.Err() == nil {
+		t.Fatal("leaving the overview should cancel the running walks")
+	}
+
+	m = newModel("/", true)
+	walking = pending(&m)
+	_, _ = m.updateKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	if walking.Err() == nil {
+		t.Fatal("quitting should cancel the running walks")
+	}
+
+	if _, err := measureOverviewSize(walking, target, nil, nil, nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled walk should not fall back to a cached size, got %v", err)
+	}
+}
+
+func TestMergeLiveEntries(t *testing.T) {
+	live := mergeLiveEntries(nil, []dirEntry{
+		{Name: "small", Path: "/x/small", Size: 10},
+		{Name: "empty", Path: "/x/empty", Size: 0},
+	})
+	live = mergeLiveEntries(live, []dirEntry{
+		{Name: "big", Path: "/x/big", Size: 100},
+		{Name: "small", Path: "/x/small", Size: 20},
+	})
+
+	if len(live) != 2 {
+		t.Fatalf("expected 2 live entries, got %d: %+v", len(live), live)
+	}
+	if live[0].Name != "big" || live[1].Size != 20 {
+		t.Fatalf("expected big first and small updated in place, got %+v", live)
+	}
+}
+
+func TestScanEntriesMsgUpdatesLiveView(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	m := newModel("/tmp/current", false)
+	_, staleID := m.scans.begin("/tmp/current")
+	_, currentID := m.scans.begin("/tmp/current")
+	updates := make(chan dirEntry)
+
+	updated, cmd := m.Update(scanEntriesMsg{path: "/tmp/current", id: staleID, entries: []dirEntry{{Name: "old", Path: "/tmp/current/old", Size: 1}}, updates: updates})
+	m = updated.(model)
+	if len(m.liveEntries) != 0 || cmd != nil {
+		t.Fatalf("stale entries must be ignored, got %+v", m.liveEntries)
+	}
+
+	updated, cmd = m.Update(scanEntriesMsg{path: "/tmp/current", id: currentID, entries: []dirEntry{{Name: "new", Path: "/tmp/current/new", Size: 5}}, updates: updates})
+	m = updated.(model)
+	if len(m.liveEntries) != 1 || cmd == nil {
+		t.Fatalf("expected live entry and a re-armed listener, got %+v cmd=%v", m.liveEntries, cmd != nil)
+	}
+	if !strings.Contains(m.View(), "new") {
+		t.Fatal("expected live entry to be rendered while scanning")
+	}
+}
+
+func TestScanResultShowsDeltaSinceBaseline(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	m := newModel("/tmp/current", false)
+	m.width, m.height = 120, 40
+	_, id := m.scans.begin("/tmp/current")
+	baseline := &scanRecord{
+		Path:      "/tmp/current",
+		ScannedAt: time.Now().Add(-3 * 24 * time.Hour),
+		TotalSize: 1 << 30,
+		Entries:   []scanRecordEntry{{Path: "/tmp/current/big", Size: 1 << 30}},
+	}
+
+	updated, _ := m.Update(scanResultMsg{
+		path:     "/tmp/current",
+		id:       id,
+		result:   scanResult{Entries: []dirEntry{{Name: "big", Path: "/tmp/current/big", Size: 5 << 30}}, TotalSize: 5 << 30},
+		baseline: baseline,
+	})
+	m = updated.(model)
+	if m.baseline != baseline {
+		t.Fatal("expected baseline kept on the model")
+	}
+	if view := m.View(); !strings.Contains(view, "+4.0 GB since") {
+		t.Fatalf("expected delta in view, got:\n%s", view)
+	}
+	if cached := m.cache["/tmp/current"]; cached.Baseline != baseline {
+		t.Fatal("expected baseline stored with the session cache")
+	}
+}
@@ -37,6 +37,8 @@ func snapshotFromModel(m model) historyEntry {
 		LargeSelected: m.largeSelected,
 		LargeOffset:   m.largeOffset,
 		IsOverview:    m.isOverview,
+		Incomplete:    m.incomplete,
+		Baseline:      m.baseline,
 	}
 }
 
@@ -220,6 +222,10 @@ func loadCacheFromDisk(path string) (*cacheEntry, error) {
 }
 
 func saveCacheToDisk(path string, result scanResult) error {
+	return saveCacheToDiskAt(path, result, time.Now())
+}
+
+func saveCacheToDiskAt(path string, result scanResult, scanTime time.Time) error {
 	cachePath, err := getCachePath(path)
 	if err != nil {
 		return err
@@ -231,12 +237,13 @@ func saveCacheToDisk(path string, result scanResult) error {
 	}
 
 	entry := cacheEntry{
+		Path:       path,
 		Entries:    result.Entries,
 		LargeFiles: result.LargeFiles,
 		TotalSize:  result.TotalSize,
 		TotalFiles: result.TotalFiles,
 		ModTime:    info.ModTime(),
-		ScanTime:   time.Now(),
+		ScanTime:   scanTime,
 	}
 
 	file, err := os.Create(cachePath)
@@ -321,7 +328,7 @@ func prefetchOverviewCache(ctx context.Context) {
 		default:
 		}
 
-		size, err := measureOverviewSize(path)
+		size, err := measureOverviewSize(ctx, path, nil, nil, nil)
 		if err == nil && size > 0 {
 			_ = storeOverviewSize(path, size)
 		}
@@ -11,11 +11,20 @@ const (
 	defaultViewport        = 12
 	overviewCacheTTL       = 7 * 24 * time.Hour
 	overviewCacheFile      = "overview_sizes.json"
+	historyDirName         = "history"
+	maxHistoryRecords      = 100
 	duTimeout              = 30 * time.Second
+	overviewTimeout        = 5 * time.Minute
 	mdlsTimeout            = 5 * time.Second
 	maxConcurrentOverview  = 8
 	batchUpdateSize        = 100
 	cacheModTimeGrace      = 30 * time.Minute
+	scanUpdateBuffer       = 1024
+	scanUpdateInterval     = 150 * time.Millisecond
+	duplicateMinSize       = 1 << 20
+	duplicatePartialChunk  = 64 << 10
+	maxDuplicateSets       = 200
+	maxDuplicateHashers    = 8
 
 	// Worker pool limits.
 	minWorkers         = 16
@@ -181,6 +190,14 @@ var skipSystemDirs = map[string]bool{
 	".DocumentRevisions-V100": true,
 	".TemporaryItems":         true,
 	".MobileBackups":          true,
+
+	// Linux pseudo filesystems and mount roots.
+	"proc":       true,
+	"sys":        true,
+	"run":        true,
+	"mnt":        true,
+	"media":      true,
+	"lost+found": true,
 }
 
 var defaultSkipDirs = map[string]bool{
@@ -1,10 +1,7 @@
 package main
 
 import (
-	"context"
-	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -77,7 +74,7 @@ func (e *multiDeleteError) Error() string {
 	return strings.Join(e.errors[:min(3, len(e.errors))], "; ")
 }
 
-// trashPathWithProgress moves a path to Trash using Finder.
+// trashPathWithProgress moves a path to the platform Trash.
 // This allows users to recover accidentally deleted files.
 func trashPathWithProgress(root string, counter *int64) (int64, error) {
 	// Verify path exists (use Lstat to handle broken symlinks).
@@ -108,39 +105,10 @@ func trashPathWithProgress(root string, counter *int64) (int64, error) {
 		}
 	}
 
-	// Move to Trash using Finder AppleScript.
+	// Move to Trash using the native mechanism (Finder on macOS, FreeDesktop Trash on Linux).
 	if err := moveToTrash(root); err != nil {
 		return 0, err
 	}
 
 	return count, nil
 }
-
-// moveToTrash uses macOS Finder to move a file/directory to Trash.
-// This is the safest method as it uses the system's native trash mechanism.
-func moveToTrash(path string) error {
-	absPath, err := filepath.Abs(path)
-	if err != nil {
-		return fmt.Errorf("failed to resolve path: %w", err)
-	}
-
-	// Escape path for AppleScript (handle quotes and backslashes).
-	escapedPath := strings.ReplaceAll(absPath, "\\", "\\\\")
-	escapedPath = strings.ReplaceAll(escapedPath, "\"", "\\\"")
-
-	script := fmt.Sprintf(`tell application "Finder" to delete POSIX file "%s"`, escapedPath)
-
-	ctx, cancel := context.WithTimeout(context.Background(), trashTimeout)
-	defer cancel()
-
-	cmd := exec.CommandContext(ctx, "osascript", "-e", script)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		if ctx.Err() == context.DeadlineExceeded {
-			return fmt.Errorf("timeout moving to Trash")
-		}
-		return fmt.Errorf("failed to move to Trash: %s", strings.TrimSpace(string(output)))
-	}
-
-	return nil
-}
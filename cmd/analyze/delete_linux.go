@@ -0,0 +1,200 @@
+//go:build linux
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// maxTrashNameAttempts bounds the search for a free name in the Trash.
+const maxTrashNameAttempts = 1000
+
+// moveToTrash follows the FreeDesktop Trash specification so deleted items
+// can be restored from the file manager.
+// Files on the home volume go to $XDG_DATA_HOME/Trash; files on other mounts
+// go to that mount's $topdir/.Trash/$uid or $topdir/.Trash-$uid.
+func moveToTrash(path string) error {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	info, err := os.Lstat(absPath)
+	if err != nil {
+		return err
+	}
+	dev, ok := deviceOf(info)
+	if !ok {
+		return fmt.Errorf("failed to move to Trash: cannot determine device of %s", absPath)
+	}
+
+	trashDir, infoPath, err := trashLocationFor(absPath, dev)
+	if err != nil {
+		return fmt.Errorf("failed to move to Trash: %w", err)
+	}
+
+	return moveIntoTrashDir(absPath, trashDir, infoPath)
+}
+
+// trashLocationFor picks the Trash directory for a path and the value written
+// to the Path key of its .trashinfo file.
+func trashLocationFor(absPath string, dev uint64) (string, string, error) {
+	homeTrash, err := homeTrashDir()
+	if err != nil {
+		return "", "", err
+	}
+	if err := ensureTrashDirs(homeTrash); err != nil {
+		return "", "", err
+	}
+	if homeInfo, err := os.Stat(homeTrash); err == nil {
+		if homeDev, ok := deviceOf(homeInfo); ok && homeDev == dev {
+			return homeTrash, absPath, nil
+		}
+	}
+
+	topdir := mountTopDir(absPath, dev)
+	if topdir == absPath {
+		// Its Trash would be inside it, and a mount point cannot be renamed.
+		return "", "", fmt.Errorf("%s is a mount point; unmount it instead", absPath)
+	}
+	trashDir, err := volumeTrashDir(topdir)
+	if err != nil {
+		return "", "", err
+	}
+	rel, err := filepath.Rel(topdir, absPath)
+	if err != nil {
+		return "", "", err
+	}
+	return trashDir, rel, nil
+}
+
+// homeTrashDir returns $XDG_DATA_HOME/Trash, defaulting to ~/.local/share/Trash.
+func homeTrashDir() (string, error) {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" || !filepath.IsAbs(dataHome) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dataHome, "Trash"), nil
+}
+
+// volumeTrashDir returns the Trash for a mount, preferring a shared sticky
+// $topdir/.Trash and falling back to a per-user $topdir/.Trash-$uid.
+func volumeTrashDir(topdir string) (string, error) {
+	uid := strconv.Itoa(os.Getuid())
+
+	shared := filepath.Join(topdir, ".Trash")
+	if info, err := os.Lstat(shared); err == nil && info.IsDir() && info.Mode()&os.ModeSticky != 0 {
+		userTrash := filepath.Join(shared, uid)
+		if err := ensureTrashDirs(userTrash); err == nil {
+			return userTrash, nil
+		}
+	}
+
+	userTrash := filepath.Join(topdir, ".Trash-"+uid)
+	if info, err := os.Lstat(userTrash); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		return "", fmt.Errorf("refusing symlinked Trash at %s", userTrash)
+	}
+	if err := ensureTrashDirs(userTrash); err != nil {
+		return "", fmt.Errorf("cannot create Trash on volume %s: %w", topdir, err)
+	}
+	return userTrash, nil
+}
+
+func ensureTrashDirs(trashDir string) error {
+	for _, sub := range []string{"files", "info"} {
+		if err := os.MkdirAll(filepath.Join(trashDir, sub), 0o700); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mountTopDir walks up from path until the parent is on a different device.
+// It starts at path itself, so a mount point is its own topdir.
+func mountTopDir(path string, dev uint64) string {
+	dir := path
+	for {
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dir
+		}
+		info, err := os.Lstat(parent)
+		if err != nil {
+			return dir
+		}
+		if parentDev, ok := deviceOf(info); !ok || parentDev != dev {
+			return dir
+		}
+		dir = parent
+	}
+}
+
+// moveIntoTrashDir reserves a unique name by creating the .trashinfo file
+// first, then renames the item into files/. The info file is removed again
+// if the rename fails so the Trash never lists a missing item.
+func moveIntoTrashDir(absPath, trashDir, infoPath string) error {
+	base := filepath.Base(absPath)
+	deletionDate := time.Now().Format("2006-01-02T15:04:05")
+	content := fmt.Sprintf("[Trash Info]\nPath=%s\nDeletionDate=%s\n", escapeTrashPath(infoPath), deletionDate)
+
+	for attempt := 1; attempt <= maxTrashNameAttempts; attempt++ {
+		name := base
+		if attempt > 1 {
+			name = fmt.Sprintf("%s.%d", base, attempt)
+		}
+
+		infoFile := filepath.Join(trashDir, "info", name+".trashinfo")
+		f, err := os.OpenFile(infoFile, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if err != nil {
+			if errors.Is(err, os.ErrExist) {
+				continue
+			}
+			return fmt.Errorf("failed to write trash info: %w", err)
+		}
+		_, writeErr := f.WriteString(content)
+		closeErr := f.Close()
+		if writeErr != nil || closeErr != nil {
+			_ = os.Remove(infoFile)
+			return fmt.Errorf("failed to write trash info: %w", errors.Join(writeErr, closeErr))
+		}
+
+		target := filepath.Join(trashDir, "files", name)
+		if _, err := os.Lstat(target); err == nil {
+			// Stale entry without info file; keep it and try the next name.
+			_ = os.Remove(infoFile)
+			continue
+		}
+		if err := os.Rename(absPath, target); err != nil {
+			_ = os.Remove(infoFile)
+			return fmt.Errorf("failed to move to Trash: %w", err)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("failed to move to Trash: no free name for %s", base)
+}
+
+// escapeTrashPath percent-encodes a path for the .trashinfo Path key,
+// keeping separators readable as the spec requires.
+func escapeTrashPath(path string) string {
+	return (&url.URL{Path: path}).EscapedPath()
+}
+
+func deviceOf(info os.FileInfo) (uint64, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+	return uint64(stat.Dev), true //nolint:unconvert // Dev width differs across arches
+}
@@ -0,0 +1,172 @@
+//go:build linux
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestMoveToTrashWritesTrashInfo(t *testing.T) {
+	base := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", filepath.Join(base, "data"))
+
+	target := filepath.Join(base, "old report.txt")
+	if err := os.WriteFile(target, []byte("content"), 0o644); err != nil {
+		t.Fatalf("write target: %v", err)
+	}
+
+	if err := moveToTrash(target); err != nil {
+		t.Fatalf("moveToTrash returned error: %v", err)
+	}
+
+	trashDir := filepath.Join(base, "data", "Trash")
+	if _, err := os.Stat(target); !os.IsNotExist(err) {
+		t.Fatalf("expected target to be moved, stat err=%v", err)
+	}
+	if _, err := os.Stat(filepath.Join(trashDir, "files", "old report.txt")); err != nil {
+		t.Fatalf("expected file in Trash: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(trashDir, "info", "old report.txt.trashinfo"))
+	if err != nil {
+		t.Fatalf("read trashinfo: %v", err)
+	}
+	info := string(data)
+	if !strings.HasPrefix(info, "[Trash Info]\n") {
+		t.Fatalf("trashinfo missing header: %q", info)
+	}
+	wantPath := "Path=" + escapeTrashPath(target) + "\n"
+	if !strings.Contains(info, wantPath) {
+		t.Fatalf("trashinfo missing %q: %q", wantPath, info)
+	}
+	if !strings.Contains(info, "%20") {
+		t.Fatalf("expected space to be percent-encoded: %q", info)
+	}
+	if !strings.Contains(info, "DeletionDate=") {
+		t.Fatalf("trashinfo missing DeletionDate: %q", info)
+	}
+}
+
+func TestMoveToTrashNameCollision(t *testing.T) {
+	base := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", filepath.Join(base, "data"))
+
+	for _, dir := range []string{"a", "b"} {
+		target := filepath.Join(base, dir, "dup.bin")
+		writeFileWithSize(t, target, 16)
+		if err := moveToTrash(target); err != nil {
+			t.Fatalf("moveToTrash(%s) returned error: %v", target, err)
+		}
+	}
+
+	trashDir := filepath.Join(base, "data", "Trash")
+	for _, name := range []string{"dup.bin", "dup.bin.2"} {
+		if _, err := os.Stat(filepath.Join(trashDir, "files", name)); err != nil {
+			t.Fatalf("expected %s in Trash files: %v", name, err)
+		}
+		if _, err := os.Stat(filepath.Join(trashDir, "info", name+".trashinfo")); err != nil {
+			t.Fatalf("expected %s.trashinfo in Trash info: %v", name, err)
+		}
+	}
+}
+
+func TestVolumeTrashDir(t *testing.T) {
+	uid := strconv.Itoa(os.Getuid())
+
+	t.Run("per-user fallback", func(t *testing.T) {
+		topdir := t.TempDir()
+		got, err := volumeTrashDir(topdir)
+		if err != nil {
+			t.Fatalf("volumeTrashDir error: %v", err)
+		}
+		want := filepath.Join(topdir, ".Trash-"+uid)
+		if got != want {
+			t.Fatalf("volumeTrashDir = %q, want %q", got, want)
+		}
+		if info, err := os.Stat(filepath.Join(want, "files")); err != nil || info.Mode().Perm() != 0o700 {
+			t.Fatalf("expected files dir with 0700, info=%v err=%v", info, err)
+		}
+	})
+
+	t.Run("shared sticky trash", func(t *testing.T) {
+		topdir := t.TempDir()
+		shared := filepath.Join(topdir, ".Trash")
+		if err := os.Mkdir(shared, 0o777); err != nil {
+			t.Fatalf("mkdir shared: %v", err)
+		}
+		if err := os.Chmod(shared, 0o777|os.ModeSticky); err != nil {
+			t.Fatalf("chmod shared: %v", err)
+		}
+		got, err := volumeTrashDir(topdir)
+		if err != nil {
+			t.Fatalf("volumeTrashDir error: %v", err)
+		}
+		if want := filepath.Join(shared, uid); got != want {
+			t.Fatalf("volumeTrashDir = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("shared trash without sticky bit is ignored", func(t *testing.T) {
+		topdir := t.TempDir()
+		if err := os.Mkdir(filepath.Join(topdir, ".Trash"), 0o755); err != nil {
+			t.Fatalf("mkdir shared: %v", err)
+		}
+		got, err := volumeTrashDir(topdir)
+		if err != nil {
+			t.Fatalf("volumeTrashDir error: %v", err)
+		}
+		if want := filepath.Join(topdir, ".Trash-"+uid); got != want {
+			t.Fatalf("volumeTrashDir = %q, want %q", got, want)
+		}
+	})
+}
+
+func TestMountTopDirSameDevice(t *testing.T) {
+	base := t.TempDir()
+	target := filepath.Join(base, "nested", "file")
+	writeFileWithSize(t, target, 1)
+
+	info, err := os.Lstat(target)
+	if err != nil {
+		t.Fatalf("lstat: %v", err)
+	}
+	dev, ok := deviceOf(info)
+	if !ok {
+		t.Fatal("deviceOf returned false")
+	}
+
+	top := mountTopDir(target, dev)
+	if !strings.HasPrefix(target, top) {
+		t.Fatalf("mountTopDir(%q) = %q, expected an ancestor", target, top)
+	}
+}
+
+func TestMountTopDirOfMountPoint(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_DATA_HOME", "")
+
+	// /proc is mounted on every Linux system and needs no privileges.
+	procInfo, err := os.Lstat("/proc")
+	if err != nil {
+		t.Skipf("no /proc: %v", err)
+	}
+	rootInfo, err := os.Lstat("/")
+	if err != nil {
+		t.Fatalf("lstat /: %v", err)
+	}
+	dev, _ := deviceOf(procInfo)
+	if rootDev, _ := deviceOf(rootInfo); rootDev == dev {
+		t.Skip("/proc is not a separate mount here")
+	}
+
+	if top := mountTopDir("/proc", dev); top != "/proc" {
+		t.Fatalf("mountTopDir(/proc) = %q, want the mount point itself", top)
+	}
+	if _, _, err := trashLocationFor("/proc", dev); err == nil || !strings.Contains(err.Error(), "mount point") {
+		t.Fatalf("trashing a mount point should be refused, got %v", err)
+	}
+}
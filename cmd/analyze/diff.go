@@ -0,0 +1,439 @@
+package main
+
+import (
+	"context"
+	"encoding/gob"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	diffAdded   = "added"
+	diffRemoved = "removed"
+	diffGrown   = "grown"
+	diffShrunk  = "shrunk"
+	diffUnknown = "unknown" // Missing from one side's list, which was cut at the top N
+)
+
+// scanSnapshot is one side of a diff: a stored cache entry or a fresh scan.
+// Scans keep only the largest maxEntries entries and maxLargeFiles files,
+// so a list that reached its cap says nothing about what fell below it.
+type scanSnapshot struct {
+	Label            string
+	Path             string
+	ScanTime         time.Time
+	Entries          []dirEntry
+	LargeFiles       []fileEntry
+	TotalSize        int64
+	EntriesTruncated bool
+	FilesTruncated   bool
+}
+
+// jsonDiff is the schema printed by diff --json.
+type jsonDiff struct {
+	Old        jsonDiffSide `json:"old"`
+	New        jsonDiffSide `json:"new"`
+	TotalDelta int64        `json:"total_delta"`
+	Entries    []diffEntry  `json:"entries"`
+	LargeFiles []diffEntry  `json:"large_files"`
+}
+
+type jsonDiffSide struct {
+	Source           string    `json:"source"`
+	Path             string    `json:"path,omitempty"`
+	ScannedAt        time.Time `json:"scanned_at"`
+	TotalSize        int64     `json:"total_size"`
+	EntriesTruncated bool      `json:"entries_truncated"`
+	FilesTruncated   bool      `json:"large_files_truncated"`
+}
+
+type diffEntry struct {
+	Status  string `json:"status"`
+	Name    string `json:"name"`
+	Path    string `json:"path"`
+	OldSize int64  `json:"old_size"`
+	NewSize int64  `json:"new_size"`
+	Delta   int64  `json:"delta"`
+}
+
+// runDiffCommand implements `analyze-go diff`. A side is a .cache
+// snapshot file, a directory whose cached snapshot is used, or with --from
+// and --to a scan from the directory's history picked by index or date.
+// Without a second side, the first is compared against a fresh scan.
+func runDiffCommand(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	jsonOutput := fs.Bool("json", false, "print the diff as JSON")
+	from := fs.String("from", "", "baseline scan from history: index (1 = newest) or date (YYYY-MM-DD)")
+	to := fs.String("to", "", "comparison scan from history instead of a fresh scan")
+	list := fs.Bool("list", false, "list the recorded scans of <dir> and exit")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: analyze-go diff [--json] [--from REF] [--to REF] <dir>")
+		fmt.Fprintln(stderr, "       analyze-go diff [--json] <snapshotA|dir> <snapshotB|dir>")
+		fmt.Fprintln(stderr, "       analyze-go diff --list <dir>")
+		fmt.Fprintln(stderr, "With one side, it is compared against a fresh scan. REF is a history")
+		fmt.Fprintln(stderr, "index (1 = newest) or a date (YYYY-MM-DD) picking the last scan that day.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	historyRefs := *from != "" || *to != ""
+	if fs.NArg() < 1 || fs.NArg() > 2 || (fs.NArg() == 2 && (historyRefs || *list)) {
+		fs.Usage()
+		return 2
+	}
+
+	if *list {
+		if err := printScanHistory(stdout, fs.Arg(0)); err != nil {
+			fmt.Fprintf(stderr, "cannot list %s: %v\n", fs.Arg(0), err)
+			return 1
+		}
+		return 0
+	}
+
+	var older *scanSnapshot
+	var err error
+	if *from != "" {
+		older, err = loadHistorySnapshot(fs.Arg(0), *from)
+	} else {
+		older, err = loadSnapshot(fs.Arg(0))
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "cannot load %s: %v\n", fs.Arg(0), err)
+		return 1
+	}
+
+	var newer *scanSnapshot
+	switch {
+	case fs.NArg() == 2:
+		newer, err = loadSnapshot(fs.Arg(1))
+	case *to != "":
+		newer, err = loadHistorySnapshot(fs.Arg(0), *to)
+	default:
+		newer, err = rescanSnapshot(older.Path)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "cannot load comparison: %v\n", err)
+		return 1
+	}
+
+	report := diffSnapshots(older, newer)
+	if *jsonOutput {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			fmt.Fprintf(stderr, "analyzer error: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+	printDiffTable(stdout, report)
+	return 0
+}
+
+// loadSnapshot reads a gob cache file, or the cached snapshot of a directory,
+// falling back to the directory's newest recorded scan.
+// Expiry is ignored: old snapshots are exactly what a diff wants.
+func loadSnapshot(arg string) (*scanSnapshot, error) {
+	abs, err := filepath.Abs(arg)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheFile := abs
+	if info.IsDir() {
+		cacheFile, err = getCachePath(abs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	entry, err := readCacheFile(cacheFile)
+	if err != nil {
+		if info.IsDir() && os.IsNotExist(err) {
+			return loadHistorySnapshot(arg, "1")
+		}
+		return nil, err
+	}
+	if info.IsDir() && entry.Path == "" {
+		entry.Path = abs
+	}
+
+	return &scanSnapshot{
+		Label:            arg,
+		Path:             entry.Path,
+		ScanTime:         entry.ScanTime,
+		Entries:          entry.Entries,
+		LargeFiles:       entry.LargeFiles,
+		TotalSize:        entry.TotalSize,
+		EntriesTruncated: len(entry.Entries) >= maxEntries,
+		FilesTruncated:   len(entry.LargeFiles) >= maxLargeFiles,
+	}, nil
+}
+
+// loadHistorySnapshot picks one recorded scan of the directory arg. History
+// keeps entry sizes only, so its large files count as unknown.
+func loadHistorySnapshot(arg, ref string) (*scanSnapshot, error) {
+	abs, err := filepath.Abs(arg)
+	if err != nil {
+		return nil, err
+	}
+	records, err := loadScanHistory(abs)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("no recorded scans for %s, run analyze on it first", abs)
+	}
+	record, err := findScanRecord(records, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]dirEntry, 0, len(record.Entries))
+	for _, entry := range record.Entries {
+		entries = append(entries, dirEntry{Name: filepath.Base(entry.Path), Path: entry.Path, Size: entry.Size})
+	}
+	return &scanSnapshot{
+		Label:            fmt.Sprintf("%s@%s", arg, ref),
+		Path:             abs,
+		ScanTime:         record.ScannedAt,
+		Entries:          entries,
+		TotalSize:        record.TotalSize,
+		EntriesTruncated: len(entries) >= maxEntries,
+		FilesTruncated:   true,
+	}, nil
+}
+
+// findScanRecord resolves ref against records, oldest first. An index counts
+// back from the newest scan; a date picks the newest scan made by the end of
+// that local day.
+func findScanRecord(records []scanRecord, ref string) (*scanRecord, error) {
+	if index, err := strconv.Atoi(ref); err == nil {
+		if index < 1 || index > len(records) {
+			return nil, fmt.Errorf("scan %d not recorded, %d scans available", index, len(records))
+		}
+		return &records[len(records)-index], nil
+	}
+
+	day, err := time.ParseInLocation("2006-01-02", ref, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scan %q: want an index or YYYY-MM-DD", ref)
+	}
+	end := day.AddDate(0, 0, 1)
+	for i := len(records) - 1; i >= 0; i-- {
+		if records[i].ScannedAt.Before(end) {
+			return &records[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no scan recorded on or before %s", ref)
+}
+
+// printScanHistory lists the recorded scans of dir, newest first, with the
+// index --from and --to accept.
+func printScanHistory(w io.Writer, dir string) error {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	records, err := loadScanHistory(abs)
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return fmt.Errorf("no recorded scans for %s", abs)
+	}
+	for i := len(records) - 1; i >= 0; i-- {
+		record := records[i]
+		fmt.Fprintf(w, "%4d  %s  %10s  %s files\n", len(records)-i,
+			record.ScannedAt.Local().Format("2006-01-02 15:04"),
+			humanizeBytes(record.TotalSize), formatNumber(record.TotalFiles))
+	}
+	return nil
+}
+
+func readCacheFile(cacheFile string) (*cacheEntry, error) {
+	file, err := os.Open(cacheFile)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close() //nolint:errcheck
+
+	var entry cacheEntry
+	if err := gob.NewDecoder(file).Decode(&entry); err != nil {
+		return nil, fmt.Errorf("not a scan snapshot: %w", err)
+	}
+	return &entry, nil
+}
+
+// rescanSnapshot scans path now without touching the disk cache, so the
+// stored snapshot stays available as a baseline.
+func rescanSnapshot(path string) (*scanSnapshot, error) {
+	if path == "" {
+		return nil, fmt.Errorf("snapshot does not record its directory; pass a second snapshot")
+	}
+
+	var filesScanned, dirsScanned, bytesScanned int64
+	currentPath := &atomic.Value{}
+	currentPath.Store("")
+
+	result, err := scanPathConcurrent(context.Background(), path, nil, &filesScanned, &dirsScanned, &bytesScanned, currentPath)
+	if err != nil {
+		return nil, err
+	}
+	return &scanSnapshot{
+		Label:            "rescan",
+		Path:             path,
+		ScanTime:         time.Now(),
+		Entries:          result.Entries,
+		LargeFiles:       result.LargeFiles,
+		TotalSize:        result.TotalSize,
+		EntriesTruncated: len(result.Entries) >= maxEntries,
+		FilesTruncated:   len(result.LargeFiles) >= maxLargeFiles,
+	}, nil
+}
+
+// diffSnapshots matches entries by name so snapshots of a moved or copied
+// directory still line up, and large files by path. Unchanged items are
+// omitted and the rest are ordered by the size of the change. An item only
+// one side lists is added or removed when the other side's list is
+// complete, and unknown when that list was cut at the top N.
+func diffSnapshots(older, newer *scanSnapshot) jsonDiff {
+	report := jsonDiff{
+		Old: jsonDiffSide{Source: older.Label, Path: older.Path, ScannedAt: older.ScanTime, TotalSize: older.TotalSize,
+			EntriesTruncated: older.EntriesTruncated, FilesTruncated: older.FilesTruncated},
+		New: jsonDiffSide{Source: newer.Label, Path: newer.Path, ScannedAt: newer.ScanTime, TotalSize: newer.TotalSize,
+			EntriesTruncated: newer.EntriesTruncated, FilesTruncated: newer.FilesTruncated},
+		TotalDelta: newer.TotalSize - older.TotalSize,
+		Entries:    []diffEntry{},
+		LargeFiles: []diffEntry{},
+	}
+
+	oldEntries := make(map[string]dirEntry, len(older.Entries))
+	for _, entry := range older.Entries {
+		oldEntries[entry.Name] = entry
+	}
+	for _, entry := range newer.Entries {
+		previous, ok := oldEntries[entry.Name]
+		delete(oldEntries, entry.Name)
+		if item, changed := compareSizes(entry.Name, entry.Path, previous.Size, entry.Size, ok, true, older.EntriesTruncated); changed {
+			report.Entries = append(report.Entries, item)
+		}
+	}
+	for _, entry := range oldEntries {
+		item, _ := compareSizes(entry.Name, entry.Path, entry.Size, 0, true, false, newer.EntriesTruncated)
+		report.Entries = append(report.Entries, item)
+	}
+
+	oldFiles := make(map[string]fileEntry, len(older.LargeFiles))
+	for _, file := range older.LargeFiles {
+		oldFiles[file.Path] = file
+	}
+	for _, file := range newer.LargeFiles {
+		previous, ok := oldFiles[file.Path]
+		delete(oldFiles, file.Path)
+		if item, changed := compareSizes(file.Name, file.Path, previous.Size, file.Size, ok, true, older.FilesTruncated); changed {
+			report.LargeFiles = append(report.LargeFiles, item)
+		}
+	}
+	for _, file := range oldFiles {
+		item, _ := compareSizes(file.Name, file.Path, file.Size, 0, true, false, newer.FilesTruncated)
+		report.LargeFiles = append(report.LargeFiles, item)
+	}
+
+	sortDiffEntries(report.Entries)
+	sortDiffEntries(report.LargeFiles)
+	return report
+}
+
+// compareSizes classifies one item. When the side missing the item had its
+// list truncated, the item may simply have been below the cut there, so its
+// status is unknown and it carries no delta.
+func compareSizes(name, path string, oldSize, newSize int64, inOld, inNew, otherTruncated bool) (diffEntry, bool) {
+	item := diffEntry{Name: name, Path: path, OldSize: oldSize, NewSize: newSize, Delta: newSize - oldSize}
+	switch {
+	case (!inOld || !inNew) && otherTruncated:
+		item.Status = diffUnknown
+		item.Delta = 0
+	case !inOld:
+		item.Status = diffAdded
+	case !inNew:
+		item.Status = diffRemoved
+	case newSize > oldSize:
+		item.Status = diffGrown
+	case newSize < oldSize:
+		item.Status = diffShrunk
+	default:
+		return item, false
+	}
+	return item, true
+}
+
+func sortDiffEntries(entries []diffEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := abs64(entries[i].Delta), abs64(entries[j].Delta)
+		if a != b {
+			return a > b
+		}
+		return entries[i].Name < entries[j].Name
+	})
+}
+
+func abs64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func printDiffTable(w io.Writer, report jsonDiff) {
+	fmt.Fprintf(w, "Old: %s  %s  %s\n", report.Old.Source, report.Old.ScannedAt.Local().Format("2006-01-02 15:04"), humanizeBytes(report.Old.TotalSize))
+	fmt.Fprintf(w, "New: %s  %s  %s\n", report.New.Source, report.New.ScannedAt.Local().Format("2006-01-02 15:04"), humanizeBytes(report.New.TotalSize))
+	fmt.Fprintf(w, "Total: %s\n", formatSizeDelta(report.TotalDelta))
+
+	printDiffSection(w, "Entries", report.Entries)
+	if len(report.LargeFiles) > 0 {
+		printDiffSection(w, "Large files", report.LargeFiles)
+	}
+}
+
+func printDiffSection(w io.Writer, title string, entries []diffEntry) {
+	fmt.Fprintf(w, "\n%s\n", title)
+	if len(entries) == 0 {
+		fmt.Fprintln(w, "  no changes")
+		return
+	}
+	fmt.Fprintf(w, "  %-8s %12s %12s %12s  %s\n", "STATUS", "DELTA", "OLD", "NEW", "NAME")
+	for _, entry := range entries {
+		delta := formatSizeDelta(entry.Delta)
+		oldSize, newSize := humanizeBytes(entry.OldSize), humanizeBytes(entry.NewSize)
+		switch entry.Status {
+		case diffAdded:
+			oldSize = "-"
+		case diffRemoved:
+			newSize = "-"
+		case diffUnknown:
+			// Known on one side only; the other side's list stopped above it.
+			delta = "?"
+			if entry.OldSize == 0 {
+				oldSize = "below top"
+			} else {
+				newSize = "below top"
+			}
+		}
+		fmt.Fprintf(w, "  %-8s %12s %12s %12s  %s\n", entry.Status, delta, oldSize, newSize, entry.Name)
+	}
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiffSnapshotsClassifiesChanges(t *testing.T) {
+	older := &scanSnapshot{
+		Label:     "a",
+		TotalSize: 300,
+		Entries: []dirEntry{
+			{Name: "grows", Path: "/x/grows", Size: 100},
+			{Name: "shrinks", Path: "/x/shrinks", Size: 150},
+			{Name: "same", Path: "/x/same", Size: 40},
+			{Name: "gone", Path: "/x/gone", Size: 10},
+		},
+		LargeFiles: []fileEntry{{Name: "iso", Path: "/x/iso", Size: 50}},
+	}
+	newer := &scanSnapshot{
+		Label:     "b",
+		TotalSize: 5000,
+		Entries: []dirEntry{
+			{Name: "grows", Path: "/x/grows", Size: 4000},
+			{Name: "shrinks", Path: "/x/shrinks", Size: 50},
+			{Name: "same", Path: "/x/same", Size: 40},
+			{Name: "fresh", Path: "/x/fresh", Size: 900},
+		},
+	}
+
+	report := diffSnapshots(older, newer)
+	if report.TotalDelta != 4700 {
+		t.Fatalf("expected total delta 4700, got %d", report.TotalDelta)
+	}
+
+	want := []struct {
+		name   string
+		status string
+		delta  int64
+	}{
+		{"grows", diffGrown, 3900},
+		{"fresh", diffAdded, 900},
+		{"shrinks", diffShrunk, -100},
+		{"gone", diffRemoved, -10},
+	}
+	if len(report.Entries) != len(want) {
+		t.Fatalf("expected %d changes, got %+v", len(want), report.Entries)
+	}
+	for i, w := range want {
+		got := report.Entries[i]
+		if got.Name != w.name || got.Status != w.status || got.Delta != w.delta {
+			t.Errorf("entry %d = %+v, want %s %s %d", i, got, w.name, w.status, w.delta)
+		}
+	}
+	if len(report.LargeFiles) != 1 || report.LargeFiles[0].Status != diffRemoved {
+		t.Fatalf("expected removed large file, got %+v", report.LargeFiles)
+	}
+}
+
+func TestDiffSnapshotsTopNBoundary(t *testing.T) {
+	// Both sides list the largest maxEntries children. "edge" sits just
+	// below the cut in the old scan and just above it in the new one, and
+	// pushes "tail" out; both barely changed.
+	var oldList, newList []dirEntry
+	for i := range maxEntries - 1 {
+		name := fmt.Sprintf("big%02d", i)
+		oldList = append(oldList, dirEntry{Name: name, Path: "/x/" + name, Size: int64(1000 - i)})
+		newList = append(newList, dirEntry{Name: name, Path: "/x/" + name, Size: int64(1000 - i)})
+	}
+	oldList = append(oldList, dirEntry{Name: "tail", Path: "/x/tail", Size: 500})
+	newList = append(newList, dirEntry{Name: "edge", Path: "/x/edge", Size: 501})
+
+	older := &scanSnapshot{Label: "a", Entries: oldList, EntriesTruncated: true}
+	newer := &scanSnapshot{Label: "b", Entries: newList, EntriesTruncated: true}
+	report := diffSnapshots(older, newer)
+	if len(report.Entries) != 2 {
+		t.Fatalf("expected two unknown entries, got %+v", report.Entries)
+	}
+	for _, entry := range report.Entries {
+		if entry.Status != diffUnknown || entry.Delta != 0 {
+			t.Errorf("entry crossing the top-%d boundary should be unknown without a delta, got %+v", maxEntries, entry)
+		}
+	}
+	if !report.Old.EntriesTruncated || !report.New.EntriesTruncated {
+		t.Errorf("truncation should be reported per side: %+v %+v", report.Old, report.New)
+	}
+
+	var out bytes.Buffer
+	printDiffTable(&out, report)
+	if !strings.Contains(out.String(), "below top") || strings.Contains(out.String(), diffAdded) {
+		t.Errorf("table should not call boundary entries added:\n%s", out.String())
+	}
+
+	// A complete old list still proves the entry is new.
+	older.Entries, older.EntriesTruncated = oldList[:3], false
+	newer.Entries = append(newer.Entries[:3:3], dirEntry{Name: "edge", Path: "/x/edge", Size: 501})
+	report = diffSnapshots(older, newer)
+	if len(report.Entries) != 1 || report.Entries[0].Status != diffAdded || report.Entries[0].Delta != 501 {
+		t.Errorf("expected edge added against a complete list, got %+v", report.Entries)
+	}
+}
+
+func TestRunDiffCommandWithSnapshots(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	target := t.TempDir()
+
+	first := filepath.Join(t.TempDir(), "before.cache")
+	if err := saveCacheToDisk(target, scanResult{
+		Entries:   []dirEntry{{Name: "build", Path: filepath.Join(target, "build"), Size: 1 << 20}},
+		TotalSize: 1 << 20,
+	}); err != nil {
+		t.Fatalf("save first: %v", err)
+	}
+	cachePath, err := getCachePath(target)
+	if err != nil {
+		t.Fatalf("getCachePath: %v", err)
+	}
+	data, err := os.ReadFile(cachePath)
+	if err != nil {
+		t.Fatalf("read cache: %v", err)
+	}
+	if err := os.WriteFile(first, data, 0644); err != nil {
+		t.Fatalf("copy snapshot: %v", err)
+	}
+
+	if err := saveCacheToDisk(target, scanResult{
+		Entries:   []dirEntry{{Name: "build", Path: filepath.Join(target, "build"), Size: 21 << 20}},
+		TotalSize: 21 << 20,
+	}); err != nil {
+		t.Fatalf("save second: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := runDiffCommand([]string{"--json", first, target}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit %d: %s", code, stderr.String())
+	}
+	var report jsonDiff
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if report.Old.Path != target || report.TotalDelta != 20<<20 {
+		t.Fatalf("unexpected report header: %+v", report)
+	}
+	if len(report.Entries) != 1 || report.Entries[0].Status != diffGrown {
+		t.Fatalf("expected build to have grown, got %+v", report.Entries)
+	}
+
+	stdout.Reset()
+	if code := runDiffCommand([]string{first, target}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit %d: %s", code, stderr.String())
+	}
+	if out := stdout.String(); !strings.Contains(out, "grown") || !strings.Contains(out, "+20.0 MB") {
+		t.Fatalf("unexpected table output:\n%s", out)
+	}
+}
+
+func TestRunDiffCommandAgainstRescan(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	target := t.TempDir()
+	writeFileWithSize(t, filepath.Join(target, "new.bin"), 64<<10)
+
+	if err := saveCacheToDisk(target, scanResult{}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := runDiffCommand([]string{"--json", target}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit %d: %s", code, stderr.String())
+	}
+	var report jsonDiff
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(report.Entries) != 1 || report.Entries[0].Name != "new.bin" || report.Entries[0].Status != diffAdded {
+		t.Fatalf("expected new.bin added, got %+v", report.Entries)
+	}
+}
+
+func TestRunDiffCommandFromHistory(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	target := t.TempDir()
+	build := filepath.Join(target, "build")
+
+	days := []time.Time{
+		time.Date(2026, 3, 1, 9, 0, 0, 0, time.Local),
+		time.Date(2026, 3, 2, 9, 0, 0, 0, time.Local),
+		time.Date(2026, 3, 3, 9, 0, 0, 0, time.Local),
+	}
+	for i, day := range days {
+		size := int64(i+1) << 20
+		if err := appendScanHistory(target, scanResult{TotalSize: size, Entries: []dirEntry{{Path: build, Size: size}}}, day); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+
+	diff := func(args ...string) jsonDiff {
+		t.Helper()
+		var stdout, stderr bytes.Buffer
+		if code := runDiffCommand(append([]string{"--json"}, args...), &stdout, &stderr); code != 0 {
+			t.Fatalf("exit %d: %s", code, stderr.String())
+		}
+		var report jsonDiff
+		if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return report
+	}
+
+	report := diff("--from", "3", "--to", "1", target)
+	if !report.Old.ScannedAt.Equal(days[0]) || !report.New.ScannedAt.Equal(days[2]) || report.TotalDelta != 2<<20 {
+		t.Fatalf("index refs picked the wrong scans: %+v", report)
+	}
+	if len(report.Entries) != 1 || report.Entries[0].Name != "build" || report.Entries[0].Status != diffGrown {
+		t.Fatalf("expected build to have grown, got %+v", report.Entries)
+	}
+
+	report = diff("--from", "2026-03-02", "--to", "2026-03-31", target)
+	if !report.Old.ScannedAt.Equal(days[1]) || !report.New.ScannedAt.Equal(days[2]) {
+		t.Fatalf("date refs picked the wrong scans: %+v", report)
+	}
+
+	// Without a cached snapshot, the directory itself means its newest scan.
+	report = diff(target, target)
+	if !report.Old.ScannedAt.Equal(days[2]) || report.TotalDelta != 0 {
+		t.Fatalf("expected the newest recorded scan, got %+v", report)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := runDiffCommand([]string{"--from", "4", target}, &stdout, &stderr); code != 1 || !strings.Contains(stderr.String(), "3 scans available") {
+		t.Fatalf("expected out-of-range index to fail, got %d: %s", code, stderr.String())
+	}
+	stderr.Reset()
+	if code := runDiffCommand([]string{"--from", "2026-02-28", target}, &stdout, &stderr); code != 1 || !strings.Contains(stderr.String(), "no scan recorded") {
+		t.Fatalf("expected a date before the history to fail, got %d: %s", code, stderr.String())
+	}
+
+	stdout.Reset()
+	if code := runDiffCommand([]string{"--list", target}, &stdout, &stderr); code != 0 {
+		t.Fatalf("list exit %d: %s", code, stderr.String())
+	}
+	if lines := strings.Split(strings.TrimSpace(stdout.String()), "\n"); len(lines) != 3 || !strings.Contains(lines[0], "2026-03-03") {
+		t.Fatalf("expected newest scan listed first:\n%s", stdout.String())
+	}
+}
+
+func TestRunDiffCommandUsage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := runDiffCommand(nil, &stdout, &stderr); code != 2 {
+		t.Fatalf("expected usage exit 2, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "usage: analyze-go diff") {
+		t.Fatalf("expected usage text, got %q", stderr.String())
+	}
+}
@@ -0,0 +1,308 @@
+package main
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/cespare/xxhash/v2"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// duplicateSet is a group of files with identical content.
+type duplicateSet struct {
+	Size  int64 // Size of one copy
+	Files []fileEntry
+}
+
+// wasted is what removing all but one copy would reclaim.
+func (s duplicateSet) wasted() int64 {
+	if len(s.Files) < 2 {
+		return 0
+	}
+	return s.Size * int64(len(s.Files)-1)
+}
+
+type duplicatesMsg struct {
+	path string
+	id   uint64
+	sets []duplicateSet
+	err  error
+}
+
+func findDuplicatesCmd(ctx context.Context, path string, id uint64, filesSeen, filesHashed *int64) tea.Cmd {
+	return func() tea.Msg {
+		sets, err := findDuplicates(ctx, path, filesSeen, filesHashed)
+		return duplicatesMsg{path: path, id: id, sets: sets, err: err}
+	}
+}
+
+// findDuplicates narrows candidates in three passes so most files are never
+// read: equal size, then a hash of the head and tail, then a full hash.
+// Hard links to one inode are not duplicates and are kept once.
+func findDuplicates(ctx context.Context, root string, filesSeen, filesHashed *int64) ([]duplicateSet, error) {
+	bySize, err := collectDuplicateCandidates(ctx, root, filesSeen)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups [][]fileEntry
+	for _, files := range bySize {
+		if len(files) > 1 {
+			groups = append(groups, files)
+		}
+	}
+
+	groups = refineByHash(ctx, groups, partialFileHash, filesHashed)
+
+	// Small files were read completely by the partial pass.
+	var confirmed, needFull [][]fileEntry
+	for _, group := range groups {
+		if group[0].Size <= 2*duplicatePartialChunk {
+			confirmed = append(confirmed, group)
+		} else {
+			needFull = append(needFull, group)
+		}
+	}
+	confirmed = append(confirmed, refineByHash(ctx, needFull, fullFileHash, filesHashed)...)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	sets := make([]duplicateSet, 0, len(confirmed))
+	for _, group := range confirmed {
+		sort.Slice(group, func(i, j int) bool { return group[i].Path < group[j].Path })
+		sets = append(sets, duplicateSet{Size: group[0].Size, Files: group})
+	}
+	sort.Slice(sets, func(i, j int) bool {
+		if sets[i].wasted() != sets[j].wasted() {
+			return sets[i].wasted() > sets[j].wasted()
+		}
+		return sets[i].Files[0].Path < sets[j].Files[0].Path
+	})
+	if len(sets) > maxDuplicateSets {
+		sets = sets[:maxDuplicateSets]
+	}
+	return sets, nil
+}
+
+// collectDuplicateCandidates groups regular files of at least
+// duplicateMinSize by size. Folded dirs are skipped: their contents are
+// managed by package tools and deleting single files there breaks them.
+func collectDuplicateCandidates(ctx context.Context, root string, filesSeen *int64) (map[int64][]fileEntry, error) {
+	bySize := make(map[int64][]fileEntry)
+	seenInodes := make(map[inodeKey]struct{})
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil {
+			if d != nil && d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if path == root {
+				return nil
+			}
+			if (filepath.Dir(path) == "/" && skipSystemDirs[d.Name()]) || shouldFoldDirWithPath(d.Name(), path) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		addCounter(filesSeen, 1)
+		info, err := d.Info()
+		if err != nil || info.Size() < duplicateMinSize {
+			return nil
+		}
+		if key, nlink, ok := fileIdentity(info); ok && nlink > 1 {
+			if _, dup := seenInodes[key]; dup {
+				return nil
+			}
+			seenInodes[key] = struct{}{}
+		}
+
+		bySize[info.Size()] = append(bySize[info.Size()], fileEntry{
+			Name: d.Name(),
+			Path: path,
+			Size: info.Size(),
+		})
+		return nil
+	})
+	return bySize, err
+}
+
+// refineByHash splits each group by hash, dropping files that became unique
+// or unreadable. Files are hashed in parallel across all groups.
+func refineByHash(ctx context.Context, groups [][]fileEntry, hash func(fileEntry) (uint64, error), filesHashed *int64) [][]fileEntry {
+	type job struct {
+		group, index int
+	}
+
+	hashes := make([][]uint64, len(groups))
+	failed := make([][]bool, len(groups))
+	jobs := make(chan job)
+	for i, group := range groups {
+		hashes[i] = make([]uint64, len(group))
+		failed[i] = make([]bool, len(group))
+	}
+
+	var wg sync.WaitGroup
+	for range min(runtime.NumCPU(), maxDuplicateHashers) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				if ctx.Err() != nil {
+					failed[j.group][j.index] = true
+					continue
+				}
+				sum, err := hash(groups[j.group][j.index])
+				hashes[j.group][j.index] = sum
+				failed[j.group][j.index] = err != nil
+				addCounter(filesHashed, 1)
+			}
+		}()
+	}
+	for i, group := range groups {
+		for k := range group {
+			jobs <- job{group: i, index: k}
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	var refined [][]fileEntry
+	for i, group := range groups {
+		byHash := make(map[uint64][]fileEntry)
+		var order []uint64
+		for k, file := range group {
+			if failed[i][k] {
+				continue
+			}
+			sum := hashes[i][k]
+			if _, ok := byHash[sum]; !ok {
+				order = append(order, sum)
+			}
+			byHash[sum] = append(byHash[sum], file)
+		}
+		for _, sum := range order {
+			if len(byHash[sum]) > 1 {
+				refined = append(refined, byHash[sum])
+			}
+		}
+	}
+	return refined
+}
+
+// partialFileHash hashes the first and last duplicatePartialChunk bytes,
+// which tells apart most same-size files that share a header.
+func partialFileHash(file fileEntry) (uint64, error) {
+	f, err := os.Open(file.Path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close() //nolint:errcheck
+
+	h := xxhash.New()
+	if file.Size <= 2*duplicatePartialChunk {
+		if _, err := io.Copy(h, f); err != nil {
+			return 0, err
+		}
+		return h.Sum64(), nil
+	}
+	if _, err := io.CopyN(h, f, duplicatePartialChunk); err != nil {
+		return 0, err
+	}
+	if _, err := io.Copy(h, io.NewSectionReader(f, file.Size-duplicatePartialChunk, duplicatePartialChunk)); err != nil {
+		return 0, err
+	}
+	return h.Sum64(), nil
+}
+
+func fullFileHash(file fileEntry) (uint64, error) {
+	f, err := os.Open(file.Path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close() //nolint:errcheck
+
+	h := xxhash.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return 0, err
+	}
+	return h.Sum64(), nil
+}
+
+// pruneDuplicateSets drops files that no longer exist, and sets that are
+// left with a single copy, after a delete.
+func pruneDuplicateSets(sets []duplicateSet) []duplicateSet {
+	pruned := sets[:0]
+	for _, set := range sets {
+		files := set.Files[:0]
+		for _, file := range set.Files {
+			if _, err := os.Lstat(file.Path); err == nil {
+				files = append(files, file)
+			}
+		}
+		set.Files = files
+		if len(set.Files) > 1 {
+			pruned = append(pruned, set)
+		}
+	}
+	return pruned
+}
+
+// duplicateRows flattens sets into the rows shown by the duplicates view.
+func duplicateRows(sets []duplicateSet) []duplicateRow {
+	var rows []duplicateRow
+	for i, set := range sets {
+		for k, file := range set.Files {
+			rows = append(rows, duplicateRow{set: i, first: k == 0, file: file})
+		}
+	}
+	return rows
+}
+
+type duplicateRow struct {
+	set   int
+	first bool // First copy in its set; the row carries the set summary
+	file  fileEntry
+}
+
+// selectsEveryCopy reports whether selected covers all copies of some set,
+// so trashing the selection would keep none of them.
+func selectsEveryCopy(sets []duplicateSet, selected map[string]bool) bool {
+	for _, set := range sets {
+		all := len(set.Files) > 0
+		for _, file := range set.Files {
+			if !selected[file.Path] {
+				all = false
+				break
+			}
+		}
+		if all {
+			return true
+		}
+	}
+	return false
+}
+
+func sumDuplicateWaste(sets []duplicateSet) int64 {
+	var total int64
+	for _, set := range sets {
+		total += set.wasted()
+	}
+	return total
+}
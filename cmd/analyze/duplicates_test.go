@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func writeFileWithContent(t *testing.T, path string, content []byte) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir %s: %v", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func patterned(size int, seed byte) []byte {
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = byte(i) ^ seed
+	}
+	return data
+}
+
+func TestFindDuplicates(t *testing.T) {
+	root := t.TempDir()
+	big := patterned(3<<20, 1)
+	writeFileWithContent(t, filepath.Join(root, "Downloads", "dataset.bin"), big)
+	writeFileWithContent(t, filepath.Join(root, "Backup", "dataset copy.bin"), big)
+	writeFileWithContent(t, filepath.Join(root, "Backup", "old", "dataset.bin"), big)
+
+	// Same size and same head/tail, differing only in the middle.
+	middle := patterned(3<<20, 1)
+	middle[len(middle)/2] ^= 0xff
+	writeFileWithContent(t, filepath.Join(root, "Other", "almost.bin"), middle)
+
+	// Small identical files fit the partial pass and are still confirmed.
+	small := patterned(1<<20, 7)
+	writeFileWithContent(t, filepath.Join(root, "a.dat"), small)
+	writeFileWithContent(t, filepath.Join(root, "b.dat"), small)
+
+	// Below the minimum size: ignored.
+	writeFileWithContent(t, filepath.Join(root, "tiny1"), []byte("same"))
+	writeFileWithContent(t, filepath.Join(root, "tiny2"), []byte("same"))
+
+	// Folded dirs are left to their package managers.
+	writeFileWithContent(t, filepath.Join(root, "node_modules", "dep", "dataset.bin"), big)
+
+	var seen, hashed int64
+	sets, err := findDuplicates(context.Background(), root, &seen, &hashed)
+	if err != nil {
+		t.Fatalf("findDuplicates: %v", err)
+	}
+	if len(sets) != 2 {
+		t.Fatalf("expected 2 duplicate sets, got %+v", sets)
+	}
+	if len(sets[0].Files) != 3 || sets[0].wasted() != 2*int64(len(big)) {
+		t.Fatalf("expected the 3-copy dataset set first, got %+v", sets[0])
+	}
+	if len(sets[1].Files) != 2 || sets[1].Size != int64(len(small)) {
+		t.Fatalf("expected the small pair second, got %+v", sets[1])
+	}
+	if seen < 8 || hashed == 0 {
+		t.Fatalf("expected progress counters to move, seen=%d hashed=%d", seen, hashed)
+	}
+}
+
+func TestFindDuplicatesIgnoresHardLinks(t *testing.T) {
+	root := t.TempDir()
+	original := filepath.Join(root, "original.bin")
+	writeFileWithContent(t, original, patterned(2<<20, 3))
+	if err := os.Link(original, filepath.Join(root, "link.bin")); err != nil {
+		t.Skipf("hard links unsupported: %v", err)
+	}
+
+	sets, err := findDuplicates(context.Background(), root, nil, nil)
+	if err != nil {
+		t.Fatalf("findDuplicates: %v", err)
+	}
+	if len(sets) != 0 {
+		t.Fatalf("hard links share storage and are not duplicates, got %+v", sets)
+	}
+}
+
+func TestFindDuplicatesCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := findDuplicates(ctx, t.TempDir(), nil, nil); err == nil {
+		t.Fatal("expected cancellation error")
+	}
+}
+
+func TestPruneDuplicateSets(t *testing.T) {
+	root := t.TempDir()
+	keep := filepath.Join(root, "keep")
+	other := filepath.Join(root, "other")
+	writeFileWithContent(t, keep, []byte("x"))
+	writeFileWithContent(t, other, []byte("x"))
+
+	sets := []duplicateSet{
+		{Size: 1, Files: []fileEntry{{Path: keep}, {Path: filepath.Join(root, "gone")}}},
+		{Size: 1, Files: []fileEntry{{Path: keep}, {Path: other}}},
+	}
+	pruned := pruneDuplicateSets(sets)
+	if len(pruned) != 1 || len(pruned[0].Files) != 2 {
+		t.Fatalf("expected only the intact set to remain, got %+v", pruned)
+	}
+}
+
+func TestDuplicatesViewSelectAndDelete(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	m := newModel("/tmp/dups", false)
+	m.scanning = false
+	m.width, m.height = 120, 40
+
+	updated, cmd := m.updateKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	m = updated.(model)
+	if !m.showDuplicates || !m.findingDuplicates || cmd == nil {
+		t.Fatalf("expected duplicate search to start, show=%v finding=%v", m.showDuplicates, m.findingDuplicates)
+	}
+
+	m.dupScans.stop()
+	_, id := m.dupScans.begin("/tmp/dups")
+	sets := []duplicateSet{{Size: 10, Files: []fileEntry{
+		{Name: "a", Path: "/tmp/dups/a", Size: 10},
+		{Name: "b", Path: "/tmp/dups/b", Size: 10},
+	}}}
+	updated, _ = m.Update(duplicatesMsg{path: "/tmp/dups", id: id, sets: sets})
+	m = updated.(model)
+	if m.findingDuplicates || len(m.duplicates) != 1 {
+		t.Fatalf("expected sets applied, got %+v", m.duplicates)
+	}
+
+	updated, _ = m.updateKey(tea.KeyMsg{Type: tea.KeyDown})
+	m = updated.(model)
+	updated, _ = m.updateKey(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	m = updated.(model)
+	if !m.dupMultiSelected["/tmp/dups/b"] || len(m.dupMultiSelected) != 1 {
+		t.Fatalf("expected second copy selected, got %v", m.dupMultiSelected)
+	}
+
+	updated, _ = m.updateKey(tea.KeyMsg{Type: tea.KeyBackspace})
+	m = updated.(model)
+	if !m.deleteConfirm || m.deleteTarget == nil || m.deleteTarget.Path != "/tmp/dups/b" {
+		t.Fatalf("expected delete confirmation for the selected copy, got %+v", m.deleteTarget)
+	}
+	view := m.View()
+	if !strings.Contains(view, "reclaimable") {
+		t.Fatalf("expected set summary in view:\n%s", view)
+	}
+	if strings.Contains(view, "no copy will be kept") {
+		t.Fatal("one copy remains unselected; no warning expected")
+	}
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// jsonReport is the stable schema printed by --json.
+// Field names are part of the public contract; add fields, never rename them.
+type jsonReport struct {
+	Path       string          `json:"path"`
+	ScannedAt  time.Time       `json:"scanned_at"`
+	TotalSize  int64           `json:"total_size"`
+	TotalFiles int64           `json:"total_files"`
+	Entries    []jsonEntry     `json:"entries"`
+	LargeFiles []jsonLargeFile `json:"large_files"`
+}
+
+type jsonEntry struct {
+	Name       string      `json:"name"`
+	Path       string      `json:"path"`
+	Size       int64       `json:"size"`
+	SharedSize int64       `json:"shared_size,omitempty"`
+	IsDir      bool        `json:"is_dir"`
+	IsSymlink  bool        `json:"is_symlink,omitempty"`
+	LastAccess *time.Time  `json:"last_access,omitempty"`
+	Children   *jsonReport `json:"children,omitempty"`
+}
+
+type jsonLargeFile struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
+
+// runJSONExport scans path headlessly and writes the report to w.
+func runJSONExport(path string, depth int, w io.Writer) error {
+	report, err := buildJSONReport(path, depth)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// buildJSONReport scans path and expands directory entries depth-1 more
+// levels. Expanded levels are assembled from their children's reports, so
+// every directory is walked once however deep the report goes.
+func buildJSONReport(path string, depth int) (*jsonReport, error) {
+	if depth < 1 {
+		return nil, fmt.Errorf("depth must be at least 1, got %d", depth)
+	}
+
+	var (
+		result   scanResult
+		children map[string]*jsonReport
+		err      error
+	)
+	if depth == 1 {
+		result, err = scanPathHeadless(path)
+	} else {
+		result, children, err = composeScan(path, depth-1)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	report := &jsonReport{
+		Path:       path,
+		ScannedAt:  time.Now().UTC(),
+		TotalSize:  result.TotalSize,
+		TotalFiles: result.TotalFiles,
+		Entries:    make([]jsonEntry, 0, len(result.Entries)),
+		LargeFiles: make([]jsonLargeFile, 0, len(result.LargeFiles)),
+	}
+
+	for _, entry := range result.Entries {
+		item := jsonEntry{
+			Name:       entry.Name,
+			Path:       entry.Path,
+			Size:       entry.Size,
+			SharedSize: entry.Shared,
+			IsDir:      entry.IsDir,
+		}
+
+		info, err := os.Lstat(entry.Path)
+		if err == nil && info.Mode()&fs.ModeSymlink != 0 {
+			item.IsSymlink = true
+		}
+
+		lastAccess := entry.LastAccess
+		if lastAccess.IsZero() && err == nil {
+			lastAccess = getLastAccessTimeFromInfo(info)
+		}
+		if !lastAccess.IsZero() {
+			utc := lastAccess.UTC()
+			item.LastAccess = &utc
+		}
+
+		// Symlinks are never followed, so they have no report here.
+		item.Children = children[entry.Path]
+
+		report.Entries = append(report.Entries, item)
+	}
+
+	for _, file := range result.LargeFiles {
+		report.LargeFiles = append(report.LargeFiles, jsonLargeFile(file))
+	}
+
+	return report, nil
+}
+
+// composeScan builds the scan result of path from reports of its child
+// directories, each expanded depth levels, instead of walking them again.
+// It skips what scanPathConcurrent skips; hard links shared between two
+// children are counted in both.
+func composeScan(path string, depth int) (scanResult, map[string]*jsonReport, error) {
+	children, err := os.ReadDir(path)
+	if err != nil {
+		return scanResult{}, nil, err
+	}
+
+	var (
+		result     scanResult
+		entries    []dirEntry
+		largeFiles []fileEntry
+	)
+	reports := make(map[string]*jsonReport)
+	for _, child := range children {
+		fullPath := filepath.Join(path, child.Name())
+		info, err := child.Info()
+		if err != nil {
+			continue
+		}
+
+		switch {
+		case child.Type()&fs.ModeSymlink != 0:
+			// Count link size only to avoid double-counting targets.
+			target, err := os.Stat(fullPath)
+			size := getActualFileSize(fullPath, info)
+			result.TotalSize += size
+			entries = append(entries, dirEntry{
+				Name:       child.Name() + " →",
+				Path:       fullPath,
+				Size:       size,
+				IsDir:      err == nil && target.IsDir(),
+				LastAccess: getLastAccessTimeFromInfo(info),
+			})
+		case child.IsDir():
+			if defaultSkipDirs[child.Name()] || (path == "/" && skipSystemDirs[child.Name()]) {
+				continue
+			}
+			entry := dirEntry{Name: child.Name(), Path: fullPath, IsDir: true}
+			// An unreadable directory is listed empty, as a scan would.
+			if report, err := buildJSONReport(fullPath, depth); err == nil {
+				reports[fullPath] = report
+				entry.Size = report.TotalSize
+				result.TotalSize += report.TotalSize
+				result.TotalFiles += report.TotalFiles
+				for _, file := range report.LargeFiles {
+					largeFiles = append(largeFiles, fileEntry(file))
+				}
+			}
+			entries = append(entries, entry)
+		default:
+			size := getActualFileSize(fullPath, info)
+			result.TotalSize += size
+			result.TotalFiles++
+			entries = append(entries, dirEntry{
+				Name:       child.Name(),
+				Path:       fullPath,
+				Size:       size,
+				LastAccess: getLastAccessTimeFromInfo(info),
+			})
+			if size >= largeFileWarmupMinSize && !shouldSkipFileForLargeTracking(fullPath) {
+				largeFiles = append(largeFiles, fileEntry{Name: child.Name(), Path: fullPath, Size: size})
+			}
+		}
+	}
+
+	// Each child report holds its own largest files, so the largest of
+	// their union are exact.
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Size > entries[j].Size })
+	sort.SliceStable(largeFiles, func(i, j int) bool { return largeFiles[i].Size > largeFiles[j].Size })
+	result.Entries = entries[:min(len(entries), maxEntries)]
+	result.LargeFiles = largeFiles[:min(len(largeFiles), maxLargeFiles)]
+	return result, reports, nil
+}
+
+// scanPathHeadless mirrors scanCmd without the TUI: disk cache first,
+// then a full scan whose result is written back to the cache.
+func scanPathHeadless(path string) (scanResult, error) {
+	if cached, err := loadCacheFromDisk(path); err == nil {
+		return scanResult{
+			Entries:    cached.Entries,
+			LargeFiles: cached.LargeFiles,
+			TotalSize:  cached.TotalSize,
+			TotalFiles: cached.TotalFiles,
+		}, nil
+	}
+
+	var filesScanned, dirsScanned, bytesScanned int64
+	currentPath := &atomic.Value{}
+	currentPath.Store("")
+
+	result, err := scanPathConcurrent(context.Background(), path, nil, &filesScanned, &dirsScanned, &bytesScanned, currentPath)
+	if err != nil {
+		return scanResult{}, err
+	}
+	_ = recordScan(path, result) // Cache save failure is not critical
+	return result, nil
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBuildJSONReportDepth(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	root := t.TempDir()
+	writeFileWithSize(t, filepath.Join(root, "top.bin"), 100)
+	writeFileWithSize(t, filepath.Join(root, "sub", "inner.bin"), 200)
+	writeFileWithSize(t, filepath.Join(root, "sub", "deeper", "leaf.bin"), 300)
+
+	shallow, err := buildJSONReport(root, 1)
+	if err != nil {
+		t.Fatalf("buildJSONReport depth 1: %v", err)
+	}
+	if shallow.TotalSize != 600 {
+		t.Fatalf("expected total size 600, got %d", shallow.TotalSize)
+	}
+	if shallow.TotalFiles != 3 {
+		t.Fatalf("expected 3 files, got %d", shallow.TotalFiles)
+	}
+	for _, entry := range shallow.Entries {
+		if entry.Children != nil {
+			t.Fatalf("depth 1 should not expand %s", entry.Name)
+		}
+	}
+
+	invalidateCache(root)
+	deep, err := buildJSONReport(root, 2)
+	if err != nil {
+		t.Fatalf("buildJSONReport depth 2: %v", err)
+	}
+	var sub *jsonEntry
+	for i := range deep.Entries {
+		if deep.Entries[i].Name == "sub" {
+			sub = &deep.Entries[i]
+		}
+	}
+	if sub == nil || sub.Children == nil {
+		t.Fatalf("expected sub to be expanded at depth 2, got %+v", sub)
+	}
+	if sub.Children.TotalSize != 500 {
+		t.Fatalf("expected sub total 500, got %d", sub.Children.TotalSize)
+	}
+	for _, entry := range sub.Children.Entries {
+		if entry.Children != nil {
+			t.Fatalf("depth 2 should stop before %s", entry.Name)
+		}
+	}
+
+	if _, err := buildJSONReport(root, 0); err == nil {
+		t.Fatal("expected error for depth 0")
+	}
+}
+
+func TestBuildJSONReportWalksEachDirectoryOnce(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	root := t.TempDir()
+	writeFileWithSize(t, filepath.Join(root, "top.bin"), 100)
+	writeFileWithSize(t, filepath.Join(root, "sub", "inner.bin"), 200)
+	writeFileWithSize(t, filepath.Join(root, "sub", "deeper", "leaf.bin"), 300)
+	writeFileWithSize(t, filepath.Join(root, "other", "big.bin"), 2<<20)
+
+	deep, err := buildJSONReport(root, 3)
+	if err != nil {
+		t.Fatalf("buildJSONReport depth 3: %v", err)
+	}
+	// Only the last level is scanned; the levels above reuse its sizes and
+	// leave no scan of their own behind.
+	for path, want := range map[string]int{
+		root:                                 0,
+		filepath.Join(root, "sub"):           0,
+		filepath.Join(root, "sub", "deeper"): 1,
+		filepath.Join(root, "other"):         0,
+	} {
+		if records, _ := loadScanHistory(path); len(records) != want {
+			t.Errorf("%s scanned %d times, want %d", path, len(records), want)
+		}
+	}
+
+	t.Setenv("HOME", t.TempDir())
+	shallow, err := buildJSONReport(root, 1)
+	if err != nil {
+		t.Fatalf("buildJSONReport depth 1: %v", err)
+	}
+	if deep.TotalSize != shallow.TotalSize || deep.TotalFiles != shallow.TotalFiles {
+		t.Fatalf("deep report totals %d/%d differ from a scan's %d/%d", deep.TotalSize, deep.TotalFiles, shallow.TotalSize, shallow.TotalFiles)
+	}
+	if len(deep.Entries) != len(shallow.Entries) {
+		t.Fatalf("entries differ: %+v vs %+v", deep.Entries, shallow.Entries)
+	}
+	for i := range deep.Entries {
+		if deep.Entries[i].Path != shallow.Entries[i].Path || deep.Entries[i].Size != shallow.Entries[i].Size {
+			t.Errorf("entry %d = %s %d, scan has %s %d", i, deep.Entries[i].Path, deep.Entries[i].Size, shallow.Entries[i].Path, shallow.Entries[i].Size)
+		}
+	}
+	if len(deep.LargeFiles) != len(shallow.LargeFiles) || len(deep.LargeFiles) != 1 || deep.LargeFiles[0].Name != "big.bin" {
+		t.Errorf("large files should come up from the children, got %+v", deep.LargeFiles)
+	}
+}
+
+func TestRunJSONExportSchema(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	root := t.TempDir()
+	writeFileWithSize(t, filepath.Join(root, "data.bin"), 2<<20)
+	if err := os.Symlink(filepath.Join(root, "data.bin"), filepath.Join(root, "link")); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := runJSONExport(root, 1, &buf); err != nil {
+		t.Fatalf("runJSONExport: %v", err)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	for _, key := range []string{"path", "scanned_at", "total_size", "total_files", "entries", "large_files"} {
+		if _, ok := decoded[key]; !ok {
+			t.Fatalf("missing key %q in %s", key, buf.String())
+		}
+	}
+
+	var report jsonReport
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("decode report: %v", err)
+	}
+	if len(report.LargeFiles) != 1 || report.LargeFiles[0].Name != "data.bin" {
+		t.Fatalf("expected data.bin as large file, got %+v", report.LargeFiles)
+	}
+	foundLink := false
+	for _, entry := range report.Entries {
+		if entry.Path == filepath.Join(root, "link") {
+			foundLink = entry.IsSymlink
+		}
+	}
+	if !foundLink {
+		t.Fatalf("expected symlink entry to be flagged, got %+v", report.Entries)
+	}
+}
@@ -245,3 +245,39 @@ func formatUnusedTime(lastAccess time.Time) string {
 
 	return ""
 }
+
+// formatSizeDelta formats growth since a previous scan, e.g. "+4.2 GB".
+func formatSizeDelta(delta int64) string {
+	if delta < 0 {
+		return "-" + humanizeBytes(-delta)
+	}
+	return "+" + humanizeBytes(delta)
+}
+
+// formatSinceLabel names a previous scan time relative to now: a clock time
+// today, a weekday within the last week, otherwise a date.
+func formatSinceLabel(then, now time.Time) string {
+	then = then.In(now.Location())
+	y1, m1, d1 := then.Date()
+	y2, m2, d2 := now.Date()
+	switch {
+	case y1 == y2 && m1 == m2 && d1 == d2:
+		return then.Format("15:04")
+	case now.Sub(then) < 7*24*time.Hour:
+		return then.Weekday().String()
+	case y1 == y2:
+		return then.Format("Jan 2")
+	default:
+		return then.Format("Jan 2, 2006")
+	}
+}
+
+// formatEntryDelta describes how much path changed since baseline, or ""
+// when the baseline did not record it or the size is unchanged.
+func formatEntryDelta(baseline *scanRecord, path string, size int64, now time.Time) string {
+	previous, ok := baseline.entrySize(path)
+	if !ok || previous == size {
+		return ""
+	}
+	return formatSizeDelta(size-previous) + " since " + formatSinceLabel(baseline.ScannedAt, now)
+}
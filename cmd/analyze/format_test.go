@@ -347,3 +347,55 @@ func TestFormatUnusedTime(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatSizeDelta(t *testing.T) {
+	if got := formatSizeDelta(4 << 30); got != "+4.0 GB" {
+		t.Errorf("formatSizeDelta(4GB) = %q", got)
+	}
+	if got := formatSizeDelta(-512); got != "-512 B" {
+		t.Errorf("formatSizeDelta(-512) = %q", got)
+	}
+}
+
+func TestFormatSinceLabel(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC) // Friday
+
+	tests := []struct {
+		name string
+		then time.Time
+		want string
+	}{
+		{"same day", time.Date(2026, 10, 16, 9, 5, 0, 0, time.UTC), "09:05"},
+		{"this week", time.Date(2026, 10, 13, 18, 0, 0, 0, time.UTC), "Tuesday"},
+		{"this year", time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC), "Aug 1"},
+		{"last year", time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC), "Aug 1, 2025"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatSinceLabel(tt.then, now); got != tt.want {
+				t.Errorf("formatSinceLabel() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatEntryDelta(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	baseline := &scanRecord{
+		ScannedAt: time.Date(2026, 10, 13, 18, 0, 0, 0, time.UTC),
+		Entries:   []scanRecordEntry{{Path: "/a", Size: 1 << 30}},
+	}
+
+	if got := formatEntryDelta(baseline, "/a", 5<<30, now); got != "+4.0 GB since Tuesday" {
+		t.Errorf("grown entry = %q", got)
+	}
+	if got := formatEntryDelta(baseline, "/a", 1<<30, now); got != "" {
+		t.Errorf("unchanged entry = %q, want empty", got)
+	}
+	if got := formatEntryDelta(baseline, "/b", 10, now); got != "" {
+		t.Errorf("unrecorded entry = %q, want empty", got)
+	}
+	if got := formatEntryDelta(nil, "/a", 10, now); got != "" {
+		t.Errorf("nil baseline = %q, want empty", got)
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"io/fs"
+	"path/filepath"
+	"sync"
+	"syscall"
+)
+
+// inodeKey identifies file data independently of the path used to reach it.
+type inodeKey struct {
+	dev uint64
+	ino uint64
+}
+
+// hardLinkTracker remembers multiply-linked inodes seen during one scan so
+// their blocks are counted once (pnpm stores, Nix profiles, backups).
+// Linked bytes are left out while walking and assigned by resolve once the
+// walk is done, so results do not depend on which goroutine got there first.
+type hardLinkTracker struct {
+	state *linkState
+	entry string // Top-level entry links are filed under; "" when unscoped
+}
+
+type linkState struct {
+	mu      sync.Mutex
+	inodes  map[inodeKey]*linkedInode
+	entries map[string]bool // Entries with at least one linked file
+}
+
+// linkedInode is one multiply-linked inode and where its links were seen.
+type linkedInode struct {
+	size       int64
+	nlink      uint64
+	owner      string            // Lexically smallest path seen
+	ownerEntry string            // Entry holding owner
+	links      map[string]uint64 // Links seen per entry
+}
+
+// linkShare is one entry's part of the linked bytes.
+type linkShare struct {
+	counted int64 // Bytes of inodes whose smallest path is in the entry
+	shared  int64 // Bytes of the entry's inodes also linked from outside it
+}
+
+func newHardLinkTracker() *hardLinkTracker {
+	return &hardLinkTracker{state: &linkState{
+		inodes:  make(map[inodeKey]*linkedInode),
+		entries: make(map[string]bool),
+	}}
+}
+
+// scope returns a tracker sharing t's inodes that files links under entry.
+func (t *hardLinkTracker) scope(entry string) *hardLinkTracker {
+	if t == nil {
+		return nil
+	}
+	return &hardLinkTracker{state: t.state, entry: entry}
+}
+
+// account returns how many of size bytes to add for the file at path now.
+// Multiply-linked files add nothing here; resolve hands out their bytes.
+func (t *hardLinkTracker) account(path string, info fs.FileInfo, size int64) int64 {
+	if t == nil {
+		return size
+	}
+	key, nlink, ok := fileIdentity(info)
+	if !ok || nlink < 2 {
+		return size
+	}
+
+	s := t.state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	inode := s.inodes[key]
+	if inode == nil {
+		inode = &linkedInode{size: size, nlink: nlink, owner: path, ownerEntry: t.entry, links: make(map[string]uint64)}
+		s.inodes[key] = inode
+	} else if path < inode.owner {
+		inode.owner, inode.ownerEntry = path, t.entry
+	}
+	inode.links[t.entry]++
+	s.entries[t.entry] = true
+	return 0
+}
+
+// hasLinks reports whether any linked file was filed under t's entry.
+func (t *hardLinkTracker) hasLinks() bool {
+	if t == nil {
+		return false
+	}
+	t.state.mu.Lock()
+	defer t.state.mu.Unlock()
+	return t.state.entries[t.entry]
+}
+
+// resolve assigns each linked inode to the entry holding its lexically
+// smallest path. An entry shares an inode when some of its links are
+// outside the entry, in another entry or outside the scan altogether.
+func (t *hardLinkTracker) resolve() map[string]linkShare {
+	if t == nil {
+		return nil
+	}
+	t.state.mu.Lock()
+	defer t.state.mu.Unlock()
+	shares := make(map[string]linkShare)
+	for _, inode := range t.state.inodes {
+		owner := shares[inode.ownerEntry]
+		owner.counted += inode.size
+		shares[inode.ownerEntry] = owner
+		for entry, seen := range inode.links {
+			if seen < inode.nlink {
+				share := shares[entry]
+				share.shared += inode.size
+				shares[entry] = share
+			}
+		}
+	}
+	return shares
+}
+
+// linkedBytes is the size of every linked inode seen, each counted once.
+func (t *hardLinkTracker) linkedBytes() int64 {
+	var total int64
+	for _, share := range t.resolve() {
+		total += share.counted
+	}
+	return total
+}
+
+// ownedFiles lists each linked inode once, under its owning path.
+func (t *hardLinkTracker) ownedFiles() []fileEntry {
+	if t == nil {
+		return nil
+	}
+	t.state.mu.Lock()
+	defer t.state.mu.Unlock()
+	files := make([]fileEntry, 0, len(t.state.inodes))
+	for _, inode := range t.state.inodes {
+		files = append(files, fileEntry{Name: filepath.Base(inode.owner), Path: inode.owner, Size: inode.size})
+	}
+	return files
+}
+
+func fileIdentity(info fs.FileInfo) (inodeKey, uint64, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return inodeKey{}, 0, false
+	}
+	//nolint:unconvert // Stat_t field widths differ between darwin and linux
+	return inodeKey{dev: uint64(stat.Dev), ino: uint64(stat.Ino)}, uint64(stat.Nlink), true
+}
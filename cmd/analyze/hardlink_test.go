@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+func TestHardLinkTrackerResolve(t *testing.T) {
+	base := t.TempDir()
+	link := func(paths ...string) os.FileInfo {
+		t.Helper()
+		writeFileWithSize(t, paths[0], 4096)
+		for _, p := range paths[1:] {
+			if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+				t.Fatalf("mkdir: %v", err)
+			}
+			if err := os.Link(paths[0], p); err != nil {
+				t.Skipf("hard links unsupported: %v", err)
+			}
+		}
+		info, _ := os.Lstat(paths[0])
+		return info
+	}
+	a, b, c := filepath.Join(base, "a"), filepath.Join(base, "b"), filepath.Join(base, "c")
+	// One inode linked from a and b, one linked twice inside c, and one whose
+	// second link is outside the scan.
+	across := link(filepath.Join(b, "x"), filepath.Join(a, "x"))
+	inside := link(filepath.Join(c, "1"), filepath.Join(c, "2"))
+	outside := link(filepath.Join(c, "solo"), filepath.Join(base, "elsewhere"))
+	single := filepath.Join(base, "single")
+	writeFileWithSize(t, single, 4096)
+	singleInfo, _ := os.Lstat(single)
+
+	// The order links are met in must not change the outcome.
+	for _, reversed := range []bool{false, true} {
+		links := newHardLinkTracker()
+		if got := links.scope(single).account(single, singleInfo, 4096); got != 4096 {
+			t.Fatalf("single link should be counted right away, got %d", got)
+		}
+		visits := []struct {
+			entry, path string
+			info        os.FileInfo
+		}{
+			{a, filepath.Join(a, "x"), across},
+			{b, filepath.Join(b, "x"), across},
+			{c, filepath.Join(c, "1"), inside},
+			{c, filepath.Join(c, "2"), inside},
+			{c, filepath.Join(c, "solo"), outside},
+		}
+		if reversed {
+			for i, j := 0, len(visits)-1; i < j; i, j = i+1, j-1 {
+				visits[i], visits[j] = visits[j], visits[i]
+			}
+		}
+		for _, v := range visits {
+			if got := links.scope(v.entry).account(v.path, v.info, 4096); got != 0 {
+				t.Fatalf("linked file should wait for resolve, got %d", got)
+			}
+		}
+
+		shares := links.resolve()
+		want := map[string]linkShare{
+			a: {counted: 4096, shared: 4096},
+			b: {shared: 4096},
+			c: {counted: 8192, shared: 4096},
+		}
+		for entry, share := range want {
+			if shares[entry] != share {
+				t.Errorf("reversed=%v: %s share = %+v, want %+v", reversed, filepath.Base(entry), shares[entry], share)
+			}
+		}
+		if !links.scope(a).hasLinks() || links.scope(single).hasLinks() {
+			t.Errorf("hasLinks should only be set for entries with linked files")
+		}
+		if got := links.linkedBytes(); got != 3*4096 {
+			t.Errorf("linkedBytes = %d, want %d", got, 3*4096)
+		}
+	}
+
+	var nilTracker *hardLinkTracker
+	if got := nilTracker.account(filepath.Join(a, "x"), across, 4096); got != 4096 {
+		t.Fatalf("nil tracker should count every link, got %d", got)
+	}
+}
+
+func TestScanPathConcurrentCountsHardLinksOnce(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	root := t.TempDir()
+	original := filepath.Join(root, "store", "blob.bin")
+	writeFileWithSize(t, original, 64<<10)
+	linked := filepath.Join(root, "project", "blob.bin")
+	if err := os.MkdirAll(filepath.Dir(linked), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.Link(original, linked); err != nil {
+		t.Skipf("hard links unsupported: %v", err)
+	}
+
+	info, err := os.Lstat(original)
+	if err != nil {
+		t.Fatalf("lstat: %v", err)
+	}
+	blobSize := getActualFileSize(original, info)
+
+	var filesScanned, dirsScanned, bytesScanned int64
+	current := &atomic.Value{}
+	current.Store("")
+
+	result, err := scanPathConcurrent(context.Background(), root, nil, &filesScanned, &dirsScanned, &bytesScanned, current)
+	if err != nil {
+		t.Fatalf("scanPathConcurrent: %v", err)
+	}
+	if result.TotalSize != blobSize {
+		t.Fatalf("expected total %d (one copy), got %d", blobSize, result.TotalSize)
+	}
+
+	sizes := map[string]int64{}
+	for _, entry := range result.Entries {
+		if entry.Shared != blobSize {
+			t.Fatalf("expected %s to report %d shared bytes, got %d", entry.Name, blobSize, entry.Shared)
+		}
+		sizes[entry.Name] = entry.Size
+	}
+	// project/blob.bin sorts before store/blob.bin, so project owns the bytes
+	// on every scan.
+	if sizes["project"] != blobSize || sizes["store"] != 0 {
+		t.Fatalf("expected project to own the linked bytes, got %v", sizes)
+	}
+
+	logical, err := getDirectoryLogicalSizeWithExclude(root, "")
+	if err != nil {
+		t.Fatalf("getDirectoryLogicalSizeWithExclude: %v", err)
+	}
+	if logical != blobSize {
+		t.Fatalf("expected logical size %d (one copy), got %d", blobSize, logical)
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+// scanRecord is one line of a directory's scan history. Records are only
+// appended, so earlier scans stay available for growth comparisons.
+type scanRecord struct {
+	Path       string            `json:"path"`
+	ScannedAt  time.Time         `json:"scanned_at"`
+	TotalSize  int64             `json:"total_size"`
+	TotalFiles int64             `json:"total_files"`
+	Entries    []scanRecordEntry `json:"entries"`
+}
+
+type scanRecordEntry struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
+
+// entrySize returns the recorded size of path and whether it was recorded.
+func (r *scanRecord) entrySize(path string) (int64, bool) {
+	if r == nil {
+		return 0, false
+	}
+	for _, entry := range r.Entries {
+		if entry.Path == path {
+			return entry.Size, true
+		}
+	}
+	return 0, false
+}
+
+func getHistoryPath(path string) (string, error) {
+	cacheDir, err := getCacheDir()
+	if err != nil {
+		return "", err
+	}
+	historyDir := filepath.Join(cacheDir, historyDirName)
+	if err := os.MkdirAll(historyDir, 0755); err != nil {
+		return "", err
+	}
+	filename := fmt.Sprintf("%x.jsonl", xxhash.Sum64String(path))
+	return filepath.Join(historyDir, filename), nil
+}
+
+// loadScanHistory returns the recorded scans for path, oldest first.
+// Unreadable lines are skipped so one torn write does not lose the rest.
+func loadScanHistory(path string) ([]scanRecord, error) {
+	historyPath, err := getHistoryPath(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(historyPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var records []scanRecord
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64<<10), 4<<20)
+	for scanner.Scan() {
+		var record scanRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil || record.Path != path {
+			continue
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
+
+// previousScanRecord returns the newest record for path taken before the
+// given time, or nil when there is none.
+func previousScanRecord(path string, before time.Time) *scanRecord {
+	records, err := loadScanHistory(path)
+	if err != nil {
+		return nil
+	}
+	for i := len(records) - 1; i >= 0; i-- {
+		if records[i].ScannedAt.Before(before) {
+			return &records[i]
+		}
+	}
+	return nil
+}
+
+// appendScanHistory adds a record for result and trims the file to the
+// newest maxHistoryRecords once it grows past twice that.
+func appendScanHistory(path string, result scanResult, scannedAt time.Time) error {
+	historyPath, err := getHistoryPath(path)
+	if err != nil {
+		return err
+	}
+
+	record := scanRecord{
+		Path:       path,
+		ScannedAt:  scannedAt,
+		TotalSize:  result.TotalSize,
+		TotalFiles: result.TotalFiles,
+		Entries:    make([]scanRecordEntry, 0, len(result.Entries)),
+	}
+	for _, entry := range result.Entries {
+		record.Entries = append(record.Entries, scanRecordEntry{Path: entry.Path, Size: entry.Size})
+	}
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(historyPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return compactScanHistory(path, historyPath)
+}
+
+func compactScanHistory(path, historyPath string) error {
+	records, err := loadScanHistory(path)
+	if err != nil || len(records) <= 2*maxHistoryRecords {
+		return err
+	}
+
+	var buf bytes.Buffer
+	for _, record := range records[len(records)-maxHistoryRecords:] {
+		line, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	tmpPath := historyPath + ".tmp"
+	if err := os.WriteFile(tmpPath, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, historyPath)
+}
+
+// recordScan persists a finished scan to both the disk cache and the
+// history, stamping them with the same time so a cached result can find
+// the scan that preceded it.
+func recordScan(path string, result scanResult) error {
+	scannedAt := time.Now()
+	historyErr := appendScanHistory(path, result, scannedAt)
+	if err := saveCacheToDiskAt(path, result, scannedAt); err != nil {
+		return err
+	}
+	return historyErr
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestScanHistoryAppendsAndFindsPrevious(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	target := t.TempDir()
+	child := filepath.Join(target, "child")
+
+	first := time.Date(2026, 3, 3, 10, 0, 0, 0, time.UTC)
+	second := first.Add(24 * time.Hour)
+	if err := appendScanHistory(target, scanResult{TotalSize: 100, Entries: []dirEntry{{Path: child, Size: 100}}}, first); err != nil {
+		t.Fatalf("append first: %v", err)
+	}
+	if err := appendScanHistory(target, scanResult{TotalSize: 300, Entries: []dirEntry{{Path: child, Size: 300}}}, second); err != nil {
+		t.Fatalf("append second: %v", err)
+	}
+
+	records, err := loadScanHistory(target)
+	if err != nil {
+		t.Fatalf("loadScanHistory: %v", err)
+	}
+	if len(records) != 2 || records[0].TotalSize != 100 || records[1].TotalSize != 300 {
+		t.Fatalf("unexpected history: %+v", records)
+	}
+
+	prev := previousScanRecord(target, second)
+	if prev == nil || !prev.ScannedAt.Equal(first) {
+		t.Fatalf("expected first scan as baseline, got %+v", prev)
+	}
+	if size, ok := prev.entrySize(child); !ok || size != 100 {
+		t.Fatalf("expected child size 100, got %d (%v)", size, ok)
+	}
+	if previousScanRecord(target, first) != nil {
+		t.Fatal("expected no record before the first scan")
+	}
+}
+
+func TestScanHistoryCompacts(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	target := t.TempDir()
+
+	start := time.Now().Add(-time.Hour)
+	for i := range 2*maxHistoryRecords + 1 {
+		if err := appendScanHistory(target, scanResult{TotalSize: int64(i)}, start.Add(time.Duration(i)*time.Second)); err != nil {
+			t.Fatalf("append %d: %v", i, err)
+		}
+	}
+
+	records, err := loadScanHistory(target)
+	if err != nil {
+		t.Fatalf("loadScanHistory: %v", err)
+	}
+	if len(records) != maxHistoryRecords {
+		t.Fatalf("expected %d records after compaction, got %d", maxHistoryRecords, len(records))
+	}
+	if last := records[len(records)-1].TotalSize; last != 2*maxHistoryRecords {
+		t.Fatalf("expected newest record kept, got total %d", last)
+	}
+}
+
+func TestRecordScanLinksCacheToHistory(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	target := t.TempDir()
+	writeFileWithSize(t, filepath.Join(target, "a"), 10)
+
+	if err := appendScanHistory(target, scanResult{TotalSize: 5}, time.Now().Add(-48*time.Hour)); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	if err := recordScan(target, scanResult{TotalSize: 10}); err != nil {
+		t.Fatalf("recordScan: %v", err)
+	}
+
+	cached, err := loadCacheFromDisk(target)
+	if err != nil {
+		t.Fatalf("loadCacheFromDisk: %v", err)
+	}
+	prev := previousScanRecord(target, cached.ScanTime)
+	if prev == nil || prev.TotalSize != 5 {
+		t.Fatalf("expected the scan before the cached one as baseline, got %+v", prev)
+	}
+}
+
+func TestLoadScanHistorySkipsTornLines(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	target := t.TempDir()
+
+	if err := appendScanHistory(target, scanResult{TotalSize: 1}, time.Now()); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	historyPath, err := getHistoryPath(target)
+	if err != nil {
+		t.Fatalf("getHistoryPath: %v", err)
+	}
+	file, err := os.OpenFile(historyPath, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	_, _ = file.WriteString("{\"path\":\n")
+	_ = file.Close()
+
+	records, err := loadScanHistory(target)
+	if err != nil || len(records) != 1 {
+		t.Fatalf("expected the intact record only, got %d (%v)", len(records), err)
+	}
+}
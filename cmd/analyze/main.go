@@ -1,13 +1,11 @@
-//go:build darwin
-
 package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"io/fs"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"slices"
 	"sort"
@@ -22,8 +20,10 @@ type dirEntry struct {
 	Name       string
 	Path       string
 	Size       int64
+	Shared     int64 // Bytes in hard-linked files also referenced outside this entry
 	IsDir      bool
 	LastAccess time.Time
+	Incomplete bool // Sizing was cut short by a cancelled scan
 }
 
 type fileEntry struct {
@@ -37,9 +37,11 @@ type scanResult struct {
 	LargeFiles []fileEntry
 	TotalSize  int64
 	TotalFiles int64
+	Incomplete bool
 }
 
 type cacheEntry struct {
+	Path       string // Scanned directory, so a copied snapshot can be rescanned
 	Entries    []dirEntry
 	LargeFiles []fileEntry
 	TotalSize  int64
@@ -60,11 +62,24 @@ type historyEntry struct {
 	LargeOffset   int
 	Dirty         bool
 	IsOverview    bool
+	Incomplete    bool
+	Baseline      *scanRecord
 }
 
 type scanResultMsg struct {
-	result scanResult
-	err    error
+	path     string
+	id       uint64
+	result   scanResult
+	baseline *scanRecord // Previous recorded scan of path, for deltas
+	err      error
+}
+
+// scanEntriesMsg carries children that finished while a scan is running.
+type scanEntriesMsg struct {
+	path    string
+	id      uint64
+	entries []dirEntry
+	updates <-chan dirEntry
 }
 
 type overviewSizeMsg struct {
@@ -72,6 +87,7 @@ type overviewSizeMsg struct {
 	Index int
 	Size  int64
 	Err   error
+	ID    uint64 // Overview scan the walk belonged to
 }
 
 type tickMsg time.Time
@@ -114,12 +130,28 @@ type model struct {
 	overviewCurrentPath  *string
 	overviewScanning     bool
 	overviewScanningSet  map[string]bool // Track which paths are currently being scanned
+	overviewScans        *scanController // Cancels the overview's in-flight size walks
 	width                int             // Terminal width
 	height               int             // Terminal height
 	multiSelected        map[string]bool // Track multi-selected items by path (safer than index)
 	largeMultiSelected   map[string]bool // Track multi-selected large files by path (safer than index)
 	totalFiles           int64           // Total files found in current/last scan
 	lastTotalFiles       int64           // Total files from previous scan (for progress bar)
+	incomplete           bool            // Current entries come from a cancelled scan
+	scans                *scanController // Cancels the in-flight directory scan
+	liveEntries          []dirEntry      // Children finished so far in the running scan
+	liveScanID           uint64          // Scan that liveEntries belong to
+	baseline             *scanRecord     // Previous scan the entries are compared against
+	showDuplicates       bool
+	findingDuplicates    bool
+	duplicates           []duplicateSet
+	duplicatesPath       string // Directory the duplicate sets were found under
+	duplicateSelected    int    // Row index into duplicateRows(duplicates)
+	duplicateOffset      int
+	dupMultiSelected     map[string]bool // Track multi-selected duplicate copies by path
+	dupScans             *scanController // Cancels the in-flight duplicate search
+	dupFilesSeen         *int64
+	dupFilesHashed       *int64
 }
 
 func (m model) inOverviewMode() bool {
@@ -127,9 +159,36 @@ func (m model) inOverviewMode() bool {
 }
 
 func main() {
+	// "diff" is a subcommand, so a directory of that name is opened as ./diff.
+	// --diff is kept as an alias.
+	if len(os.Args) > 1 && (os.Args[1] == "diff" || os.Args[1] == "--diff" || os.Args[1] == "-diff") {
+		os.Exit(runDiffCommand(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
+	jsonOutput := flag.Bool("json", false, "print scan results for <path> as JSON and exit")
+	depth := flag.Int("depth", 1, "directory levels to expand in JSON output")
+	flag.Parse()
+
 	target := os.Getenv("MO_ANALYZE_PATH")
-	if target == "" && len(os.Args) > 1 {
-		target = os.Args[1]
+	if target == "" && flag.NArg() > 0 {
+		target = flag.Arg(0)
+	}
+
+	if *jsonOutput {
+		if target == "" {
+			fmt.Fprintln(os.Stderr, "usage: analyze-go --json [--depth N] <path>")
+			os.Exit(2)
+		}
+		abs, err := filepath.Abs(target)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cannot resolve %q: %v\n", target, err)
+			os.Exit(1)
+		}
+		if err := runJSONExport(abs, *depth, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "analyzer error: %v\n", err)
+			os.Exit(1)
+		}
+		return
 	}
 
 	var abs string
@@ -166,6 +225,7 @@ func newModel(path string, isOverview bool) model {
 	currentPath.Store("")
 	var overviewFilesScanned, overviewDirsScanned, overviewBytesScanned int64
 	overviewCurrentPath := ""
+	var dupFilesSeen, dupFilesHashed int64
 
 	m := model{
 		path:                 path,
@@ -185,8 +245,14 @@ func newModel(path string, isOverview bool) model {
 		overviewCurrentPath:  &overviewCurrentPath,
 		overviewSizeCache:    make(map[string]int64),
 		overviewScanningSet:  make(map[string]bool),
+		overviewScans:        newScanController(),
 		multiSelected:        make(map[string]bool),
 		largeMultiSelected:   make(map[string]bool),
+		dupMultiSelected:     make(map[string]bool),
+		scans:                newScanController(),
+		dupScans:             newScanController(),
+		dupFilesSeen:         &dupFilesSeen,
+		dupFilesHashed:       &dupFilesHashed,
 	}
 
 	if isOverview {
@@ -212,33 +278,6 @@ func newModel(path string, isOverview bool) model {
 	return m
 }
 
-func createOverviewEntries() []dirEntry {
-	home := os.Getenv("HOME")
-	entries := []dirEntry{}
-
-	// Separate Home and ~/Library to avoid double counting.
-	if home != "" {
-		entries = append(entries, dirEntry{Name: "Home", Path: home, IsDir: true, Size: -1})
-
-		userLibrary := filepath.Join(home, "Library")
-		if _, err := os.Stat(userLibrary); err == nil {
-			entries = append(entries, dirEntry{Name: "App Library", Path: userLibrary, IsDir: true, Size: -1})
-		}
-	}
-
-	entries = append(entries,
-		dirEntry{Name: "Applications", Path: "/Applications", IsDir: true, Size: -1},
-		dirEntry{Name: "System Library", Path: "/Library", IsDir: true, Size: -1},
-	)
-
-	// Include Volumes only when real mounts exist.
-	if hasUsefulVolumeMounts("/Volumes") {
-		entries = append(entries, dirEntry{Name: "Volumes", Path: "/Volumes", IsDir: true, Size: -1})
-	}
-
-	return entries
-}
-
 func hasUsefulVolumeMounts(path string) bool {
 	entries, err := os.ReadDir(path)
 	if err != nil {
@@ -314,11 +353,13 @@ func (m *model) scheduleOverviewScans() tea.Cmd {
 		return nil
 	}
 
+	// The walks have no singleflight entry, so the controller gets no path.
+	ctx, id := m.overviewScans.join("")
 	var cmds []tea.Cmd
 	for _, idx := range pendingIndices {
 		entry := m.entries[idx]
 		m.overviewScanningSet[entry.Path] = true
-		cmd := scanOverviewPathCmd(entry.Path, idx)
+		cmd := scanOverviewPathCmd(ctx, id, entry.Path, idx, m.overviewFilesScanned, m.overviewDirsScanned, m.overviewBytesScanned)
 		cmds = append(cmds, cmd)
 	}
 
@@ -362,8 +403,14 @@ func (m model) Init() tea.Cmd {
 	return tea.Batch(m.scanCmd(m.path), tickCmd())
 }
 
+// scanCmd starts a scan of path, cancelling any scan already in flight.
+// Finished children stream back as scanEntriesMsg before the final result.
 func (m model) scanCmd(path string) tea.Cmd {
-	return func() tea.Msg {
+	ctx, id := m.scans.begin(path)
+	updates := make(chan dirEntry, scanUpdateBuffer)
+	run := func() tea.Msg {
+		defer close(updates)
+
 		if cached, err := loadCacheFromDisk(path); err == nil {
 			result := scanResult{
 				Entries:    cached.Entries,
@@ -371,27 +418,89 @@ func (m model) scanCmd(path string) tea.Cmd {
 				TotalSize:  cached.TotalSize,
 				TotalFiles: 0, // Cache doesn't store file count currently, minor UI limitation
 			}
-			return scanResultMsg{result: result, err: nil}
+			baseline := previousScanRecord(path, cached.ScanTime)
+			return scanResultMsg{path: path, id: id, result: result, baseline: baseline, err: nil}
 		}
 
+		started := time.Now()
 		v, err, _ := scanGroup.Do(path, func() (any, error) {
-			return scanPathConcurrent(path, m.filesScanned, m.dirsScanned, m.bytesScanned, m.currentPath)
+			return scanPathConcurrent(ctx, path, updates, m.filesScanned, m.dirsScanned, m.bytesScanned, m.currentPath)
 		})
 
 		if err != nil {
-			return scanResultMsg{err: err}
+			return scanResultMsg{path: path, id: id, err: err}
 		}
 
 		result := v.(scanResult)
+		baseline := previousScanRecord(path, started)
+
+		// Partial results are shown but never persisted.
+		if !result.Incomplete {
+			go func(p string, r scanResult) {
+				if err := recordScan(p, r); err != nil {
+					_ = err // Cache save failure is not critical
+				}
+			}(path, result)
+		}
+
+		return scanResultMsg{path: path, id: id, result: result, baseline: baseline, err: nil}
+	}
+	return tea.Batch(run, waitForScanEntries(path, id, updates))
+}
 
-		go func(p string, r scanResult) {
-			if err := saveCacheToDisk(p, r); err != nil {
-				_ = err // Cache save failure is not critical
+// waitForScanEntries blocks for the next finished child and then collects
+// whatever else arrives within scanUpdateInterval, so a burst of small
+// files becomes one redraw.
+func waitForScanEntries(path string, id uint64, updates <-chan dirEntry) tea.Cmd {
+	return func() tea.Msg {
+		entry, ok := <-updates
+		if !ok {
+			return nil
+		}
+		msg := scanEntriesMsg{path: path, id: id, entries: []dirEntry{entry}, updates: updates}
+
+		timer := time.NewTimer(scanUpdateInterval)
+		defer timer.Stop()
+		for {
+			select {
+			case entry, ok := <-updates:
+				if !ok {
+					return msg
+				}
+				msg.entries = append(msg.entries, entry)
+			case <-timer.C:
+				return msg
 			}
-		}(path, result)
+		}
+	}
+}
 
-		return scanResultMsg{result: result, err: nil}
+// mergeLiveEntries folds streamed entries into the live list, keeping the
+// largest maxEntries sorted by size.
+func mergeLiveEntries(live, updates []dirEntry) []dirEntry {
+	for _, update := range updates {
+		if update.Size <= 0 && update.Shared <= 0 {
+			continue
+		}
+		replaced := false
+		for i := range live {
+			if live[i].Path == update.Path {
+				live[i] = update
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			live = append(live, update)
+		}
+	}
+	sort.SliceStable(live, func(i, j int) bool {
+		return live[i].Size > live[j].Size
+	})
+	if len(live) > maxEntries {
+		live = live[:maxEntries]
 	}
+	return live
 }
 
 func tickCmd() tea.Cmd {
@@ -413,6 +522,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.deleting = false
 			m.multiSelected = make(map[string]bool)
 			m.largeMultiSelected = make(map[string]bool)
+			m.dupMultiSelected = make(map[string]bool)
 			if msg.err != nil {
 				m.status = fmt.Sprintf("Failed to delete: %v", msg.err)
 			} else {
@@ -422,6 +532,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				invalidateCache(m.path)
 				m.status = fmt.Sprintf("Deleted %d items", msg.count)
+				if m.duplicates != nil {
+					m.duplicates = pruneDuplicateSets(m.duplicates)
+					m.clampDuplicateSelection()
+				}
 				for i := range m.history {
 					m.history[i].Dirty = true
 				}
@@ -441,27 +555,43 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 		return m, nil
+	case scanEntriesMsg:
+		if !m.scans.isCurrent(msg.id) || msg.path != m.path || !m.scanning {
+			return m, nil
+		}
+		if msg.id != m.liveScanID {
+			m.liveEntries = nil
+			m.liveScanID = msg.id
+		}
+		m.liveEntries = mergeLiveEntries(m.liveEntries, msg.entries)
+		return m, waitForScanEntries(msg.path, msg.id, msg.updates)
 	case scanResultMsg:
+		if !m.scans.isCurrent(msg.id) || (msg.path != "" && msg.path != m.path) {
+			// A scan the user moved away from; keep what it found for later.
+			m.storePartialScan(msg)
+			return m, nil
+		}
+		m.scans.finish(msg.id)
 		m.scanning = false
+		m.liveEntries = nil
 		if msg.err != nil {
 			m.status = fmt.Sprintf("Scan failed: %v", msg.err)
 			return m, nil
 		}
-		filteredEntries := make([]dirEntry, 0, len(msg.result.Entries))
-		for _, e := range msg.result.Entries {
-			if e.Size > 0 {
-				filteredEntries = append(filteredEntries, e)
-			}
-		}
-		m.entries = filteredEntries
+		m.entries = filterScannedEntries(msg.result.Entries)
 		m.largeFiles = msg.result.LargeFiles
 		m.totalSize = msg.result.TotalSize
 		m.totalFiles = msg.result.TotalFiles
+		m.incomplete = msg.result.Incomplete
+		m.baseline = msg.baseline
 		m.status = fmt.Sprintf("Scanned %s", humanizeBytes(m.totalSize))
+		if m.incomplete {
+			m.status = fmt.Sprintf("Partial scan, %s so far, press R to rescan", humanizeBytes(m.totalSize))
+		}
 		m.clampEntrySelection()
 		m.clampLargeSelection()
 		m.cache[m.path] = cacheSnapshot(m)
-		if m.totalSize > 0 {
+		if m.totalSize > 0 && !m.incomplete {
 			if m.overviewSizeCache == nil {
 				m.overviewSizeCache = make(map[string]int64)
 			}
@@ -471,15 +601,40 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}(m.path, m.totalSize)
 		}
 		return m, nil
+	case duplicatesMsg:
+		if !m.dupScans.isCurrent(msg.id) {
+			return m, nil
+		}
+		m.dupScans.finish(msg.id)
+		m.findingDuplicates = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Duplicate search failed: %v", msg.err)
+			return m, nil
+		}
+		m.duplicates = msg.sets
+		m.duplicatesPath = msg.path
+		m.duplicateSelected = 0
+		m.duplicateOffset = 0
+		m.dupMultiSelected = make(map[string]bool)
+		if len(m.duplicates) == 0 {
+			m.status = "No duplicates found"
+		} else {
+			m.status = fmt.Sprintf("%d duplicate sets, %s reclaimable", len(m.duplicates), humanizeBytes(sumDuplicateWaste(m.duplicates)))
+		}
+		return m, nil
 	case overviewSizeMsg:
-		delete(m.overviewScanningSet, msg.Path)
-
 		if msg.Err == nil {
 			if m.overviewSizeCache == nil {
 				m.overviewSizeCache = make(map[string]int64)
 			}
 			m.overviewSizeCache[msg.Path] = msg.Size
 		}
+		// A walk from a stopped overview scan is not in overviewScanningSet
+		// anymore; its cancellation error is not a measuring failure.
+		if !m.overviewScans.isCurrent(msg.ID) {
+			return m, nil
+		}
+		delete(m.overviewScanningSet, msg.Path)
 
 		if m.inOverviewMode() {
 			for i := range m.entries {
@@ -512,7 +667,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 		}
-		if m.scanning || m.deleting || (m.inOverviewMode() && (m.overviewScanning || hasPending)) {
+		if m.scanning || m.deleting || m.findingDuplicates || (m.inOverviewMode() && (m.overviewScanning || hasPending)) {
 			m.spinner = (m.spinner + 1) % len(spinnerFrames)
 			if m.deleting && m.deleteCount != nil {
 				count := atomic.LoadInt64(m.deleteCount)
@@ -540,7 +695,15 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 			// Collect paths (safer than indices).
 			var pathsToDelete []string
-			if m.showLargeFiles {
+			if m.showDuplicates {
+				if len(m.dupMultiSelected) > 0 {
+					for path := range m.dupMultiSelected {
+						pathsToDelete = append(pathsToDelete, path)
+					}
+				} else if m.deleteTarget != nil {
+					pathsToDelete = append(pathsToDelete, m.deleteTarget.Path)
+				}
+			} else if m.showLargeFiles {
 				if len(m.largeMultiSelected) > 0 {
 					for path := range m.largeMultiSelected {
 						pathsToDelete = append(pathsToDelete, path)
@@ -585,15 +748,31 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	switch msg.String() {
 	case "q", "ctrl+c", "Q":
+		m.scans.stop()
+		m.dupScans.stop()
+		m.overviewScans.stop()
 		return m, tea.Quit
 	case "esc":
+		if m.showDuplicates {
+			m.leaveDuplicatesView()
+			return m, nil
+		}
 		if m.showLargeFiles {
 			m.showLargeFiles = false
 			return m, nil
 		}
+		m.scans.stop()
+		m.overviewScans.stop()
 		return m, tea.Quit
 	case "up", "k", "K":
-		if m.showLargeFiles {
+		if m.showDuplicates {
+			if m.duplicateSelected > 0 {
+				m.duplicateSelected--
+				if m.duplicateSelected < m.duplicateOffset {
+					m.duplicateOffset = m.duplicateSelected
+				}
+			}
+		} else if m.showLargeFiles {
 			if m.largeSelected > 0 {
 				m.largeSelected--
 				if m.largeSelected < m.largeOffset {
@@ -607,7 +786,15 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 		}
 	case "down", "j", "J":
-		if m.showLargeFiles {
+		if m.showDuplicates {
+			if m.duplicateSelected < len(duplicateRows(m.duplicates))-1 {
+				m.duplicateSelected++
+				viewport := calculateViewport(m.height, true)
+				if m.duplicateSelected >= m.duplicateOffset+viewport {
+					m.duplicateOffset = m.duplicateSelected - viewport + 1
+				}
+			}
+		} else if m.showLargeFiles {
 			if m.largeSelected < len(m.largeFiles)-1 {
 				m.largeSelected++
 				viewport := calculateViewport(m.height, true)
@@ -623,15 +810,22 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 		}
 	case "enter", "right", "l", "L":
-		if m.showLargeFiles {
+		if m.showLargeFiles || m.showDuplicates {
 			return m, nil
 		}
 		return m.enterSelectedDir()
 	case "b", "left", "h", "B", "H":
+		if m.showDuplicates {
+			m.leaveDuplicatesView()
+			return m, nil
+		}
 		if m.showLargeFiles {
 			m.showLargeFiles = false
 			return m, nil
 		}
+		if m.scanning {
+			m.scans.stop()
+		}
 		if len(m.history) == 0 {
 			if !m.inOverviewMode() {
 				return m, m.switchToOverviewMode()
@@ -646,6 +840,8 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.largeSelected = last.LargeSelected
 		m.largeOffset = last.LargeOffset
 		m.isOverview = last.IsOverview
+		m.incomplete = last.Incomplete
+		m.baseline = last.Baseline
 		if last.Dirty {
 			// On overview return, refresh cached entries.
 			if last.IsOverview {
@@ -678,25 +874,37 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		m.status = fmt.Sprintf("Scanned %s", humanizeBytes(m.totalSize))
 		m.scanning = false
+		if m.inOverviewMode() && hasPendingOverviewEntries(m.entries) {
+			// Walks stopped on the way out; start the unfinished ones again.
+			m.status = "Checking system folders..."
+			return m, tea.Batch(m.scheduleOverviewScans(), tickCmd())
+		}
 		return m, nil
 	case "r", "R":
 		m.multiSelected = make(map[string]bool)
 		m.largeMultiSelected = make(map[string]bool)
 
+		if m.showDuplicates {
+			return m, m.startDuplicateSearch()
+		}
+
 		if m.inOverviewMode() {
 			// Explicitly invalidate cache for all overview entries to force re-scan
 			for _, entry := range m.entries {
 				invalidateCache(entry.Path)
 			}
 
+			m.stopOverviewScans()
 			m.overviewSizeCache = make(map[string]int64)
-			m.overviewScanningSet = make(map[string]bool)
 			m.hydrateOverviewEntries() // Reset sizes to pending
 
 			for i := range m.entries {
 				m.entries[i].Size = -1
 			}
 			m.totalSize = 0
+			atomic.StoreInt64(m.overviewFilesScanned, 0)
+			atomic.StoreInt64(m.overviewDirsScanned, 0)
+			atomic.StoreInt64(m.overviewBytesScanned, 0)
 
 			m.status = "Refreshing..."
 			m.overviewScanning = true
@@ -718,6 +926,9 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, tea.Batch(m.scanCmd(m.path), tickCmd())
 	case "t", "T":
 		if !m.inOverviewMode() {
+			if m.showDuplicates {
+				m.leaveDuplicatesView()
+			}
 			m.showLargeFiles = !m.showLargeFiles
 			if m.showLargeFiles {
 				m.largeSelected = 0
@@ -728,10 +939,50 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 			m.status = fmt.Sprintf("Scanned %s", humanizeBytes(m.totalSize))
 		}
+	case "d", "D":
+		if m.inOverviewMode() || m.scanning {
+			return m, nil
+		}
+		if m.showDuplicates {
+			m.leaveDuplicatesView()
+			return m, nil
+		}
+		m.showLargeFiles = false
+		m.showDuplicates = true
+		if m.duplicates != nil && m.duplicatesPath == m.path {
+			m.status = fmt.Sprintf("%d duplicate sets, %s reclaimable", len(m.duplicates), humanizeBytes(sumDuplicateWaste(m.duplicates)))
+			return m, nil
+		}
+		return m, m.startDuplicateSearch()
 	case "o", "O":
 		// Open selected entries (multi-select aware).
 		const maxBatchOpen = 20
-		if m.showLargeFiles {
+		if m.showDuplicates {
+			rows := duplicateRows(m.duplicates)
+			if len(m.dupMultiSelected) > 0 {
+				count := len(m.dupMultiSelected)
+				if count > maxBatchOpen {
+					m.status = fmt.Sprintf("Too many items to open, max %d, selected %d", maxBatchOpen, count)
+					return m, nil
+				}
+				for path := range m.dupMultiSelected {
+					go func(p string) {
+						ctx, cancel := context.WithTimeout(context.Background(), openCommandTimeout)
+						defer cancel()
+						_ = openPathCommand(ctx, p).Run()
+					}(path)
+				}
+				m.status = fmt.Sprintf("Opening %d items...", count)
+			} else if m.duplicateSelected < len(rows) {
+				selected := rows[m.duplicateSelected].file
+				go func(path string) {
+					ctx, cancel := context.WithTimeout(context.Background(), openCommandTimeout)
+					defer cancel()
+					_ = openPathCommand(ctx, path).Run()
+				}(selected.Path)
+				m.status = fmt.Sprintf("Opening %s...", selected.Name)
+			}
+		} else if m.showLargeFiles {
 			if len(m.largeFiles) > 0 {
 				if len(m.largeMultiSelected) > 0 {
 					count := len(m.largeMultiSelected)
@@ -743,7 +994,7 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 						go func(p string) {
 							ctx, cancel := context.WithTimeout(context.Background(), openCommandTimeout)
 							defer cancel()
-							_ = exec.CommandContext(ctx, "open", p).Run()
+							_ = openPathCommand(ctx, p).Run()
 						}(path)
 					}
 					m.status = fmt.Sprintf("Opening %d items...", count)
@@ -752,7 +1003,7 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					go func(path string) {
 						ctx, cancel := context.WithTimeout(context.Background(), openCommandTimeout)
 						defer cancel()
-						_ = exec.CommandContext(ctx, "open", path).Run()
+						_ = openPathCommand(ctx, path).Run()
 					}(selected.Path)
 					m.status = fmt.Sprintf("Opening %s...", selected.Name)
 				}
@@ -768,7 +1019,7 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					go func(p string) {
 						ctx, cancel := context.WithTimeout(context.Background(), openCommandTimeout)
 						defer cancel()
-						_ = exec.CommandContext(ctx, "open", p).Run()
+						_ = openPathCommand(ctx, p).Run()
 					}(path)
 				}
 				m.status = fmt.Sprintf("Opening %d items...", count)
@@ -777,15 +1028,40 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				go func(path string) {
 					ctx, cancel := context.WithTimeout(context.Background(), openCommandTimeout)
 					defer cancel()
-					_ = exec.CommandContext(ctx, "open", path).Run()
+					_ = openPathCommand(ctx, path).Run()
 				}(selected.Path)
 				m.status = fmt.Sprintf("Opening %s...", selected.Name)
 			}
 		}
 	case "f", "F":
-		// Reveal in Finder (multi-select aware).
+		// Reveal in the file manager (multi-select aware).
 		const maxBatchReveal = 20
-		if m.showLargeFiles {
+		if m.showDuplicates {
+			rows := duplicateRows(m.duplicates)
+			if len(m.dupMultiSelected) > 0 {
+				count := len(m.dupMultiSelected)
+				if count > maxBatchReveal {
+					m.status = fmt.Sprintf("Too many items to reveal, max %d, selected %d", maxBatchReveal, count)
+					return m, nil
+				}
+				for path := range m.dupMultiSelected {
+					go func(p string) {
+						ctx, cancel := context.WithTimeout(context.Background(), openCommandTimeout)
+						defer cancel()
+						_ = revealPathCommand(ctx, p).Run()
+					}(path)
+				}
+				m.status = fmt.Sprintf("Showing %d items in %s...", count, fileManagerName)
+			} else if m.duplicateSelected < len(rows) {
+				selected := rows[m.duplicateSelected].file
+				go func(path string) {
+					ctx, cancel := context.WithTimeout(context.Background(), openCommandTimeout)
+					defer cancel()
+					_ = revealPathCommand(ctx, path).Run()
+				}(selected.Path)
+				m.status = fmt.Sprintf("Showing %s in %s...", selected.Name, fileManagerName)
+			}
+		} else if m.showLargeFiles {
 			if len(m.largeFiles) > 0 {
 				if len(m.largeMultiSelected) > 0 {
 					count := len(m.largeMultiSelected)
@@ -797,18 +1073,18 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 						go func(p string) {
 							ctx, cancel := context.WithTimeout(context.Background(), openCommandTimeout)
 							defer cancel()
-							_ = exec.CommandContext(ctx, "open", "-R", p).Run()
+							_ = revealPathCommand(ctx, p).Run()
 						}(path)
 					}
-					m.status = fmt.Sprintf("Showing %d items in Finder...", count)
+					m.status = fmt.Sprintf("Showing %d items in %s...", count, fileManagerName)
 				} else {
 					selected := m.largeFiles[m.largeSelected]
 					go func(path string) {
 						ctx, cancel := context.WithTimeout(context.Background(), openCommandTimeout)
 						defer cancel()
-						_ = exec.CommandContext(ctx, "open", "-R", path).Run()
+						_ = revealPathCommand(ctx, path).Run()
 					}(selected.Path)
-					m.status = fmt.Sprintf("Showing %s in Finder...", selected.Name)
+					m.status = fmt.Sprintf("Showing %s in %s...", selected.Name, fileManagerName)
 				}
 			}
 		} else if len(m.entries) > 0 {
@@ -822,23 +1098,37 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					go func(p string) {
 						ctx, cancel := context.WithTimeout(context.Background(), openCommandTimeout)
 						defer cancel()
-						_ = exec.CommandContext(ctx, "open", "-R", p).Run()
+						_ = revealPathCommand(ctx, p).Run()
 					}(path)
 				}
-				m.status = fmt.Sprintf("Showing %d items in Finder...", count)
+				m.status = fmt.Sprintf("Showing %d items in %s...", count, fileManagerName)
 			} else {
 				selected := m.entries[m.selected]
 				go func(path string) {
 					ctx, cancel := context.WithTimeout(context.Background(), openCommandTimeout)
 					defer cancel()
-					_ = exec.CommandContext(ctx, "open", "-R", path).Run()
+					_ = revealPathCommand(ctx, path).Run()
 				}(selected.Path)
-				m.status = fmt.Sprintf("Showing %s in Finder...", selected.Name)
+				m.status = fmt.Sprintf("Showing %s in %s...", selected.Name, fileManagerName)
 			}
 		}
 	case " ":
 		// Toggle multi-select (paths as keys).
-		if m.showLargeFiles {
+		if m.showDuplicates {
+			rows := duplicateRows(m.duplicates)
+			if m.duplicateSelected < len(rows) {
+				if m.dupMultiSelected == nil {
+					m.dupMultiSelected = make(map[string]bool)
+				}
+				selectedPath := rows[m.duplicateSelected].file.Path
+				if m.dupMultiSelected[selectedPath] {
+					delete(m.dupMultiSelected, selectedPath)
+				} else {
+					m.dupMultiSelected[selectedPath] = true
+				}
+				m.status = m.duplicateSelectionStatus()
+			}
+		} else if m.showLargeFiles {
 			if len(m.largeFiles) > 0 && m.largeSelected < len(m.largeFiles) {
 				if m.largeMultiSelected == nil {
 					m.largeMultiSelected = make(map[string]bool)
@@ -892,7 +1182,22 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 		}
 	case "delete", "backspace":
-		if m.showLargeFiles {
+		if m.showDuplicates {
+			rows := duplicateRows(m.duplicates)
+			if len(m.dupMultiSelected) > 0 {
+				for _, row := range rows {
+					if m.dupMultiSelected[row.file.Path] {
+						m.deleteConfirm = true
+						m.deleteTarget = &dirEntry{Name: row.file.Name, Path: row.file.Path, Size: row.file.Size}
+						break // Only need first one for display
+					}
+				}
+			} else if m.duplicateSelected < len(rows) {
+				selected := rows[m.duplicateSelected].file
+				m.deleteConfirm = true
+				m.deleteTarget = &dirEntry{Name: selected.Name, Path: selected.Path, Size: selected.Size}
+			}
+		} else if m.showLargeFiles {
 			if len(m.largeFiles) > 0 {
 				if len(m.largeMultiSelected) > 0 {
 					m.deleteConfirm = true
@@ -944,11 +1249,74 @@ func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// startDuplicateSearch looks for duplicate files under the current path,
+// replacing any search still running.
+func (m *model) startDuplicateSearch() tea.Cmd {
+	ctx, id := m.dupScans.begin(m.path)
+	atomic.StoreInt64(m.dupFilesSeen, 0)
+	atomic.StoreInt64(m.dupFilesHashed, 0)
+	m.findingDuplicates = true
+	m.duplicates = nil
+	m.duplicatesPath = ""
+	m.duplicateSelected = 0
+	m.duplicateOffset = 0
+	m.dupMultiSelected = make(map[string]bool)
+	m.status = "Finding duplicates..."
+	return tea.Batch(findDuplicatesCmd(ctx, m.path, id, m.dupFilesSeen, m.dupFilesHashed), tickCmd())
+}
+
+func (m *model) leaveDuplicatesView() {
+	if m.findingDuplicates {
+		m.dupScans.stop()
+		m.findingDuplicates = false
+		m.duplicates = nil
+		m.duplicatesPath = ""
+	}
+	m.showDuplicates = false
+	m.dupMultiSelected = make(map[string]bool)
+	m.status = fmt.Sprintf("Scanned %s", humanizeBytes(m.totalSize))
+}
+
+func (m *model) duplicateSelectionStatus() string {
+	if len(m.dupMultiSelected) == 0 {
+		return fmt.Sprintf("%d duplicate sets, %s reclaimable", len(m.duplicates), humanizeBytes(sumDuplicateWaste(m.duplicates)))
+	}
+	var totalSize int64
+	for _, row := range duplicateRows(m.duplicates) {
+		if m.dupMultiSelected[row.file.Path] {
+			totalSize += row.file.Size
+		}
+	}
+	return fmt.Sprintf("%d selected, %s", len(m.dupMultiSelected), humanizeBytes(totalSize))
+}
+
+func (m *model) clampDuplicateSelection() {
+	rows := len(duplicateRows(m.duplicates))
+	if m.duplicateSelected >= rows {
+		m.duplicateSelected = max(rows-1, 0)
+	}
+	if m.duplicateOffset > m.duplicateSelected {
+		m.duplicateOffset = m.duplicateSelected
+	}
+}
+
+// stopOverviewScans cancels the overview's size walks. Entries they were
+// measuring stay pending and are walked again when the overview is shown.
+func (m *model) stopOverviewScans() {
+	m.overviewScans.stop()
+	m.overviewScanningSet = make(map[string]bool)
+	m.overviewScanning = false
+}
+
 func (m *model) switchToOverviewMode() tea.Cmd {
+	m.scans.stop()
 	m.isOverview = true
+	m.incomplete = false
+	m.baseline = nil
 	m.path = "/"
 	m.scanning = false
 	m.showLargeFiles = false
+	m.leaveDuplicatesView()
 	m.largeFiles = nil
 	m.largeSelected = 0
 	m.largeOffset = 0
@@ -971,6 +1339,10 @@ func (m model) enterSelectedDir() (tea.Model, tea.Cmd) {
 	}
 	selected := m.entries[m.selected]
 	if selected.IsDir {
+		if m.scanning {
+			m.scans.stop()
+		}
+		m.stopOverviewScans()
 		if len(m.history) == 0 || m.history[len(m.history)-1].Path != m.path {
 			m.history = append(m.history, snapshotFromModel(m))
 		}
@@ -980,6 +1352,8 @@ func (m model) enterSelectedDir() (tea.Model, tea.Cmd) {
 		m.status = "Scanning..."
 		m.scanning = true
 		m.isOverview = false
+		m.incomplete = false
+		m.baseline = nil
 		m.multiSelected = make(map[string]bool)
 		m.largeMultiSelected = make(map[string]bool)
 
@@ -999,9 +1373,14 @@ func (m model) enterSelectedDir() (tea.Model, tea.Cmd) {
 			m.offset = cached.EntryOffset
 			m.largeSelected = cached.LargeSelected
 			m.largeOffset = cached.LargeOffset
+			m.incomplete = cached.Incomplete
+			m.baseline = cached.Baseline
 			m.clampEntrySelection()
 			m.clampLargeSelection()
 			m.status = fmt.Sprintf("Cached view for %s", displayPath(m.path))
+			if m.incomplete {
+				m.status = fmt.Sprintf("Partial view for %s, press R to rescan", displayPath(m.path))
+			}
 			m.scanning = false
 			return m, nil
 		}
@@ -1065,6 +1444,40 @@ func (m *model) clampLargeSelection() {
 	}
 }
 
+// filterScannedEntries drops empty entries, keeping hard-link-only ones.
+func filterScannedEntries(entries []dirEntry) []dirEntry {
+	filtered := make([]dirEntry, 0, len(entries))
+	for _, e := range entries {
+		if e.Size > 0 || e.Shared > 0 {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered
+}
+
+// storePartialScan keeps the result of a superseded scan in the session
+// cache so revisiting the directory shows what was found so far.
+func (m *model) storePartialScan(msg scanResultMsg) {
+	if msg.err != nil || msg.path == "" || msg.path == m.path {
+		return
+	}
+	if _, ok := m.cache[msg.path]; ok {
+		return
+	}
+	entries := filterScannedEntries(msg.result.Entries)
+	if len(entries) == 0 {
+		return
+	}
+	m.cache[msg.path] = historyEntry{
+		Path:       msg.path,
+		Entries:    entries,
+		LargeFiles: msg.result.LargeFiles,
+		TotalSize:  msg.result.TotalSize,
+		TotalFiles: msg.result.TotalFiles,
+		Incomplete: msg.result.Incomplete,
+	}
+}
+
 func sumKnownEntrySizes(entries []dirEntry) int64 {
 	var total int64
 	for _, entry := range entries {
@@ -1127,14 +1540,15 @@ func (m *model) removePathFromView(path string) {
 	m.clampLargeSelection()
 }
 
-func scanOverviewPathCmd(path string, index int) tea.Cmd {
+func scanOverviewPathCmd(ctx context.Context, id uint64, path string, index int, filesScanned, dirsScanned, bytesScanned *int64) tea.Cmd {
 	return func() tea.Msg {
-		size, err := measureOverviewSize(path)
+		size, err := measureOverviewSize(ctx, path, filesScanned, dirsScanned, bytesScanned)
 		return overviewSizeMsg{
 			Path:  path,
 			Index: index,
 			Size:  size,
 			Err:   err,
+			ID:    id,
 		}
 	}
 }
@@ -0,0 +1,48 @@
+//go:build darwin
+
+package main
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// fileManagerName is shown in status messages when revealing items.
+const fileManagerName = "Finder"
+
+func createOverviewEntries() []dirEntry {
+	home := os.Getenv("HOME")
+	entries := []dirEntry{}
+
+	// Separate Home and ~/Library to avoid double counting.
+	if home != "" {
+		entries = append(entries, dirEntry{Name: "Home", Path: home, IsDir: true, Size: -1})
+
+		userLibrary := filepath.Join(home, "Library")
+		if _, err := os.Stat(userLibrary); err == nil {
+			entries = append(entries, dirEntry{Name: "App Library", Path: userLibrary, IsDir: true, Size: -1})
+		}
+	}
+
+	entries = append(entries,
+		dirEntry{Name: "Applications", Path: "/Applications", IsDir: true, Size: -1},
+		dirEntry{Name: "System Library", Path: "/Library", IsDir: true, Size: -1},
+	)
+
+	// Include Volumes only when real mounts exist.
+	if hasUsefulVolumeMounts("/Volumes") {
+		entries = append(entries, dirEntry{Name: "Volumes", Path: "/Volumes", IsDir: true, Size: -1})
+	}
+
+	return entries
+}
+
+func openPathCommand(ctx context.Context, path string) *exec.Cmd {
+	return exec.CommandContext(ctx, "open", path)
+}
+
+func revealPathCommand(ctx context.Context, path string) *exec.Cmd {
+	return exec.CommandContext(ctx, "open", "-R", path)
+}
@@ -0,0 +1,58 @@
+//go:build linux
+
+package main
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// fileManagerName is shown in status messages when revealing items.
+const fileManagerName = "file manager"
+
+func createOverviewEntries() []dirEntry {
+	home := os.Getenv("HOME")
+	entries := []dirEntry{}
+
+	if home != "" {
+		entries = append(entries, dirEntry{Name: "Home", Path: home, IsDir: true, Size: -1})
+	}
+
+	for _, dir := range []struct{ name, path string }{
+		{"Variable Data", "/var"},
+		{"Optional Software", "/opt"},
+		{"System Software", "/usr"},
+	} {
+		if info, err := os.Stat(dir.path); err == nil && info.IsDir() {
+			entries = append(entries, dirEntry{Name: dir.name, Path: dir.path, IsDir: true, Size: -1})
+		}
+	}
+
+	// Include removable and manual mount points only when something is mounted.
+	mountRoots := []struct{ name, path string }{
+		{"Mounts", "/mnt"},
+		{"Media", "/media"},
+	}
+	if user := os.Getenv("USER"); user != "" {
+		mountRoots = append(mountRoots, struct{ name, path string }{"Removable Media", filepath.Join("/run/media", user)})
+	}
+	for _, root := range mountRoots {
+		if hasUsefulVolumeMounts(root.path) {
+			entries = append(entries, dirEntry{Name: root.name, Path: root.path, IsDir: true, Size: -1})
+		}
+	}
+
+	return entries
+}
+
+func openPathCommand(ctx context.Context, path string) *exec.Cmd {
+	return exec.CommandContext(ctx, "xdg-open", path)
+}
+
+// revealPathCommand opens the containing folder; there is no portable
+// way to ask every Linux file manager to select a single item.
+func revealPathCommand(ctx context.Context, path string) *exec.Cmd {
+	return exec.CommandContext(ctx, "xdg-open", filepath.Dir(path))
+}
@@ -4,13 +4,13 @@ import (
 	"bytes"
 	"container/heap"
 	"context"
+	"errors"
 	"fmt"
 	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
-	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -23,6 +23,100 @@ import (
 
 var scanGroup singleflight.Group
 
+// scanController owns the cancel func of the model's in-flight scan.
+// It is shared by pointer so value copies of the Bubble Tea model agree on
+// which scan is current.
+type scanController struct {
+	mu     sync.Mutex
+	ctx    context.Context
+	cancel context.CancelFunc
+	path   string
+	id     uint64
+}
+
+func newScanController() *scanController {
+	return &scanController{}
+}
+
+// begin cancels any running scan and returns the context and id for a new one.
+func (c *scanController) begin(path string) (context.Context, uint64) {
+	if c == nil {
+		return context.Background(), 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stopLocked()
+	return c.beginLocked(path)
+}
+
+// join returns the context and id of the running scan, starting one for
+// path when none is running. Work spread over several commands, like the
+// overview's size walks, joins one scan so stop cancels all of it.
+func (c *scanController) join(path string) (context.Context, uint64) {
+	if c == nil {
+		return context.Background(), 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cancel != nil {
+		return c.ctx, c.id
+	}
+	return c.beginLocked(path)
+}
+
+func (c *scanController) beginLocked(path string) (context.Context, uint64) {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.ctx, c.cancel = ctx, cancel
+	c.path = path
+	c.id++
+	return ctx, c.id
+}
+
+// stop cancels the running scan, if any. The id moves on so the cancelled
+// scan's result is no longer current and is only kept as a partial scan.
+func (c *scanController) stop() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stopLocked()
+	c.id++
+}
+
+func (c *scanController) stopLocked() {
+	if c.cancel == nil {
+		return
+	}
+	c.cancel()
+	c.ctx, c.cancel = nil, nil
+	// Later scans of the same path must not join the cancelled flight.
+	scanGroup.Forget(c.path)
+}
+
+// isCurrent reports whether id belongs to the most recently started scan.
+func (c *scanController) isCurrent(id uint64) bool {
+	if c == nil {
+		return true
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return id == c.id
+}
+
+// finish releases the cancel func once scan id has delivered its result.
+func (c *scanController) finish(id uint64) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if id == c.id && c.cancel != nil {
+		c.cancel()
+		c.ctx, c.cancel = nil, nil
+	}
+}
+
 // trySend attempts to send an item to a channel with a timeout.
 // Returns true if the item was sent, false if the timeout was reached.
 func trySend[T any](ch chan<- T, item T, timeout time.Duration) bool {
@@ -38,7 +132,11 @@ func trySend[T any](ch chan<- T, item T, timeout time.Duration) bool {
 	}
 }
 
-func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *int64, currentPath *atomic.Value) (scanResult, error) {
+// scanPathConcurrent sizes the children of root. Each finished child is also
+// published to updates (when non-nil) so the view can show it before the
+// scan completes. When ctx is cancelled it stops dispatching work and
+// returns what it has, flagged as incomplete.
+func scanPathConcurrent(ctx context.Context, root string, updates chan<- dirEntry, filesScanned, dirsScanned, bytesScanned *int64, currentPath *atomic.Value) (scanResult, error) {
 	children, err := os.ReadDir(root)
 	if err != nil {
 		return scanResult{}, err
@@ -66,8 +164,8 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 		numWorkers = 1
 	}
 	sem := make(chan struct{}, numWorkers)
-	duSem := make(chan struct{}, min(4, runtime.NumCPU()))        // limits concurrent du processes
-	duQueueSem := make(chan struct{}, min(4, runtime.NumCPU())*2) // limits how many goroutines may be waiting to run du
+	foldSem := make(chan struct{}, min(4, runtime.NumCPU()))        // limits concurrent folded-dir sizers
+	foldQueueSem := make(chan struct{}, min(4, runtime.NumCPU())*2) // limits how many goroutines may be waiting to size a folded dir
 	var wg sync.WaitGroup
 
 	// Collect results via channels.
@@ -82,40 +180,70 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 	entryChan := make(chan dirEntry, entryBufSize)
 	largeFileChan := make(chan fileEntry, maxLargeFiles*2)
 
+	addEntry := func(entry dirEntry) {
+		if entriesHeap.Len() < maxEntries {
+			heap.Push(entriesHeap, entry)
+		} else if entry.Size > (*entriesHeap)[0].Size {
+			heap.Pop(entriesHeap)
+			heap.Push(entriesHeap, entry)
+		}
+	}
+	addLargeFile := func(file fileEntry) {
+		if largeFilesHeap.Len() < maxLargeFiles {
+			heap.Push(largeFilesHeap, file)
+			if largeFilesHeap.Len() == maxLargeFiles {
+				atomic.StoreInt64(&largeFileMinSize, (*largeFilesHeap)[0].Size)
+			}
+		} else if file.Size > (*largeFilesHeap)[0].Size {
+			heap.Pop(largeFilesHeap)
+			heap.Push(largeFilesHeap, file)
+			atomic.StoreInt64(&largeFileMinSize, (*largeFilesHeap)[0].Size)
+		}
+	}
+
 	var collectorWg sync.WaitGroup
 	collectorWg.Add(2)
 	go func() {
 		defer collectorWg.Done()
 		for entry := range entryChan {
-			if entriesHeap.Len() < maxEntries {
-				heap.Push(entriesHeap, entry)
-			} else if entry.Size > (*entriesHeap)[0].Size {
-				heap.Pop(entriesHeap)
-				heap.Push(entriesHeap, entry)
-			}
+			addEntry(entry)
 		}
 	}()
 	go func() {
 		defer collectorWg.Done()
 		for file := range largeFileChan {
-			if largeFilesHeap.Len() < maxLargeFiles {
-				heap.Push(largeFilesHeap, file)
-				if largeFilesHeap.Len() == maxLargeFiles {
-					atomic.StoreInt64(&largeFileMinSize, (*largeFilesHeap)[0].Size)
-				}
-			} else if file.Size > (*largeFilesHeap)[0].Size {
-				heap.Pop(largeFilesHeap)
-				heap.Push(largeFilesHeap, file)
-				atomic.StoreInt64(&largeFileMinSize, (*largeFilesHeap)[0].Size)
-			}
+			addLargeFile(file)
 		}
 	}()
 
+	links := newHardLinkTracker()
+
+	// Entries holding hard links wait for their linked bytes until the walk
+	// is done; the rest go straight to the heap.
+	var (
+		linkedMu sync.Mutex
+		linked   []dirEntry
+	)
+	emit := func(entry dirEntry, scope *hardLinkTracker) {
+		if scope.hasLinks() {
+			linkedMu.Lock()
+			linked = append(linked, entry)
+			linkedMu.Unlock()
+		} else {
+			trySend(entryChan, entry, 100*time.Millisecond)
+		}
+		publishEntry(updates, entry)
+	}
+
 	isRootDir := root == "/"
 	home := os.Getenv("HOME")
 	isHomeDir := home != "" && root == home
 
 	for _, child := range children {
+		if ctx.Err() != nil {
+			break
+		}
+
 		fullPath := filepath.Join(root, child.Name())
 
 		// Skip symlinks to avoid following unexpected targets.
@@ -134,13 +262,13 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 			size := getActualFileSize(fullPath, info)
 			atomic.AddInt64(&total, size)
 
-			trySend(entryChan, dirEntry{
+			emit(dirEntry{
 				Name:       child.Name() + " →",
 				Path:       fullPath,
 				Size:       size,
 				IsDir:      isDir,
 				LastAccess: getLastAccessTimeFromInfo(info),
-			}, 100*time.Millisecond)
+			}, nil)
 			continue
 
 		}
@@ -163,54 +291,51 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 					defer wg.Done()
 					defer func() { <-sem }()
 
+					scope := links.scope(path)
 					var size int64
 					if cached, err := loadStoredOverviewSize(path); err == nil && cached > 0 {
 						size = cached
 					} else if cached, err := loadCacheFromDisk(path); err == nil {
 						size = cached.TotalSize
 					} else {
-						size = calculateDirSizeConcurrent(path, largeFileChan, &largeFileMinSize, duSem, duQueueSem, filesScanned, dirsScanned, bytesScanned, currentPath)
+						size = calculateDirSizeConcurrent(ctx, path, largeFileChan, &largeFileMinSize, foldSem, foldQueueSem, scope, filesScanned, dirsScanned, bytesScanned, currentPath)
 					}
 					atomic.AddInt64(&total, size)
 					atomic.AddInt64(dirsScanned, 1)
 
-					trySend(entryChan, dirEntry{
+					emit(dirEntry{
 						Name:       name,
 						Path:       path,
 						Size:       size,
 						IsDir:      true,
 						LastAccess: time.Time{},
-					}, 100*time.Millisecond)
+						Incomplete: ctx.Err() != nil,
+					}, scope)
 				}(child.Name(), fullPath)
 				continue
 			}
 
 			// Folded dirs: fast size without expanding.
 			if shouldFoldDirWithPath(child.Name(), fullPath) {
-				duQueueSem <- struct{}{}
+				foldQueueSem <- struct{}{}
 				wg.Add(1)
 				go func(name, path string) {
 					defer wg.Done()
-					defer func() { <-duQueueSem }()
-
-					size, err := func() (int64, error) {
-						duSem <- struct{}{}
-						defer func() { <-duSem }()
-						return getDirectorySizeFromDu(path)
-					}()
-					if err != nil || size <= 0 {
-						size = calculateDirSizeFast(path, filesScanned, dirsScanned, bytesScanned, currentPath)
-					}
+					defer func() { <-foldQueueSem }()
+
+					scope := links.scope(path)
+					size := measureFoldedDir(ctx, path, foldSem, scope, filesScanned, dirsScanned, bytesScanned, currentPath)
 					atomic.AddInt64(&total, size)
 					atomic.AddInt64(dirsScanned, 1)
 
-					trySend(entryChan, dirEntry{
+					emit(dirEntry{
 						Name:       name,
 						Path:       path,
 						Size:       size,
 						IsDir:      true,
 						LastAccess: time.Time{},
-					}, 100*time.Millisecond)
+						Incomplete: ctx.Err() != nil,
+					}, scope)
 				}(child.Name(), fullPath)
 				continue
 			}
@@ -221,17 +346,19 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 				defer wg.Done()
 				defer func() { <-sem }()
 
-				size := calculateDirSizeConcurrent(path, largeFileChan, &largeFileMinSize, duSem, duQueueSem, filesScanned, dirsScanned, bytesScanned, currentPath)
+				scope := links.scope(path)
+				size := calculateDirSizeConcurrent(ctx, path, largeFileChan, &largeFileMinSize, foldSem, foldQueueSem, scope, filesScanned, dirsScanned, bytesScanned, currentPath)
 				atomic.AddInt64(&total, size)
 				atomic.AddInt64(dirsScanned, 1)
 
-				trySend(entryChan, dirEntry{
+				emit(dirEntry{
 					Name:       name,
 					Path:       path,
 					Size:       size,
 					IsDir:      true,
 					LastAccess: time.Time{},
-				}, 100*time.Millisecond)
+					Incomplete: ctx.Err() != nil,
+				}, scope)
 			}(child.Name(), fullPath)
 			continue
 		}
@@ -240,22 +367,23 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 		if err != nil {
 			continue
 		}
-		// Actual disk usage for sparse/cloud files.
-		size := getActualFileSize(fullPath, info)
+		// Actual disk usage for sparse/cloud files, once per hard-linked inode.
+		scope := links.scope(fullPath)
+		size := scope.account(fullPath, info, getActualFileSize(fullPath, info))
 		atomic.AddInt64(&total, size)
 		atomic.AddInt64(filesScanned, 1)
 		atomic.AddInt64(bytesScanned, size)
 
-		trySend(entryChan, dirEntry{
+		emit(dirEntry{
 			Name:       child.Name(),
 			Path:       fullPath,
 			Size:       size,
 			IsDir:      false,
 			LastAccess: getLastAccessTimeFromInfo(info),
-		}, 100*time.Millisecond)
+		}, scope)
 
 		// Track large files only.
-		if !shouldSkipFileForLargeTracking(fullPath) {
+		if size > 0 && !shouldSkipFileForLargeTracking(fullPath) {
 			minSize := atomic.LoadInt64(&largeFileMinSize)
 			if size >= minSize {
 				trySend(largeFileChan, fileEntry{Name: child.Name(), Path: fullPath, Size: size}, 100*time.Millisecond)
@@ -270,6 +398,22 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 	close(largeFileChan)
 	collectorWg.Wait()
 
+	// Every link has been seen: give each linked inode to the entry with
+	// its lexically smallest path.
+	shares := links.resolve()
+	for _, entry := range linked {
+		share := shares[entry.Path]
+		entry.Size += share.counted
+		entry.Shared = share.shared
+		total += share.counted
+		addEntry(entry)
+	}
+	for _, file := range links.ownedFiles() {
+		if file.Size > 0 && !shouldSkipFileForLargeTracking(file.Path) && file.Size >= atomic.LoadInt64(&largeFileMinSize) {
+			addLargeFile(file)
+		}
+	}
+
 	// Convert heaps to sorted slices (descending).
 	entries := make([]dirEntry, entriesHeap.Len())
 	for i := len(entries) - 1; i >= 0; i-- {
@@ -281,9 +425,13 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 		largeFiles[i] = heap.Pop(largeFilesHeap).(fileEntry)
 	}
 
+	incomplete := ctx.Err() != nil
+
 	// Use Spotlight for large files when it expands the list.
-	if spotlightFiles := findLargeFilesWithSpotlight(root, spotlightMinFileSize); len(spotlightFiles) > len(largeFiles) {
-		largeFiles = spotlightFiles
+	if !incomplete {
+		if spotlightFiles := findLargeFilesWithSpotlight(root, spotlightMinFileSize); len(spotlightFiles) > len(largeFiles) {
+			largeFiles = spotlightFiles
+		}
 	}
 
 	return scanResult{
@@ -291,9 +439,22 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 		LargeFiles: largeFiles,
 		TotalSize:  total,
 		TotalFiles: atomic.LoadInt64(filesScanned),
+		Incomplete: incomplete,
 	}, nil
 }
 
+// publishEntry forwards a finished entry to the live view without ever
+// blocking the scan; the final result is authoritative if updates are dropped.
+func publishEntry(updates chan<- dirEntry, entry dirEntry) {
+	if updates == nil {
+		return
+	}
+	select {
+	case updates <- entry:
+	default:
+	}
+}
+
 func shouldFoldDirWithPath(name, path string) bool {
 	if foldDirs[name] {
 		return true
@@ -318,132 +479,6 @@ func shouldSkipFileForLargeTracking(path string) bool {
 	return skipExtensions[ext]
 }
 
-// calculateDirSizeFast performs concurrent dir sizing using os.ReadDir.
-func calculateDirSizeFast(root string, filesScanned, dirsScanned, bytesScanned *int64, currentPath *atomic.Value) int64 {
-	var total int64
-	var wg sync.WaitGroup
-
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
-	defer cancel()
-
-	concurrency := min(runtime.NumCPU()*4, 64)
-	sem := make(chan struct{}, concurrency)
-
-	var walk func(string)
-	walk = func(dirPath string) {
-		select {
-		case <-ctx.Done():
-			return
-		default:
-		}
-
-		if currentPath != nil && atomic.LoadInt64(filesScanned)%int64(batchUpdateSize) == 0 {
-			currentPath.Store(dirPath)
-		}
-
-		entries, err := os.ReadDir(dirPath)
-		if err != nil {
-			return
-		}
-
-		var localBytes, localFiles int64
-
-		for _, entry := range entries {
-			if entry.IsDir() {
-				subDir := filepath.Join(dirPath, entry.Name())
-				sem <- struct{}{}
-				wg.Add(1)
-				go func(p string) {
-					defer wg.Done()
-					defer func() { <-sem }()
-					walk(p)
-				}(subDir)
-				atomic.AddInt64(dirsScanned, 1)
-			} else {
-				info, err := entry.Info()
-				if err == nil {
-					size := getActualFileSize(filepath.Join(dirPath, entry.Name()), info)
-					localBytes += size
-					localFiles++
-				}
-			}
-		}
-
-		if localBytes > 0 {
-			atomic.AddInt64(&total, localBytes)
-			atomic.AddInt64(bytesScanned, localBytes)
-		}
-		if localFiles > 0 {
-			atomic.AddInt64(filesScanned, localFiles)
-		}
-	}
-
-	walk(root)
-	wg.Wait()
-
-	return total
-}
-
-// Use Spotlight (mdfind) to quickly find large files.
-func findLargeFilesWithSpotlight(root string, minSize int64) []fileEntry {
-	query := fmt.Sprintf("kMDItemFSSize >= %d", minSize)
-
-	ctx, cancel := context.WithTimeout(context.Background(), mdlsTimeout)
-	defer cancel()
-
-	cmd := exec.CommandContext(ctx, "mdfind", "-onlyin", root, query)
-	output, err := cmd.Output()
-	if err != nil {
-		return nil
-	}
-
-	var files []fileEntry
-
-	for line := range strings.Lines(strings.TrimSpace(string(output))) {
-		if line == "" {
-			continue
-		}
-
-		// Filter code files first (cheap).
-		if shouldSkipFileForLargeTracking(line) {
-			continue
-		}
-
-		// Filter folded directories (cheap string check).
-		if isInFoldedDir(line) {
-			continue
-		}
-
-		info, err := os.Lstat(line)
-		if err != nil {
-			continue
-		}
-
-		if info.IsDir() || info.Mode()&os.ModeSymlink != 0 {
-			continue
-		}
-
-		// Actual disk usage for sparse/cloud files.
-		actualSize := getActualFileSize(line, info)
-		files = append(files, fileEntry{
-			Name: filepath.Base(line),
-			Path: line,
-			Size: actualSize,
-		})
-	}
-
-	// Sort by size (descending).
-	sort.Slice(files, func(i, j int) bool {
-		return files[i].Size > files[j].Size
-	})
-
-	if len(files) > maxLargeFiles {
-		files = files[:maxLargeFiles]
-	}
-
-	return files
-}
-
 // isInFoldedDir checks if a path is inside a folded directory.
 func isInFoldedDir(path string) bool {
 	parts := strings.SplitSeq(path, string(os.PathSeparator))
@@ -455,7 +490,9 @@ func isInFoldedDir(path string) bool {
 	return false
 }
 
-func calculateDirSizeConcurrent(root string, largeFileChan chan<- fileEntry, largeFileMinSize *int64, duSem, duQueueSem chan struct{}, filesScanned, dirsScanned, bytesScanned *int64, currentPath *atomic.Value) int64 {
+// calculateDirSizeConcurrent returns the size of root, leaving hard-linked
+// files to links.
+func calculateDirSizeConcurrent(ctx context.Context, root string, largeFileChan chan<- fileEntry, largeFileMinSize *int64, foldSem, foldQueueSem chan struct{}, links *hardLinkTracker, filesScanned, dirsScanned, bytesScanned *int64, currentPath *atomic.Value) int64 {
 	children, err := os.ReadDir(root)
 	if err != nil {
 		return 0
@@ -469,6 +506,10 @@ func calculateDirSizeConcurrent(root string, largeFileChan chan<- fileEntry, lar
 	sem := make(chan struct{}, maxConcurrent)
 
 	for _, child := range children {
+		if ctx.Err() != nil {
+			break
+		}
+
 		fullPath := filepath.Join(root, child.Name())
 
 		if child.Type()&fs.ModeSymlink != 0 {
@@ -477,7 +518,7 @@ func calculateDirSizeConcurrent(root string, largeFileChan chan<- fileEntry, lar
 				continue
 			}
 			size := getActualFileSize(fullPath, info)
-			total += size
+			atomic.AddInt64(&total, size)
 			atomic.AddInt64(filesScanned, 1)
 			atomic.AddInt64(bytesScanned, size)
 			continue
@@ -485,22 +526,13 @@ func calculateDirSizeConcurrent(root string, largeFileChan chan<- fileEntry, lar
 
 		if child.IsDir() {
 			if shouldFoldDirWithPath(child.Name(), fullPath) {
-				duQueueSem <- struct{}{}
+				foldQueueSem <- struct{}{}
 				wg.Add(1)
 				go func(path string) {
 					defer wg.Done()
-					defer func() { <-duQueueSem }()
-
-					size, err := func() (int64, error) {
-						duSem <- struct{}{}
-						defer func() { <-duSem }()
-						return getDirectorySizeFromDu(path)
-					}()
-					if err != nil || size <= 0 {
-						size = calculateDirSizeFast(path, filesScanned, dirsScanned, bytesScanned, currentPath)
-					} else {
-						atomic.AddInt64(bytesScanned, size)
-					}
+					defer func() { <-foldQueueSem }()
+
+					size := measureFoldedDir(ctx, path, foldSem, links, filesScanned, dirsScanned, bytesScanned, currentPath)
 					atomic.AddInt64(&total, size)
 					atomic.AddInt64(dirsScanned, 1)
 				}(fullPath)
@@ -513,7 +545,7 @@ func calculateDirSizeConcurrent(root string, largeFileChan chan<- fileEntry, lar
 				defer wg.Done()
 				defer func() { <-sem }()
 
-				size := calculateDirSizeConcurrent(path, largeFileChan, largeFileMinSize, duSem, duQueueSem, filesScanned, dirsScanned, bytesScanned, currentPath)
+				size := calculateDirSizeConcurrent(ctx, path, largeFileChan, largeFileMinSize, foldSem, foldQueueSem, links, filesScanned, dirsScanned, bytesScanned, currentPath)
 				atomic.AddInt64(&total, size)
 				atomic.AddInt64(dirsScanned, 1)
 			}(fullPath)
@@ -525,12 +557,12 @@ func calculateDirSizeConcurrent(root string, largeFileChan chan<- fileEntry, lar
 			continue
 		}
 
-		size := getActualFileSize(fullPath, info)
-		total += size
+		size := links.account(fullPath, info, getActualFileSize(fullPath, info))
+		atomic.AddInt64(&total, size)
 		atomic.AddInt64(filesScanned, 1)
 		atomic.AddInt64(bytesScanned, size)
 
-		if !shouldSkipFileForLargeTracking(fullPath) && largeFileMinSize != nil {
+		if size > 0 && !shouldSkipFileForLargeTracking(fullPath) && largeFileMinSize != nil {
 			minSize := atomic.LoadInt64(largeFileMinSize)
 			if size >= minSize {
 				trySend(largeFileChan, fileEntry{Name: child.Name(), Path: fullPath, Size: size}, 100*time.Millisecond)
@@ -549,7 +581,9 @@ func calculateDirSizeConcurrent(root string, largeFileChan chan<- fileEntry, lar
 
 // measureOverviewSize calculates the size of a directory using multiple strategies.
 // When scanning Home, it excludes ~/Library to avoid duplicate counting.
-func measureOverviewSize(path string) (int64, error) {
+// Each measurement gives up after overviewTimeout; progress goes to the
+// counters, which may be nil.
+func measureOverviewSize(ctx context.Context, path string, filesScanned, dirsScanned, bytesScanned *int64) (int64, error) {
 	if path == "" {
 		return 0, fmt.Errorf("empty path")
 	}
@@ -570,9 +604,40 @@ func measureOverviewSize(path string) (int64, error) {
 		excludePath = filepath.Join(home, "Library")
 	}
 
-	if duSize, err := getDirectorySizeFromDuWithExclude(path, excludePath); err == nil && duSize > 0 {
-		_ = storeOverviewSize(path, duSize)
-		return duSize, nil
+	ctx, cancel := context.WithTimeout(ctx, overviewTimeout)
+	defer cancel()
+
+	if useDuFastPath() {
+		if duSize, err := getDirectorySizeFromDuWithExclude(ctx, path, excludePath); err == nil && duSize > 0 {
+			_ = storeOverviewSize(path, duSize)
+			return duSize, nil
+		}
+	}
+
+	links := newHardLinkTracker()
+	nativeSize, err := measureDirSizeNative(ctx, path, nativeSizeOptions{
+		exclude:      excludePath,
+		links:        links,
+		filesScanned: filesScanned,
+		dirsScanned:  dirsScanned,
+		bytesScanned: bytesScanned,
+	})
+	if err == nil && nativeSize > 0 {
+		nativeSize += links.linkedBytes()
+		_ = storeOverviewSize(path, nativeSize)
+		return nativeSize, nil
+	}
+
+	// A timed-out walk would time out again walking logically, and a
+	// cancelled one is no longer wanted at all.
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return 0, ctx.Err()
+	}
+	if ctx.Err() != nil {
+		if cached, err := loadCacheFromDisk(path); err == nil {
+			return cached.TotalSize, nil
+		}
+		return 0, fmt.Errorf("measuring %s: %w", path, ctx.Err())
 	}
 
 	if logicalSize, err := getDirectoryLogicalSizeWithExclude(path, excludePath); err == nil && logicalSize > 0 {
@@ -588,17 +653,17 @@ func measureOverviewSize(path string) (int64, error) {
 	return 0, fmt.Errorf("unable to measure directory size with fast methods")
 }
 
-func getDirectorySizeFromDu(path string) (int64, error) {
-	return getDirectorySizeFromDuWithExclude(path, "")
+func getDirectorySizeFromDu(ctx context.Context, path string) (int64, error) {
+	return getDirectorySizeFromDuWithExclude(ctx, path, "")
 }
 
-func getDirectorySizeFromDuWithExclude(path string, excludePath string) (int64, error) {
+func getDirectorySizeFromDuWithExclude(ctx context.Context, path string, excludePath string) (int64, error) {
 	runDuSize := func(target string) (int64, error) {
 		if _, err := os.Stat(target); err != nil {
 			return 0, err
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), duTimeout)
+		ctx, cancel := context.WithTimeout(ctx, duTimeout)
 		defer cancel()
 
 		cmd := exec.CommandContext(ctx, "du", "-skP", target)
@@ -653,6 +718,7 @@ func getDirectorySizeFromDuWithExclude(path string, excludePath string) (int64,
 
 func getDirectoryLogicalSizeWithExclude(path string, excludePath string) (int64, error) {
 	var total int64
+	links := newHardLinkTracker()
 	err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
 		if err != nil {
 			if os.IsPermission(err) {
@@ -671,13 +737,13 @@ func getDirectoryLogicalSizeWithExclude(path string, excludePath string) (int64,
 		if err != nil {
 			return nil
 		}
-		total += getActualFileSize(p, info)
+		total += links.account(p, info, getActualFileSize(p, info))
 		return nil
 	})
 	if err != nil && err != filepath.SkipDir {
 		return 0, err
 	}
-	return total, nil
+	return total + links.linkedBytes(), nil
 }
 
 func getActualFileSize(_ string, info fs.FileInfo) int64 {
@@ -700,11 +766,3 @@ func getLastAccessTime(path string) time.Time {
 	}
 	return getLastAccessTimeFromInfo(info)
 }
-
-func getLastAccessTimeFromInfo(info fs.FileInfo) time.Time {
-	stat, ok := info.Sys().(*syscall.Stat_t)
-	if !ok {
-		return time.Time{}
-	}
-	return time.Unix(stat.Atimespec.Sec, stat.Atimespec.Nsec)
-}
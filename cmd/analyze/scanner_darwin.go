@@ -0,0 +1,84 @@
+//go:build darwin
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Use Spotlight (mdfind) to quickly find large files.
+func findLargeFilesWithSpotlight(root string, minSize int64) []fileEntry {
+	query := fmt.Sprintf("kMDItemFSSize >= %d", minSize)
+
+	ctx, cancel := context.WithTimeout(context.Background(), mdlsTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "mdfind", "-onlyin", root, query)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+
+	var files []fileEntry
+
+	for line := range strings.Lines(strings.TrimSpace(string(output))) {
+		if line == "" {
+			continue
+		}
+
+		// Filter code files first (cheap).
+		if shouldSkipFileForLargeTracking(line) {
+			continue
+		}
+
+		// Filter folded directories (cheap string check).
+		if isInFoldedDir(line) {
+			continue
+		}
+
+		info, err := os.Lstat(line)
+		if err != nil {
+			continue
+		}
+
+		if info.IsDir() || info.Mode()&os.ModeSymlink != 0 {
+			continue
+		}
+
+		// Actual disk usage for sparse/cloud files.
+		actualSize := getActualFileSize(line, info)
+		files = append(files, fileEntry{
+			Name: filepath.Base(line),
+			Path: line,
+			Size: actualSize,
+		})
+	}
+
+	// Sort by size (descending).
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Size > files[j].Size
+	})
+
+	if len(files) > maxLargeFiles {
+		files = files[:maxLargeFiles]
+	}
+
+	return files
+}
+
+func getLastAccessTimeFromInfo(info fs.FileInfo) time.Time {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return time.Time{}
+	}
+	return time.Unix(stat.Atimespec.Sec, stat.Atimespec.Nsec)
+}
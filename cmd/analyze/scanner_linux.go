@@ -0,0 +1,23 @@
+//go:build linux
+
+package main
+
+import (
+	"io/fs"
+	"syscall"
+	"time"
+)
+
+// findLargeFilesWithSpotlight has no index to query on Linux, so the
+// heap-based results collected during the walk are used as-is.
+func findLargeFilesWithSpotlight(_ string, _ int64) []fileEntry {
+	return nil
+}
+
+func getLastAccessTimeFromInfo(info fs.FileInfo) time.Time {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return time.Time{}
+	}
+	return time.Unix(int64(stat.Atim.Sec), int64(stat.Atim.Nsec)) //nolint:unconvert // Timespec fields are int32 on 32-bit arches
+}
@@ -1,8 +1,10 @@
 package main
 
 import (
+	"context"
 	"os"
 	"path/filepath"
+	"sync/atomic"
 	"testing"
 )
 
@@ -43,3 +45,79 @@ func TestGetDirectoryLogicalSizeWithExclude(t *testing.T) {
 		t.Fatalf("expected 400 bytes when excluding top-level Library, got %d", excluding)
 	}
 }
+
+func TestScanPathConcurrentCancelled(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	root := t.TempDir()
+	writeFileWithSize(t, filepath.Join(root, "sub", "file.bin"), 128)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var filesScanned, dirsScanned, bytesScanned int64
+	current := &atomic.Value{}
+	current.Store("")
+
+	result, err := scanPathConcurrent(ctx, root, nil, &filesScanned, &dirsScanned, &bytesScanned, current)
+	if err != nil {
+		t.Fatalf("scanPathConcurrent returned error: %v", err)
+	}
+	if !result.Incomplete {
+		t.Fatal("expected cancelled scan to be marked incomplete")
+	}
+}
+
+func TestScanControllerBeginCancelsPrevious(t *testing.T) {
+	scans := newScanController()
+
+	first, firstID := scans.begin("/tmp/a")
+	second, secondID := scans.begin("/tmp/b")
+
+	if first.Err() == nil {
+		t.Fatal("expected first scan to be cancelled by the second")
+	}
+	if second.Err() != nil {
+		t.Fatalf("second scan should still be running, err=%v", second.Err())
+	}
+	if scans.isCurrent(firstID) || !scans.isCurrent(secondID) {
+		t.Fatalf("isCurrent mismatch: first=%v second=%v", scans.isCurrent(firstID), scans.isCurrent(secondID))
+	}
+
+	scans.stop()
+	if second.Err() == nil {
+		t.Fatal("expected stop to cancel the running scan")
+	}
+	if scans.isCurrent(secondID) {
+		t.Fatal("a stopped scan must no longer be current")
+	}
+}
+
+func TestScanPathConcurrentPublishesEntries(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	root := t.TempDir()
+	writeFileWithSize(t, filepath.Join(root, "alpha", "a.bin"), 64)
+	writeFileWithSize(t, filepath.Join(root, "beta", "b.bin"), 32)
+	writeFileWithSize(t, filepath.Join(root, "top.bin"), 16)
+
+	updates := make(chan dirEntry, 16)
+	var filesScanned, dirsScanned, bytesScanned int64
+	current := &atomic.Value{}
+	current.Store("")
+
+	if _, err := scanPathConcurrent(context.Background(), root, updates, &filesScanned, &dirsScanned, &bytesScanned, current); err != nil {
+		t.Fatalf("scanPathConcurrent returned error: %v", err)
+	}
+	close(updates)
+
+	seen := map[string]bool{}
+	for entry := range updates {
+		seen[entry.Name] = true
+	}
+	for _, name := range []string{"alpha", "beta", "top.bin"} {
+		if !seen[name] {
+			t.Fatalf("expected %s to be published, got %v", name, seen)
+		}
+	}
+}
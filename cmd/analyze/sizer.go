@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// nativeSizeOptions configures measureDirSizeNative. Nil counters are ignored.
+type nativeSizeOptions struct {
+	exclude      string // Absolute path skipped entirely, e.g. ~/Library under Home
+	links        *hardLinkTracker
+	filesScanned *int64
+	dirsScanned  *int64
+	bytesScanned *int64
+	currentPath  *atomic.Value
+}
+
+// measureDirSizeNative walks root in parallel without following symlinks and
+// sums getActualFileSize per file, the same rule the expanded scan uses, so a
+// folded directory reports what it would total once opened. Hard-linked
+// files are left to the tracker, whose owner adds them once the walk is done
+// (see hardLinkTracker.resolve).
+// It returns ctx.Err() alongside the partial size when cancelled.
+func measureDirSizeNative(ctx context.Context, root string, opts nativeSizeOptions) (int64, error) {
+	rootInfo, err := os.Lstat(root)
+	if err != nil {
+		return 0, err
+	}
+	if !rootInfo.IsDir() {
+		size := opts.links.account(root, rootInfo, getActualFileSize(root, rootInfo))
+		addCounter(opts.filesScanned, 1)
+		addCounter(opts.bytesScanned, size)
+		return size, nil
+	}
+
+	var total int64
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, min(runtime.NumCPU()*4, 64))
+
+	var walk func(string)
+	walk = func(dirPath string) {
+		if ctx.Err() != nil {
+			return
+		}
+
+		entries, err := os.ReadDir(dirPath)
+		if err != nil {
+			return
+		}
+
+		var localBytes, localFiles int64
+		for _, entry := range entries {
+			fullPath := filepath.Join(dirPath, entry.Name())
+			if opts.exclude != "" && fullPath == opts.exclude {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+
+			if entry.IsDir() {
+				addCounter(opts.dirsScanned, 1)
+				// Spawn when a worker slot is free, otherwise recurse inline so
+				// walkers never block waiting on each other.
+				select {
+				case sem <- struct{}{}:
+					wg.Add(1)
+					go func(p string) {
+						defer wg.Done()
+						defer func() { <-sem }()
+						walk(p)
+					}(fullPath)
+				default:
+					walk(fullPath)
+				}
+				continue
+			}
+
+			localBytes += opts.links.account(fullPath, info, getActualFileSize(fullPath, info))
+			localFiles++
+		}
+
+		atomic.AddInt64(&total, localBytes)
+		addCounter(opts.bytesScanned, localBytes)
+		if localFiles > 0 {
+			files := addCounter(opts.filesScanned, localFiles)
+			if opts.currentPath != nil && files%int64(batchUpdateSize) < localFiles {
+				opts.currentPath.Store(dirPath)
+			}
+		}
+	}
+
+	walk(root)
+	wg.Wait()
+
+	return atomic.LoadInt64(&total), ctx.Err()
+}
+
+// measureFoldedDir sizes a folded directory without expanding it, trying
+// du first only when the fast path is enabled.
+func measureFoldedDir(ctx context.Context, path string, foldSem chan struct{}, links *hardLinkTracker, filesScanned, dirsScanned, bytesScanned *int64, currentPath *atomic.Value) int64 {
+	foldSem <- struct{}{}
+	defer func() { <-foldSem }()
+
+	if useDuFastPath() {
+		if size, err := getDirectorySizeFromDu(ctx, path); err == nil && size > 0 {
+			atomic.AddInt64(bytesScanned, size)
+			return size
+		}
+	}
+
+	size, _ := measureDirSizeNative(ctx, path, nativeSizeOptions{
+		links:        links,
+		filesScanned: filesScanned,
+		dirsScanned:  dirsScanned,
+		bytesScanned: bytesScanned,
+		currentPath:  currentPath,
+	})
+	return size
+}
+
+// useDuFastPath reports whether MO_ANALYZE_USE_DU=1 asks for the external du
+// binary. It is faster on some network volumes but cannot share hard-link
+// accounting with the rest of the scan.
+func useDuFastPath() bool {
+	return os.Getenv("MO_ANALYZE_USE_DU") == "1"
+}
+
+func addCounter(counter *int64, delta int64) int64 {
+	if counter == nil {
+		return 0
+	}
+	return atomic.AddInt64(counter, delta)
+}
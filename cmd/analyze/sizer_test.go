@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+func TestMeasureDirSizeNativeMatchesFileSizes(t *testing.T) {
+	root := t.TempDir()
+	writeFileWithSize(t, filepath.Join(root, "a.bin"), 10000)
+	writeFileWithSize(t, filepath.Join(root, "nested", "deep", "b.bin"), 5000)
+	writeFileWithSize(t, filepath.Join(root, "Library", "skip.bin"), 50000)
+
+	var expected int64
+	_ = filepath.WalkDir(root, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == filepath.Join(root, "Library") {
+			return filepath.SkipDir
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err == nil {
+			expected += getActualFileSize(p, info)
+		}
+		return nil
+	})
+
+	var files, dirs, bytes int64
+	size, err := measureDirSizeNative(context.Background(), root, nativeSizeOptions{
+		exclude:      filepath.Join(root, "Library"),
+		links:        newHardLinkTracker(),
+		filesScanned: &files,
+		dirsScanned:  &dirs,
+		bytesScanned: &bytes,
+	})
+	if err != nil {
+		t.Fatalf("measureDirSizeNative: %v", err)
+	}
+	if size != expected {
+		t.Fatalf("expected %d bytes, got %d", expected, size)
+	}
+	if files != 2 {
+		t.Fatalf("expected 2 files counted, got %d", files)
+	}
+	if dirs != 2 {
+		t.Fatalf("expected 2 subdirectories counted, got %d", dirs)
+	}
+	if bytes != size {
+		t.Fatalf("expected bytesScanned %d, got %d", size, bytes)
+	}
+}
+
+func TestMeasureDirSizeNativeHardLinks(t *testing.T) {
+	root := t.TempDir()
+	original := filepath.Join(root, "one", "blob")
+	writeFileWithSize(t, original, 8192)
+	if err := os.MkdirAll(filepath.Join(root, "two"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.Link(original, filepath.Join(root, "two", "blob")); err != nil {
+		t.Skipf("hard links unsupported: %v", err)
+	}
+
+	links := newHardLinkTracker()
+	withLinks, err := measureDirSizeNative(context.Background(), root, nativeSizeOptions{links: links})
+	if err != nil {
+		t.Fatalf("measureDirSizeNative: %v", err)
+	}
+	withLinks += links.linkedBytes()
+	withoutLinks, _ := measureDirSizeNative(context.Background(), root, nativeSizeOptions{})
+
+	blob := actualSizeOf(t, original)
+	if withoutLinks-withLinks != blob {
+		t.Fatalf("expected hard link to be counted once: with=%d without=%d blob=%d", withLinks, withoutLinks, blob)
+	}
+	if shared := links.resolve()[""].shared; shared != 0 {
+		t.Fatalf("both links are inside the measured tree, expected nothing shared, got %d", shared)
+	}
+}
+
+func TestMeasureDirSizeNativeCancelled(t *testing.T) {
+	root := t.TempDir()
+	writeFileWithSize(t, filepath.Join(root, "x", "file"), 100)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := measureDirSizeNative(ctx, root, nativeSizeOptions{}); err == nil {
+		t.Fatal("expected cancellation error")
+	}
+}
+
+func TestMeasureDirSizeNativeMatchesExpandedScan(t *testing.T) {
+	root := t.TempDir()
+	for i := range 20 {
+		writeFileWithSize(t, filepath.Join(root, "dir"+strconv.Itoa(i%4), "f"+strconv.Itoa(i)), 1000*(i+1))
+	}
+
+	folded, err := measureDirSizeNative(context.Background(), root, nativeSizeOptions{links: newHardLinkTracker()})
+	if err != nil {
+		t.Fatalf("measureDirSizeNative: %v", err)
+	}
+	var files, dirs, bytes int64
+	current := &atomic.Value{}
+	current.Store("")
+	expanded, err := scanPathConcurrent(context.Background(), root, nil, &files, &dirs, &bytes, current)
+	if err != nil {
+		t.Fatalf("scanPathConcurrent: %v", err)
+	}
+	if folded != expanded.TotalSize {
+		t.Fatalf("folded size %d differs from expanded scan %d", folded, expanded.TotalSize)
+	}
+}
+
+func actualSizeOf(t *testing.T, path string) int64 {
+	t.Helper()
+	info, err := os.Lstat(path)
+	if err != nil {
+		t.Fatalf("lstat %s: %v", path, err)
+	}
+	return getActualFileSize(path, info)
+}
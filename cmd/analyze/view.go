@@ -1,11 +1,10 @@
-//go:build darwin
-
 package main
 
 import (
 	"fmt"
 	"strings"
 	"sync/atomic"
+	"time"
 )
 
 // View renders the TUI.
@@ -24,15 +23,20 @@ func (m model) View() string {
 				}
 			}
 
+			files, bytes := atomic.LoadInt64(m.overviewFilesScanned), atomic.LoadInt64(m.overviewBytesScanned)
 			if allPending {
-				fmt.Fprintf(&b, "%s%s%s%s Analyzing disk usage, please wait...%s\n",
+				fmt.Fprintf(&b, "%s%s%s%s Analyzing disk usage: %s%s files%s, %s%s%s, please wait...%s\n",
 					colorCyan, colorBold,
 					spinnerFrames[m.spinner],
-					colorReset, colorReset)
+					colorReset,
+					colorYellow, formatNumber(files), colorReset,
+					colorGreen, humanizeBytes(bytes), colorReset,
+					colorReset)
 				return b.String()
 			} else {
 				fmt.Fprintf(&b, "%sSelect a location to explore:%s  ", colorGray, colorReset)
-				fmt.Fprintf(&b, "%s%s%s%s %s\n\n", colorCyan, colorBold, spinnerFrames[m.spinner], colorReset, m.status)
+				fmt.Fprintf(&b, "%s%s%s%s %s %s(%s files)%s\n\n", colorCyan, colorBold, spinnerFrames[m.spinner], colorReset, m.status,
+					colorGray, formatNumber(files), colorReset)
 			}
 		} else {
 			hasPending := false
@@ -53,6 +57,13 @@ func (m model) View() string {
 		fmt.Fprintf(&b, "%sAnalyze Disk%s  %s%s%s", colorPurpleBold, colorReset, colorGray, displayPath(m.path), colorReset)
 		if !m.scanning {
 			fmt.Fprintf(&b, "  |  Total: %s", humanizeBytes(m.totalSize))
+			if m.incomplete {
+				fmt.Fprintf(&b, " %s(partial)%s", colorYellow, colorReset)
+			} else if m.baseline != nil && m.baseline.TotalSize != m.totalSize {
+				fmt.Fprintf(&b, " %s(%s since %s)%s", colorGray,
+					formatSizeDelta(m.totalSize-m.baseline.TotalSize),
+					formatSinceLabel(m.baseline.ScannedAt, time.Now()), colorReset)
+			}
 		}
 		fmt.Fprintf(&b, "\n\n")
 	}
@@ -72,6 +83,17 @@ func (m model) View() string {
 		return b.String()
 	}
 
+	if m.findingDuplicates {
+		fmt.Fprintf(&b, "%s%s%s%s Finding duplicates: %s%s files%s checked, %s%s%s hashed\n",
+			colorCyan, colorBold,
+			spinnerFrames[m.spinner],
+			colorReset,
+			colorYellow, formatNumber(atomic.LoadInt64(m.dupFilesSeen)), colorReset,
+			colorYellow, formatNumber(atomic.LoadInt64(m.dupFilesHashed)), colorReset)
+
+		return b.String()
+	}
+
 	if m.scanning {
 		filesScanned, dirsScanned, bytesScanned := m.getScanProgress()
 
@@ -107,10 +129,17 @@ func (m model) View() string {
 			}
 		}
 
+		if len(m.liveEntries) > 0 {
+			fmt.Fprintln(&b)
+			renderLiveEntries(&b, m.liveEntries, calculateViewport(m.height, false)-2, calculateNameWidth(m.width))
+		}
+
 		return b.String()
 	}
 
-	if m.showLargeFiles {
+	if m.showDuplicates {
+		m.renderDuplicates(&b)
+	} else if m.showLargeFiles {
 		if len(m.largeFiles) == 0 {
 			fmt.Fprintln(&b, "  No large files found")
 		} else {
@@ -247,6 +276,27 @@ func (m model) View() string {
 					}
 				}
 
+				// Only reserve the shared column when hard links were found.
+				showShared := false
+				for _, entry := range m.entries {
+					if entry.Shared > 0 {
+						showShared = true
+						break
+					}
+				}
+
+				// Growth since the previous recorded scan, sized to the widest label.
+				var deltas []string
+				deltaWidth := 0
+				if m.baseline != nil && !m.incomplete {
+					now := time.Now()
+					deltas = make([]string, len(m.entries))
+					for i, entry := range m.entries {
+						deltas[i] = formatEntryDelta(m.baseline, entry.Path, entry.Size, now)
+						deltaWidth = max(deltaWidth, displayWidth(deltas[i]))
+					}
+				}
+
 				viewport := calculateViewport(m.height, false)
 				nameWidth := calculateNameWidth(m.width)
 				start := max(m.offset, 0)
@@ -305,8 +355,28 @@ func (m model) View() string {
 
 					displayIndex := idx + 1
 
+					sizeSegment := fmt.Sprintf("%s%10s%s", sizeColor, size, colorReset)
+					if showShared {
+						sharedText := ""
+						if entry.Shared > 0 {
+							sharedText = humanizeBytes(entry.Shared) + " shared"
+						}
+						sizeSegment += fmt.Sprintf("  %s%16s%s", colorGray, sharedText, colorReset)
+					}
+					if deltaWidth > 0 {
+						deltaColor := colorGray
+						if strings.HasPrefix(deltas[idx], "+") {
+							deltaColor = colorYellow
+						} else if strings.HasPrefix(deltas[idx], "-") {
+							deltaColor = colorGreen
+						}
+						sizeSegment += fmt.Sprintf("  %s%-*s%s", deltaColor, deltaWidth, deltas[idx], colorReset)
+					}
+
 					var hintLabel string
-					if entry.IsDir && isCleanableDir(entry.Path) {
+					if entry.Incomplete {
+						hintLabel = fmt.Sprintf("%sincomplete%s", colorYellow, colorReset)
+					} else if entry.IsDir && isCleanableDir(entry.Path) {
 						hintLabel = fmt.Sprintf("%s🧹%s", colorYellow, colorReset)
 					} else {
 						lastAccess := entry.LastAccess
@@ -319,13 +389,13 @@ func (m model) View() string {
 					}
 
 					if hintLabel == "" {
-						fmt.Fprintf(&b, "%s%s %s%2d.%s %s %s%s%s  |  %s %s%10s%s\n",
+						fmt.Fprintf(&b, "%s%s %s%2d.%s %s %s%s%s  |  %s %s\n",
 							entryPrefix, selectIcon, numColor, displayIndex, colorReset, bar, percentColor, percentStr, colorReset,
-							nameSegment, sizeColor, size, colorReset)
+							nameSegment, sizeSegment)
 					} else {
-						fmt.Fprintf(&b, "%s%s %s%2d.%s %s %s%s%s  |  %s %s%10s%s  %s\n",
+						fmt.Fprintf(&b, "%s%s %s%2d.%s %s %s%s%s  |  %s %s  %s\n",
 							entryPrefix, selectIcon, numColor, displayIndex, colorReset, bar, percentColor, percentStr, colorReset,
-							nameSegment, sizeColor, size, colorReset, hintLabel)
+							nameSegment, sizeSegment, hintLabel)
 					}
 				}
 			}
@@ -339,6 +409,13 @@ func (m model) View() string {
 		} else {
 			fmt.Fprintf(&b, "%s↑↓→ | Enter | R Refresh | O Open | F File | Q Quit%s\n", colorGray, colorReset)
 		}
+	} else if m.showDuplicates {
+		selectCount := len(m.dupMultiSelected)
+		if selectCount > 0 {
+			fmt.Fprintf(&b, "%s↑↓← | Space Select | R Refresh | O Open | F File | ⌫ Del %d | ← Back | Q Quit%s\n", colorGray, selectCount, colorReset)
+		} else {
+			fmt.Fprintf(&b, "%s↑↓← | Space Select | R Refresh | O Open | F File | ⌫ Del | ← Back | Q Quit%s\n", colorGray, colorReset)
+		}
 	} else if m.showLargeFiles {
 		selectCount := len(m.largeMultiSelected)
 		if selectCount > 0 {
@@ -351,15 +428,15 @@ func (m model) View() string {
 		selectCount := len(m.multiSelected)
 		if selectCount > 0 {
 			if largeFileCount > 0 {
-				fmt.Fprintf(&b, "%s↑↓←→ | Space Select | Enter | R Refresh | O Open | F File | ⌫ Del %d | T Top %d | Q Quit%s\n", colorGray, selectCount, largeFileCount, colorReset)
+				fmt.Fprintf(&b, "%s↑↓←→ | Space Select | Enter | R Refresh | O Open | F File | ⌫ Del %d | T Top %d | D Dups | Q Quit%s\n", colorGray, selectCount, largeFileCount, colorReset)
 			} else {
-				fmt.Fprintf(&b, "%s↑↓←→ | Space Select | Enter | R Refresh | O Open | F File | ⌫ Del %d | Q Quit%s\n", colorGray, selectCount, colorReset)
+				fmt.Fprintf(&b, "%s↑↓←→ | Space Select | Enter | R Refresh | O Open | F File | ⌫ Del %d | D Dups | Q Quit%s\n", colorGray, selectCount, colorReset)
 			}
 		} else {
 			if largeFileCount > 0 {
-				fmt.Fprintf(&b, "%s↑↓←→ | Space Select | Enter | R Refresh | O Open | F File | ⌫ Del | T Top %d | Q Quit%s\n", colorGray, largeFileCount, colorReset)
+				fmt.Fprintf(&b, "%s↑↓←→ | Space Select | Enter | R Refresh | O Open | F File | ⌫ Del | T Top %d | D Dups | Q Quit%s\n", colorGray, largeFileCount, colorReset)
 			} else {
-				fmt.Fprintf(&b, "%s↑↓←→ | Space Select | Enter | R Refresh | O Open | F File | ⌫ Del | Q Quit%s\n", colorGray, colorReset)
+				fmt.Fprintf(&b, "%s↑↓←→ | Space Select | Enter | R Refresh | O Open | F File | ⌫ Del | D Dups | Q Quit%s\n", colorGray, colorReset)
 			}
 		}
 	}
@@ -367,7 +444,14 @@ func (m model) View() string {
 		fmt.Fprintln(&b)
 		var deleteCount int
 		var totalDeleteSize int64
-		if m.showLargeFiles && len(m.largeMultiSelected) > 0 {
+		if m.showDuplicates && len(m.dupMultiSelected) > 0 {
+			deleteCount = len(m.dupMultiSelected)
+			for _, row := range duplicateRows(m.duplicates) {
+				if m.dupMultiSelected[row.file.Path] {
+					totalDeleteSize += row.file.Size
+				}
+			}
+		} else if m.showLargeFiles && len(m.largeMultiSelected) > 0 {
 			deleteCount = len(m.largeMultiSelected)
 			for path := range m.largeMultiSelected {
 				for _, file := range m.largeFiles {
@@ -377,7 +461,7 @@ func (m model) View() string {
 					}
 				}
 			}
-		} else if !m.showLargeFiles && len(m.multiSelected) > 0 {
+		} else if !m.showLargeFiles && !m.showDuplicates && len(m.multiSelected) > 0 {
 			deleteCount = len(m.multiSelected)
 			for path := range m.multiSelected {
 				for _, entry := range m.entries {
@@ -400,10 +484,101 @@ func (m model) View() string {
 				m.deleteTarget.Name, humanizeBytes(m.deleteTarget.Size),
 				colorGray, colorReset)
 		}
+		if m.showDuplicates && selectsEveryCopy(m.duplicates, m.dupMultiSelected) {
+			fmt.Fprintf(&b, "%sEvery copy of at least one set is selected, no copy will be kept%s\n", colorYellow, colorReset)
+		}
 	}
 	return b.String()
 }
 
+// renderDuplicates draws duplicate sets as bracketed groups of copies; the
+// first row of each set carries its copy count and reclaimable size.
+func (m model) renderDuplicates(b *strings.Builder) {
+	rows := duplicateRows(m.duplicates)
+	if len(rows) == 0 {
+		fmt.Fprintln(b, "  No duplicate files found")
+		return
+	}
+
+	viewport := calculateViewport(m.height, true)
+	start := max(m.duplicateOffset, 0)
+	end := min(start+viewport, len(rows))
+	nameWidth := calculateNameWidth(m.width)
+	for idx := start; idx < end; idx++ {
+		row := rows[idx]
+		set := m.duplicates[row.set]
+		shortPath := truncateMiddle(displayPath(row.file.Path), nameWidth)
+		paddedPath := padName(shortPath, nameWidth)
+		entryPrefix := "   "
+		nameColor := ""
+		sizeColor := colorGray
+		numColor := ""
+
+		isMultiSelected := m.dupMultiSelected != nil && m.dupMultiSelected[row.file.Path]
+		selectIcon := "○"
+		if isMultiSelected {
+			selectIcon = fmt.Sprintf("%s●%s", colorGreen, colorReset)
+			nameColor = colorGreen
+		}
+		if idx == m.duplicateSelected {
+			entryPrefix = fmt.Sprintf(" %s%s▶%s ", colorCyan, colorBold, colorReset)
+			if !isMultiSelected {
+				nameColor = colorCyan
+			}
+			sizeColor = colorCyan
+			numColor = colorCyan
+		}
+
+		setLabel := "   "
+		bracket := "├"
+		hintLabel := ""
+		switch {
+		case row.first:
+			setLabel = fmt.Sprintf("%2d.", row.set+1)
+			bracket = "┌"
+			hintLabel = fmt.Sprintf("  %s%d copies, %s reclaimable%s", colorYellow, len(set.Files), humanizeBytes(set.wasted()), colorReset)
+		case idx+1 >= len(rows) || rows[idx+1].set != row.set:
+			bracket = "└"
+		}
+
+		fmt.Fprintf(b, "%s%s %s%s%s %s%s%s 📄 %s%s%s  %s%10s%s%s\n",
+			entryPrefix, selectIcon, numColor, setLabel, colorReset, colorGray, bracket, colorReset,
+			nameColor, paddedPath, colorReset, sizeColor, humanizeBytes(row.file.Size), colorReset, hintLabel)
+	}
+}
+
+// renderLiveEntries draws the children finished so far in a running scan.
+// Rows are read-only; selection starts once the scan completes.
+func renderLiveEntries(b *strings.Builder, entries []dirEntry, rows, nameWidth int) {
+	rows = max(rows, 1)
+	maxSize := int64(1)
+	var knownTotal int64
+	for _, entry := range entries {
+		if entry.Size > maxSize {
+			maxSize = entry.Size
+		}
+		knownTotal += entry.Size
+	}
+
+	for idx, entry := range entries[:min(rows, len(entries))] {
+		icon := "📄"
+		if entry.IsDir {
+			icon = "📁"
+		}
+		var percent float64
+		if knownTotal > 0 {
+			percent = float64(entry.Size) / float64(knownTotal) * 100
+		}
+		name := padName(trimNameWithWidth(entry.Name, nameWidth), nameWidth)
+		fmt.Fprintf(b, "   %s%2d.%s %s %s%5.1f%%%s  |  %s %s %s%10s%s\n",
+			colorGray, idx+1, colorReset,
+			coloredProgressBar(entry.Size, maxSize, percent),
+			colorGray, percent, colorReset,
+			icon, name,
+			colorGray, humanizeBytes(entry.Size), colorReset)
+	}
+}
+
 // calculateViewport returns visible rows for the current terminal height.
 func calculateViewport(termHeight int, isLargeFiles bool) int {
 	if termHeight <= 0 {
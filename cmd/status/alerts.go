@@ -0,0 +1,395 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultAlertCooldown keeps a flapping rule from notifying more than once
+// in this window unless the rule sets its own cooldown.
+const defaultAlertCooldown = 15 * time.Minute
+
+// alertUnit is how a metric is measured, which decides how thresholds
+// are parsed and values are printed.
+type alertUnit int
+
+const (
+	alertPercent alertUnit = iota
+	alertBytes
+	alertCelsius
+	alertMBps
+	alertScore
+)
+
+// alertRule fires when Metric stays past Threshold for For and resolves
+// once it comes back past Clear.
+type alertRule struct {
+	Text      string // Rule as written in the config file
+	Metric    string // cpu, cpu_temp, memory, swap, disk:/, disk_io, network, battery, health
+	Below     bool   // Fire on values under Threshold instead of over it
+	Threshold float64
+	Clear     float64
+	For       time.Duration
+	Cooldown  time.Duration
+	Unit      alertUnit
+}
+
+// breached reports whether value is past limit in the rule's direction.
+func (r alertRule) breached(value, limit float64) bool {
+	if r.Below {
+		return value < limit
+	}
+	return value > limit
+}
+
+func (r alertRule) format(value float64) string {
+	switch r.Unit {
+	case alertBytes:
+		return humanBytes(uint64(value))
+	case alertCelsius:
+		return fmt.Sprintf("%.0f°C", value)
+	case alertMBps:
+		return fmt.Sprintf("%.1f MB/s", value)
+	case alertScore:
+		return fmt.Sprintf("%.0f", value)
+	}
+	return fmt.Sprintf("%.0f%%", value)
+}
+
+// alertEvent is a rule starting or stopping to fire.
+type alertEvent struct {
+	Rule     alertRule
+	Value    float64
+	Resolved bool
+	At       time.Time
+	Host     string // Remote host the snapshot came from; "" for this machine
+}
+
+func (e alertEvent) String() string {
+	prefix := ""
+	if e.Host != "" {
+		prefix = e.Host + ": "
+	}
+	if e.Resolved {
+		return fmt.Sprintf("%s%s recovered to %s", prefix, e.Rule.Metric, e.Rule.format(e.Value))
+	}
+	return fmt.Sprintf("%s%s is %s (%s)", prefix, e.Rule.Metric, e.Rule.format(e.Value), e.Rule.Text)
+}
+
+type alertState struct {
+	pendingSince time.Time // Zero while the condition does not hold
+	firing       bool
+	firingSince  time.Time
+	notified     bool      // Sinks were told about the current firing
+	lastFired    time.Time // Last firing sent to sinks
+	value        float64
+}
+
+// cooledDown reports whether sinks may hear about rule firing again.
+func (st *alertState) cooledDown(rule alertRule, now time.Time) bool {
+	return st.lastFired.IsZero() || now.Sub(st.lastFired) >= rule.Cooldown
+}
+
+// alertEngine tracks rule state across snapshots.
+type alertEngine struct {
+	rules  []alertRule
+	states []alertState
+}
+
+func newAlertEngine(rules []alertRule) *alertEngine {
+	return &alertEngine{rules: rules, states: make([]alertState, len(rules))}
+}
+
+// Evaluate updates every rule with m and returns the rules that started
+// or stopped firing. Metrics missing from m leave their rule untouched.
+// The cooldown only holds back events: a rule breaching again inside it
+// is firing at once, and its event is sent when the cooldown ends if it
+// still fires then. A firing that was never sent resolves silently.
+func (e *alertEngine) Evaluate(m MetricsSnapshot) []alertEvent {
+	if e == nil {
+		return nil
+	}
+	now := m.CollectedAt
+	var events []alertEvent
+	for i, rule := range e.rules {
+		value, ok := alertValue(rule.Metric, m)
+		if !ok {
+			continue
+		}
+		st := &e.states[i]
+		st.value = value
+
+		if st.firing {
+			if !rule.breached(value, rule.Clear) {
+				st.firing = false
+				st.pendingSince = time.Time{}
+				if st.notified {
+					events = append(events, alertEvent{Rule: rule, Value: value, Resolved: true, At: now})
+				}
+				st.notified = false
+			} else if !st.notified && st.cooledDown(rule, now) {
+				st.notified = true
+				st.lastFired = now
+				events = append(events, alertEvent{Rule: rule, Value: value, At: now})
+			}
+			continue
+		}
+		if !rule.breached(value, rule.Threshold) {
+			st.pendingSince = time.Time{}
+			continue
+		}
+		if st.pendingSince.IsZero() {
+			st.pendingSince = now
+		}
+		if now.Sub(st.pendingSince) < rule.For {
+			continue
+		}
+		st.firing = true
+		st.firingSince = now
+		if !st.cooledDown(rule, now) {
+			continue
+		}
+		st.notified = true
+		st.lastFired = now
+		events = append(events, alertEvent{Rule: rule, Value: value, At: now})
+	}
+	return events
+}
+
+// Firing returns the rules currently firing with their latest values.
+func (e *alertEngine) Firing() []alertEvent {
+	if e == nil {
+		return nil
+	}
+	var active []alertEvent
+	for i, st := range e.states {
+		if st.firing {
+			active = append(active, alertEvent{Rule: e.rules[i], Value: st.value, At: st.firingSince})
+		}
+	}
+	return active
+}
+
+// alertValue reads metric from m. ok is false when the snapshot does not
+// carry it, e.g. no temperature sensor or no disk at that mount.
+func alertValue(metric string, m MetricsSnapshot) (float64, bool) {
+	if mount, ok := strings.CutPrefix(metric, "disk:"); ok {
+		for _, d := range m.Disks {
+			if d.Mount == mount {
+				return d.UsedPercent, true
+			}
+		}
+		return 0, false
+	}
+
+	switch metric {
+	case "cpu":
+		return m.CPU.Usage, true
+	case "cpu_temp":
+		return m.Thermal.CPUTemp, m.Thermal.CPUTemp > 0
+	case "memory":
+		return m.Memory.UsedPercent, m.Memory.Total > 0
+	case "swap":
+		return float64(m.Memory.SwapUsed), true
+	case "disk":
+		if len(m.Disks) == 0 {
+			return 0, false
+		}
+		return m.Disks[0].UsedPercent, true
+	case "disk_io":
+		return m.DiskIO.ReadRate + m.DiskIO.WriteRate, true
+	case "network":
+		total := 0.0
+		for _, n := range busiestInterfaces(m.Network) {
+			total += n.RxRateMBs + n.TxRateMBs
+		}
+		return total, true
+	case "battery":
+		if len(m.Batteries) == 0 {
+			return 0, false
+		}
+		return m.Batteries[0].Percent, true
+	case "health":
+		return float64(m.HealthScore), true
+	}
+	return 0, false
+}
+
+func alertMetricUnit(metric string) (alertUnit, bool) {
+	if strings.HasPrefix(metric, "disk:") && len(metric) > len("disk:") {
+		return alertPercent, true
+	}
+	switch metric {
+	case "cpu", "memory", "disk", "battery":
+		return alertPercent, true
+	case "swap":
+		return alertBytes, true
+	case "cpu_temp":
+		return alertCelsius, true
+	case "disk_io", "network":
+		return alertMBps, true
+	case "health":
+		return alertScore, true
+	}
+	return 0, false
+}
+
+// getAlertConfigPath returns the path to the alert rules file.
+func getAlertConfigPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "mole", "status_alerts")
+}
+
+// loadAlertRules reads one rule per line:
+//
+//	# metric > threshold [for 5m] [clear value] [cooldown 30m]
+//	disk:/ > 90% for 5m
+//	swap > 4GB
+//	cpu_temp > 90 clear 80 cooldown 30m
+//	battery < 15%
+//
+// clear sets the hysteresis point a firing rule must cross to resolve; it
+// defaults to the threshold. A missing file yields no rules. Bad lines are
+// skipped and reported together in the error.
+func loadAlertRules(path string) ([]alertRule, error) {
+	if path == "" {
+		return nil, nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close() //nolint:errcheck
+
+	var rules []alertRule
+	var problems []string
+	scanner := bufio.NewScanner(file)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rule, err := parseAlertRule(line)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("line %d: %v", lineNo, err))
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return rules, err
+	}
+	if len(problems) > 0 {
+		return rules, fmt.Errorf("%s: %s", filepath.Base(path), strings.Join(problems, "; "))
+	}
+	return rules, nil
+}
+
+func parseAlertRule(line string) (alertRule, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 3 {
+		return alertRule{}, fmt.Errorf("expected \"metric > value\", got %q", line)
+	}
+	// Mount points keep their case; metric names do not.
+	metric := fields[0]
+	if !strings.HasPrefix(strings.ToLower(metric), "disk:") {
+		metric = strings.ToLower(metric)
+	} else {
+		metric = "disk:" + metric[len("disk:"):]
+	}
+	rule := alertRule{Text: line, Metric: metric, Cooldown: defaultAlertCooldown}
+
+	unit, ok := alertMetricUnit(rule.Metric)
+	if !ok {
+		return alertRule{}, fmt.Errorf("unknown metric %q", fields[0])
+	}
+	rule.Unit = unit
+
+	switch fields[1] {
+	case ">":
+	case "<":
+		rule.Below = true
+	default:
+		return alertRule{}, fmt.Errorf("unknown operator %q, use > or <", fields[1])
+	}
+
+	threshold, err := parseAlertValue(fields[2], unit)
+	if err != nil {
+		return alertRule{}, err
+	}
+	rule.Threshold = threshold
+	rule.Clear = threshold
+
+	rest := fields[3:]
+	for len(rest) > 0 {
+		if len(rest) < 2 {
+			return alertRule{}, fmt.Errorf("%q needs a value", rest[0])
+		}
+		keyword, value := strings.ToLower(rest[0]), rest[1]
+		rest = rest[2:]
+		switch keyword {
+		case "for", "cooldown":
+			d, err := time.ParseDuration(value)
+			if err != nil || d < 0 {
+				return alertRule{}, fmt.Errorf("%s needs a duration like 5m, got %q", keyword, value)
+			}
+			if keyword == "for" {
+				rule.For = d
+			} else {
+				rule.Cooldown = d
+			}
+		case "clear":
+			clearAt, err := parseAlertValue(value, unit)
+			if err != nil {
+				return alertRule{}, err
+			}
+			rule.Clear = clearAt
+		default:
+			return alertRule{}, fmt.Errorf("unknown option %q", keyword)
+		}
+	}
+
+	if rule.breached(rule.Clear, rule.Threshold) {
+		return alertRule{}, fmt.Errorf("clear %s is past the threshold", rule.format(rule.Clear))
+	}
+	return rule, nil
+}
+
+// parseAlertValue reads a threshold, accepting the unit suffix that fits
+// the metric: 90%, 4GB, 90°C, 100MB/s.
+func parseAlertValue(s string, unit alertUnit) (float64, error) {
+	text := strings.ToUpper(s)
+	multiplier := 1.0
+	switch unit {
+	case alertPercent:
+		text = strings.TrimSuffix(text, "%")
+	case alertCelsius:
+		text = strings.TrimSuffix(strings.TrimSuffix(text, "C"), "°")
+	case alertMBps:
+		text = strings.TrimSuffix(strings.TrimSuffix(text, "/S"), "MB")
+	case alertBytes:
+		text = strings.TrimSuffix(strings.TrimSuffix(text, "B"), "I")
+		for suffix, scale := range map[string]float64{"K": 1 << 10, "M": 1 << 20, "G": 1 << 30, "T": 1 << 40} {
+			if trimmed, ok := strings.CutSuffix(text, suffix); ok {
+				text, multiplier = trimmed, scale
+				break
+			}
+		}
+	}
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid threshold %q", s)
+	}
+	return value * multiplier, nil
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+const notifyTimeout = 5 * time.Second
+
+// alertSink delivers alert events somewhere outside the engine. The TUI
+// banner reads alertEngine.Firing directly instead.
+type alertSink interface {
+	Send(alertEvent) error
+}
+
+// logAlertSink writes one timestamped line per event.
+type logAlertSink struct {
+	w io.Writer
+}
+
+func (s logAlertSink) Send(e alertEvent) error {
+	state := "FIRING"
+	if e.Resolved {
+		state = "RESOLVED"
+	}
+	_, err := fmt.Fprintf(s.w, "%s %s %s\n", e.At.Format(time.RFC3339), state, e)
+	return err
+}
+
+// desktopAlertSink raises a desktop notification with notify-send on
+// Linux or osascript on macOS.
+type desktopAlertSink struct{}
+
+func (desktopAlertSink) Send(e alertEvent) error {
+	name, args := desktopNotifyCommand(runtime.GOOS, "Mole", e.String())
+	if name == "" {
+		return fmt.Errorf("desktop notifications are not supported on %s", runtime.GOOS)
+	}
+	if !commandExists(name) {
+		return fmt.Errorf("%s not found", name)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+	defer cancel()
+	return exec.CommandContext(ctx, name, args...).Run()
+}
+
+// desktopNotifyCommand builds the notifier invocation for goos, or returns
+// an empty name when there is none.
+func desktopNotifyCommand(goos, title, body string) (string, []string) {
+	switch goos {
+	case "linux":
+		return "notify-send", []string{"--app-name=mole", title, body}
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(body), appleScriptString(title))
+		return "osascript", []string{"-e", script}
+	}
+	return "", nil
+}
+
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+// dispatchAlerts sends every event to every sink and returns the first
+// failure, so one broken sink does not silence the others.
+func dispatchAlerts(sinks []alertSink, events []alertEvent) error {
+	var firstErr error
+	for _, e := range events {
+		for _, s := range sinks {
+			if err := s.Send(e); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+// runAlerts is the headless alert mode: it collects every interval and
+// sends rule changes to sinks until ctx is cancelled. Sink errors are
+// logged to errOut and do not stop the loop.
+func runAlerts(ctx context.Context, c *Collector, engine *alertEngine, interval time.Duration, sinks []alertSink, errOut io.Writer) {
+	_, _ = c.Collect()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		data, _ := c.Collect()
+		if err := dispatchAlerts(sinks, engine.Evaluate(data)); err != nil {
+			fmt.Fprintf(errOut, "alert delivery failed: %v\n", err)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseAlertRule(t *testing.T) {
+	rule, err := parseAlertRule("disk:/Volumes/Data > 90% for 5m clear 85 cooldown 30m")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rule.Metric != "disk:/Volumes/Data" || rule.Below {
+		t.Fatalf("unexpected metric or direction: %+v", rule)
+	}
+	if rule.Threshold != 90 || rule.Clear != 85 || rule.For != 5*time.Minute || rule.Cooldown != 30*time.Minute {
+		t.Fatalf("unexpected rule: %+v", rule)
+	}
+
+	rule, err = parseAlertRule("swap > 4GB")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rule.Threshold != 4<<30 || rule.Clear != rule.Threshold || rule.Cooldown != defaultAlertCooldown {
+		t.Fatalf("unexpected swap rule: %+v", rule)
+	}
+
+	rule, err = parseAlertRule("battery < 15%")
+	if err != nil || !rule.Below || rule.Threshold != 15 {
+		t.Fatalf("unexpected battery rule: %+v (%v)", rule, err)
+	}
+}
+
+func TestParseAlertRuleErrors(t *testing.T) {
+	for _, line := range []string{
+		"cpu 90",
+		"gpu > 90",
+		"cpu >= 90",
+		"cpu > lots",
+		"cpu > 90 for soon",
+		"cpu > 90 clear 95",
+		"cpu > 90 every 5m",
+		"cpu > 90 for",
+	} {
+		if _, err := parseAlertRule(line); err == nil {
+			t.Errorf("expected error for %q", line)
+		}
+	}
+}
+
+func TestLoadAlertRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "status_alerts")
+	config := "# laptop\ncpu_temp > 90°C\n\nnonsense\nnetwork > 100MB/s for 1m\n"
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	rules, err := loadAlertRules(path)
+	if err == nil || !strings.Contains(err.Error(), "line 4") {
+		t.Fatalf("expected error for line 4, got %v", err)
+	}
+	if len(rules) != 2 || rules[0].Threshold != 90 || rules[1].Threshold != 100 {
+		t.Fatalf("valid lines should still load: %+v", rules)
+	}
+
+	rules, err = loadAlertRules(filepath.Join(t.TempDir(), "missing"))
+	if err != nil || len(rules) != 0 {
+		t.Fatalf("missing file should yield no rules, got %v (%v)", rules, err)
+	}
+}
+
+func alertSnapshot(at time.Time, diskPercent float64) MetricsSnapshot {
+	return MetricsSnapshot{CollectedAt: at, Disks: []DiskStatus{{Mount: "/", UsedPercent: diskPercent}}}
+}
+
+func TestAlertEngineForAndHysteresis(t *testing.T) {
+	rule, err := parseAlertRule("disk:/ > 90 for 5m clear 85 cooldown 0s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine := newAlertEngine([]alertRule{rule})
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if events := engine.Evaluate(alertSnapshot(start, 95)); len(events) != 0 {
+		t.Fatalf("should wait for the duration, got %v", events)
+	}
+	if events := engine.Evaluate(alertSnapshot(start.Add(4*time.Minute), 95)); len(events) != 0 {
+		t.Fatalf("should still be pending, got %v", events)
+	}
+	events := engine.Evaluate(alertSnapshot(start.Add(5*time.Minute), 93))
+	if len(events) != 1 || events[0].Resolved || events[0].Value != 93 {
+		t.Fatalf("expected rule to fire, got %v", events)
+	}
+	if firing := engine.Firing(); len(firing) != 1 {
+		t.Fatalf("expected one firing rule, got %v", firing)
+	}
+
+	// Between clear and threshold the rule keeps firing.
+	if events := engine.Evaluate(alertSnapshot(start.Add(6*time.Minute), 88)); len(events) != 0 {
+		t.Fatalf("hysteresis should hold the alert, got %v", events)
+	}
+	events = engine.Evaluate(alertSnapshot(start.Add(7*time.Minute), 80))
+	if len(events) != 1 || !events[0].Resolved {
+		t.Fatalf("expected rule to resolve, got %v", events)
+	}
+	if firing := engine.Firing(); len(firing) != 0 {
+		t.Fatalf("expected nothing firing, got %v", firing)
+	}
+}
+
+func TestAlertEngineCooldown(t *testing.T) {
+	rule, err := parseAlertRule("disk:/ > 90 cooldown 10m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine := newAlertEngine([]alertRule{rule})
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if events := engine.Evaluate(alertSnapshot(start, 95)); len(events) != 1 {
+		t.Fatalf("expected immediate fire, got %v", events)
+	}
+	if events := engine.Evaluate(alertSnapshot(start.Add(time.Minute), 50)); len(events) != 1 || !events[0].Resolved {
+		t.Fatalf("expected resolve, got %v", events)
+	}
+	if events := engine.Evaluate(alertSnapshot(start.Add(2*time.Minute), 95)); len(events) != 0 {
+		t.Fatalf("cooldown should suppress refire, got %v", events)
+	}
+	if events := engine.Evaluate(alertSnapshot(start.Add(11*time.Minute), 95)); len(events) != 1 {
+		t.Fatalf("expected refire after cooldown, got %v", events)
+	}
+}
+
+func TestAlertEngineCooldownKeepsBannerFiring(t *testing.T) {
+	rule, err := parseAlertRule("disk:/ > 90 clear 85")
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine := newAlertEngine([]alertRule{rule})
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	engine.Evaluate(alertSnapshot(start, 95))
+	engine.Evaluate(alertSnapshot(start.Add(time.Minute), 50))
+
+	// Breaching again inside the default cooldown: no event, but the
+	// banner must still show the rule.
+	again := start.Add(2 * time.Minute)
+	if events := engine.Evaluate(alertSnapshot(again, 96)); len(events) != 0 {
+		t.Fatalf("cooldown should hold back the event, got %v", events)
+	}
+	firing := engine.Firing()
+	if len(firing) != 1 || firing[0].Value != 96 || !firing[0].At.Equal(again) {
+		t.Fatalf("re-breached rule should be firing, got %v", firing)
+	}
+	if banner := stripANSI(renderAlertBanner(firing, 120)); !strings.Contains(banner, "disk:/") {
+		t.Errorf("banner should show the ongoing breach, got %q", banner)
+	}
+
+	// Resolving a firing no sink heard about stays quiet.
+	if events := engine.Evaluate(alertSnapshot(start.Add(3*time.Minute), 50)); len(events) != 0 {
+		t.Fatalf("unsent firing should resolve silently, got %v", events)
+	}
+	if firing := engine.Firing(); len(firing) != 0 {
+		t.Fatalf("expected nothing firing, got %v", firing)
+	}
+}
+
+func TestAlertEngineSkipsMissingMetrics(t *testing.T) {
+	rule, err := parseAlertRule("cpu_temp > 90")
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine := newAlertEngine([]alertRule{rule})
+	snapshot := MetricsSnapshot{CollectedAt: time.Now(), Thermal: ThermalStatus{CPUTemp: 0}}
+	if events := engine.Evaluate(snapshot); len(events) != 0 {
+		t.Fatalf("missing sensor should not fire, got %v", events)
+	}
+}
+
+func TestLogAlertSink(t *testing.T) {
+	rule, err := parseAlertRule("swap > 4GB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	at := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	err = dispatchAlerts([]alertSink{logAlertSink{w: &buf}}, []alertEvent{
+		{Rule: rule, Value: 5 << 30, At: at},
+		{Rule: rule, Value: 1 << 30, At: at, Resolved: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "2026-01-01T12:00:00Z FIRING swap is 5.0 GB (swap > 4GB)\n" +
+		"2026-01-01T12:00:00Z RESOLVED swap recovered to 1024.0 MB\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected log output:\n%s", buf.String())
+	}
+}
+
+func TestDesktopNotifyCommand(t *testing.T) {
+	name, args := desktopNotifyCommand("linux", "Mole", "cpu is 95%")
+	if name != "notify-send" || args[len(args)-1] != "cpu is 95%" {
+		t.Fatalf("unexpected linux command: %s %v", name, args)
+	}
+	name, args = desktopNotifyCommand("darwin", "Mole", `disk "/" is full`)
+	if name != "osascript" || args[1] != `display notification "disk \"/\" is full" with title "Mole"` {
+		t.Fatalf("unexpected darwin command: %s %v", name, args)
+	}
+	if name, _ := desktopNotifyCommand("windows", "Mole", "x"); name != "" {
+		t.Fatalf("expected no notifier on windows, got %s", name)
+	}
+}
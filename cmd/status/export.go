@@ -0,0 +1,442 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+const (
+	defaultWatchInterval = 5 * time.Second
+	// Rates are deltas between two collections, so a single snapshot
+	// samples twice with this gap.
+	jsonSampleDelay = time.Second
+	bytesPerMiB     = 1024 * 1024
+)
+
+// jsonSnapshot is the stable schema printed by --json and --watch.
+// Field names carry their unit; add fields, never rename them.
+type jsonSnapshot struct {
+	CollectedAt   time.Time        `json:"collected_at"`
+	Host          string           `json:"host"`
+	Platform      string           `json:"platform"`
+	UptimeSeconds uint64           `json:"uptime_seconds"`
+	Procs         uint64           `json:"procs"`
+	Health        jsonHealth       `json:"health"`
+	Hardware      jsonHardware     `json:"hardware"`
+	CPU           jsonCPU          `json:"cpu"`
+	GPU           []jsonGPU        `json:"gpu"`
+	Memory        jsonMemory       `json:"memory"`
+	Disks         []jsonDisk       `json:"disks"`
+	DiskIO        jsonDiskIO       `json:"disk_io"`
+	DiskHealth    []jsonDiskHealth `json:"disk_health"`
+	Network       []jsonNetwork    `json:"network"`
+	Proxy         jsonProxy        `json:"proxy"`
+	Batteries     []jsonBattery    `json:"batteries"`
+	Thermal       jsonThermal      `json:"thermal"`
+	Sensors       []jsonSensor     `json:"sensors"`
+	Bluetooth     []jsonBluetooth  `json:"bluetooth"`
+	Containers    []jsonContainer  `json:"containers"`
+	TopProcesses  []jsonProcess    `json:"top_processes"`
+	Error         string           `json:"error,omitempty"`
+}
+
+type jsonHealth struct {
+	Score     int                 `json:"score"`
+	Message   string              `json:"message"`
+	Penalties []jsonHealthPenalty `json:"penalties"`
+}
+
+type jsonHealthPenalty struct {
+	Component string  `json:"component"`
+	Points    float64 `json:"points"`
+}
+
+type jsonHardware struct {
+	Model       string `json:"model"`
+	CPUModel    string `json:"cpu_model"`
+	TotalRAM    string `json:"total_ram"`
+	DiskSize    string `json:"disk_size"`
+	OSVersion   string `json:"os_version"`
+	RefreshRate string `json:"refresh_rate"`
+}
+
+type jsonCPU struct {
+	UsagePercent     float64   `json:"usage_percent"`
+	PerCorePercent   []float64 `json:"per_core_percent"`
+	PerCoreEstimated bool      `json:"per_core_estimated"`
+	Load1            float64   `json:"load_1"`
+	Load5            float64   `json:"load_5"`
+	Load15           float64   `json:"load_15"`
+	Cores            int       `json:"cores"`
+	LogicalCPUs      int       `json:"logical_cpus"`
+	PerformanceCores int       `json:"performance_cores"`
+	EfficiencyCores  int       `json:"efficiency_cores"`
+	// cgroup v2 limits, present only when a quota applies.
+	CgroupQuotaCores       float64 `json:"cgroup_quota_cores,omitempty"`
+	CgroupUsagePercent     float64 `json:"cgroup_usage_percent,omitempty"`
+	CgroupThrottledPercent float64 `json:"cgroup_throttled_percent,omitempty"`
+}
+
+type jsonGPU struct {
+	Name           string  `json:"name"`
+	UsagePercent   float64 `json:"usage_percent"`
+	MemoryUsedMiB  float64 `json:"memory_used_mib"`
+	MemoryTotalMiB float64 `json:"memory_total_mib"`
+	Cores          int     `json:"cores"`
+	Note           string  `json:"note,omitempty"`
+}
+
+type jsonMemory struct {
+	UsedBytes      uint64  `json:"used_bytes"`
+	TotalBytes     uint64  `json:"total_bytes"`
+	UsedPercent    float64 `json:"used_percent"`
+	SwapUsedBytes  uint64  `json:"swap_used_bytes"`
+	SwapTotalBytes uint64  `json:"swap_total_bytes"`
+	CachedBytes    uint64  `json:"cached_bytes"`
+	Pressure       string  `json:"pressure,omitempty"`
+	CgroupLimit    uint64  `json:"cgroup_limit_bytes,omitempty"`
+	CgroupUsed     uint64  `json:"cgroup_used_bytes,omitempty"`
+}
+
+type jsonDisk struct {
+	Mount       string  `json:"mount"`
+	Device      string  `json:"device"`
+	Fstype      string  `json:"fstype"`
+	External    bool    `json:"external"`
+	UsedBytes   uint64  `json:"used_bytes"`
+	TotalBytes  uint64  `json:"total_bytes"`
+	UsedPercent float64 `json:"used_percent"`
+}
+
+type jsonDiskIO struct {
+	ReadBytesPerSec  float64          `json:"read_bytes_per_sec"`
+	WriteBytesPerSec float64          `json:"write_bytes_per_sec"`
+	Devices          []jsonDiskDevice `json:"devices,omitempty"`
+}
+
+type jsonDiskDevice struct {
+	Name             string  `json:"name"`
+	ReadBytesPerSec  float64 `json:"read_bytes_per_sec"`
+	WriteBytesPerSec float64 `json:"write_bytes_per_sec"`
+	ReadsPerSec      float64 `json:"reads_per_sec"`
+	WritesPerSec     float64 `json:"writes_per_sec"`
+	AwaitMs          float64 `json:"await_ms"`
+	BusyPercent      float64 `json:"busy_percent"`
+}
+
+type jsonDiskHealth struct {
+	Device       string   `json:"device"`
+	Model        string   `json:"model,omitempty"`
+	Protocol     string   `json:"protocol,omitempty"`
+	SmartPassed  bool     `json:"smart_passed"`
+	PercentUsed  *float64 `json:"percent_used,omitempty"` // Absent when the drive does not report wear
+	MediaErrors  uint64   `json:"media_errors"`
+	TempCelsius  float64  `json:"temperature_celsius,omitempty"`
+	PowerOnHours uint64   `json:"power_on_hours"`
+}
+
+type jsonNetwork struct {
+	Name          string  `json:"name"`
+	IP            string  `json:"ip,omitempty"`
+	RxBytesPerSec float64 `json:"rx_bytes_per_sec"`
+	TxBytesPerSec float64 `json:"tx_bytes_per_sec"`
+	// Counters since mole started watching the interface.
+	RxBytesTotal uint64 `json:"rx_bytes_since_start"`
+	TxBytesTotal uint64 `json:"tx_bytes_since_start"`
+	ErrorsIn     uint64 `json:"errors_in"`
+	ErrorsOut    uint64 `json:"errors_out"`
+	DropsIn      uint64 `json:"drops_in"`
+	DropsOut     uint64 `json:"drops_out"`
+}
+
+type jsonProxy struct {
+	Enabled bool   `json:"enabled"`
+	Type    string `json:"type,omitempty"`
+	Host    string `json:"host,omitempty"`
+}
+
+type jsonBattery struct {
+	Percent         float64 `json:"percent"`
+	Status          string  `json:"status"`
+	TimeLeft        string  `json:"time_left,omitempty"`
+	Health          string  `json:"health,omitempty"`
+	CycleCount      int     `json:"cycle_count"`
+	CapacityPercent int     `json:"capacity_percent"`
+}
+
+type jsonThermal struct {
+	CPUTempCelsius float64 `json:"cpu_temp_celsius"`
+	GPUTempCelsius float64 `json:"gpu_temp_celsius"`
+	FanRPM         int     `json:"fan_rpm"`
+	FanCount       int     `json:"fan_count"`
+	SystemPowerW   float64 `json:"system_power_watts"`
+	AdapterPowerW  float64 `json:"adapter_power_watts"`
+	BatteryPowerW  float64 `json:"battery_power_watts"`
+}
+
+type jsonSensor struct {
+	Label string  `json:"label"`
+	Value float64 `json:"value"`
+	Unit  string  `json:"unit"`
+	Note  string  `json:"note,omitempty"`
+}
+
+type jsonBluetooth struct {
+	Name      string `json:"name"`
+	Connected bool   `json:"connected"`
+	Battery   string `json:"battery,omitempty"`
+}
+
+type jsonContainer struct {
+	ID               string  `json:"id"`
+	Name             string  `json:"name"`
+	Image            string  `json:"image"`
+	Engine           string  `json:"engine"`
+	State            string  `json:"state"`
+	CPUPercent       float64 `json:"cpu_percent"`
+	MemoryUsedBytes  uint64  `json:"memory_used_bytes"`
+	MemoryLimitBytes uint64  `json:"memory_limit_bytes,omitempty"`
+}
+
+type jsonProcess struct {
+	Name          string  `json:"name"`
+	CPUPercent    float64 `json:"cpu_percent"`
+	MemoryPercent float64 `json:"memory_percent"`
+	PID           int32   `json:"pid"`
+	User          string  `json:"user,omitempty"`
+	Command       string  `json:"command,omitempty"`
+	RSSBytes      uint64  `json:"rss_bytes"`
+	Threads       int32   `json:"threads"`
+	IOBytesPerSec float64 `json:"io_bytes_per_sec"`
+}
+
+// toJSONSnapshot converts a snapshot to the export schema. Rates are
+// converted from the MiB/s the cards display to bytes per second.
+func toJSONSnapshot(m MetricsSnapshot, collectErr error) jsonSnapshot {
+	out := jsonSnapshot{
+		CollectedAt:   m.CollectedAt.UTC(),
+		Host:          m.Host,
+		Platform:      m.Platform,
+		UptimeSeconds: m.UptimeSeconds,
+		Procs:         m.Procs,
+		Health:        jsonHealth{Score: m.HealthScore, Message: m.HealthScoreMsg, Penalties: make([]jsonHealthPenalty, 0, len(m.HealthPenalties))},
+		Hardware: jsonHardware{
+			Model:       m.Hardware.Model,
+			CPUModel:    m.Hardware.CPUModel,
+			TotalRAM:    m.Hardware.TotalRAM,
+			DiskSize:    m.Hardware.DiskSize,
+			OSVersion:   m.Hardware.OSVersion,
+			RefreshRate: m.Hardware.RefreshRate,
+		},
+		CPU: jsonCPU{
+			UsagePercent:     m.CPU.Usage,
+			PerCorePercent:   m.CPU.PerCore,
+			PerCoreEstimated: m.CPU.PerCoreEstimated,
+			Load1:            m.CPU.Load1,
+			Load5:            m.CPU.Load5,
+			Load15:           m.CPU.Load15,
+			Cores:            m.CPU.CoreCount,
+			LogicalCPUs:      m.CPU.LogicalCPU,
+			PerformanceCores: m.CPU.PCoreCount,
+			EfficiencyCores:  m.CPU.ECoreCount,
+
+			CgroupQuotaCores:       m.CPU.CgroupQuota,
+			CgroupUsagePercent:     m.CPU.CgroupUsage,
+			CgroupThrottledPercent: m.CPU.CgroupThrottled,
+		},
+		Memory: jsonMemory{
+			UsedBytes:      m.Memory.Used,
+			TotalBytes:     m.Memory.Total,
+			UsedPercent:    m.Memory.UsedPercent,
+			SwapUsedBytes:  m.Memory.SwapUsed,
+			SwapTotalBytes: m.Memory.SwapTotal,
+			CachedBytes:    m.Memory.Cached,
+			Pressure:       m.Memory.Pressure,
+			CgroupLimit:    m.Memory.CgroupLimit,
+			CgroupUsed:     m.Memory.CgroupUsed,
+		},
+		DiskIO: jsonDiskIO{
+			ReadBytesPerSec:  m.DiskIO.ReadRate * bytesPerMiB,
+			WriteBytesPerSec: m.DiskIO.WriteRate * bytesPerMiB,
+		},
+		Proxy: jsonProxy{Enabled: m.Proxy.Enabled, Type: m.Proxy.Type, Host: m.Proxy.Host},
+		Thermal: jsonThermal{
+			CPUTempCelsius: m.Thermal.CPUTemp,
+			GPUTempCelsius: m.Thermal.GPUTemp,
+			FanRPM:         m.Thermal.FanSpeed,
+			FanCount:       m.Thermal.FanCount,
+			SystemPowerW:   m.Thermal.SystemPower,
+			AdapterPowerW:  m.Thermal.AdapterPower,
+			BatteryPowerW:  m.Thermal.BatteryPower,
+		},
+		GPU:          make([]jsonGPU, 0, len(m.GPU)),
+		Disks:        make([]jsonDisk, 0, len(m.Disks)),
+		Network:      make([]jsonNetwork, 0, len(m.Network)),
+		Batteries:    make([]jsonBattery, 0, len(m.Batteries)),
+		Sensors:      make([]jsonSensor, 0, len(m.Sensors)),
+		Bluetooth:    make([]jsonBluetooth, 0, len(m.Bluetooth)),
+		Containers:   make([]jsonContainer, 0, len(m.Containers)),
+		DiskHealth:   make([]jsonDiskHealth, 0, len(m.DiskHealth)),
+		TopProcesses: make([]jsonProcess, 0, len(m.TopProcesses)),
+	}
+	if out.CPU.PerCorePercent == nil {
+		out.CPU.PerCorePercent = []float64{}
+	}
+	if collectErr != nil {
+		out.Error = collectErr.Error()
+	}
+
+	for _, p := range m.HealthPenalties {
+		out.Health.Penalties = append(out.Health.Penalties, jsonHealthPenalty{Component: p.Component, Points: p.Penalty})
+	}
+	for _, g := range m.GPU {
+		out.GPU = append(out.GPU, jsonGPU{
+			Name:           g.Name,
+			UsagePercent:   g.Usage,
+			MemoryUsedMiB:  g.MemoryUsed,
+			MemoryTotalMiB: g.MemoryTotal,
+			Cores:          g.CoreCount,
+			Note:           g.Note,
+		})
+	}
+	for _, d := range m.Disks {
+		out.Disks = append(out.Disks, jsonDisk{
+			Mount:       d.Mount,
+			Device:      d.Device,
+			Fstype:      d.Fstype,
+			External:    d.External,
+			UsedBytes:   d.Used,
+			TotalBytes:  d.Total,
+			UsedPercent: d.UsedPercent,
+		})
+	}
+	for _, d := range m.DiskIO.Devices {
+		out.DiskIO.Devices = append(out.DiskIO.Devices, jsonDiskDevice{
+			Name:             d.Name,
+			ReadBytesPerSec:  d.ReadRate * bytesPerMiB,
+			WriteBytesPerSec: d.WriteRate * bytesPerMiB,
+			ReadsPerSec:      d.ReadIOPS,
+			WritesPerSec:     d.WriteIOPS,
+			AwaitMs:          d.Await,
+			BusyPercent:      d.Busy,
+		})
+	}
+	for _, d := range m.DiskHealth {
+		h := jsonDiskHealth{
+			Device:       d.Device,
+			Model:        d.Model,
+			Protocol:     d.Protocol,
+			SmartPassed:  d.Passed,
+			MediaErrors:  d.MediaErrors,
+			TempCelsius:  d.Temperature,
+			PowerOnHours: d.PowerOnHours,
+		}
+		if d.WearKnown {
+			h.PercentUsed = &d.PercentUsed
+		}
+		out.DiskHealth = append(out.DiskHealth, h)
+	}
+	for _, n := range m.Network {
+		out.Network = append(out.Network, jsonNetwork{
+			Name:          n.Name,
+			IP:            n.IP,
+			RxBytesPerSec: n.RxRateMBs * bytesPerMiB,
+			TxBytesPerSec: n.TxRateMBs * bytesPerMiB,
+			RxBytesTotal:  n.RxTotal,
+			TxBytesTotal:  n.TxTotal,
+			ErrorsIn:      n.ErrIn,
+			ErrorsOut:     n.ErrOut,
+			DropsIn:       n.DropIn,
+			DropsOut:      n.DropOut,
+		})
+	}
+	for _, b := range m.Batteries {
+		out.Batteries = append(out.Batteries, jsonBattery{
+			Percent:         b.Percent,
+			Status:          b.Status,
+			TimeLeft:        b.TimeLeft,
+			Health:          b.Health,
+			CycleCount:      b.CycleCount,
+			CapacityPercent: b.Capacity,
+		})
+	}
+	for _, s := range m.Sensors {
+		out.Sensors = append(out.Sensors, jsonSensor{Label: s.Label, Value: s.Value, Unit: s.Unit, Note: s.Note})
+	}
+	for _, d := range m.Bluetooth {
+		out.Bluetooth = append(out.Bluetooth, jsonBluetooth{Name: d.Name, Connected: d.Connected, Battery: d.Battery})
+	}
+	for _, ct := range m.Containers {
+		out.Containers = append(out.Containers, jsonContainer{
+			ID:               ct.ID,
+			Name:             ct.Name,
+			Image:            ct.Image,
+			Engine:           ct.Engine,
+			State:            ct.State,
+			CPUPercent:       ct.CPU,
+			MemoryUsedBytes:  ct.MemUsed,
+			MemoryLimitBytes: ct.MemLimit,
+		})
+	}
+	for _, p := range m.TopProcesses {
+		out.TopProcesses = append(out.TopProcesses, jsonProcess{
+			Name:          p.Name,
+			CPUPercent:    p.CPU,
+			MemoryPercent: p.Memory,
+			PID:           p.PID,
+			User:          p.User,
+			Command:       p.Command,
+			RSSBytes:      p.RSS,
+			Threads:       p.Threads,
+			IOBytesPerSec: p.IORate * bytesPerMiB,
+		})
+	}
+	return out
+}
+
+// runJSONSnapshot prints one indented snapshot. The collector is sampled
+// twice so network and disk rates are populated, and the second sample
+// waits for the background SMART read the first one started.
+func runJSONSnapshot(c *Collector, w io.Writer) error {
+	_, _ = c.Collect()
+	time.Sleep(jsonSampleDelay)
+	c.smartDone.Wait()
+	data, err := c.Collect()
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(toJSONSnapshot(data, err))
+}
+
+// runStaticJSONSnapshot prints host, memory and disk figures at once,
+// without the sampling delay or slow probes of a full snapshot.
+func runStaticJSONSnapshot(c *Collector, w io.Writer) error {
+	data, err := c.CollectStatic()
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(toJSONSnapshot(data, err))
+}
+
+// runWatch streams one compact snapshot per line (NDJSON) every interval
+// until ctx is cancelled.
+func runWatch(ctx context.Context, c *Collector, interval time.Duration, w io.Writer) error {
+	_, _ = c.Collect()
+
+	encoder := json.NewEncoder(w)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		data, err := c.Collect()
+		if encErr := encoder.Encode(toJSONSnapshot(data, err)); encErr != nil {
+			return encErr
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestToJSONSnapshotUnits(t *testing.T) {
+	snap := MetricsSnapshot{
+		CollectedAt:   time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Host:          "box",
+		UptimeSeconds: 90061,
+		HealthScore:   88,
+		Memory:        MemoryStatus{Used: 8 << 30, Total: 16 << 30, UsedPercent: 50},
+		DiskIO:        DiskIOStatus{ReadRate: 2, WriteRate: 0.5},
+		Network:       []NetworkStatus{{Name: "en0", RxRateMBs: 1, TxRateMBs: 0.25}},
+		Disks:         []DiskStatus{{Mount: "/", Used: 100, Total: 200, UsedPercent: 50}},
+	}
+
+	out := toJSONSnapshot(snap, errors.New("gpu: timeout"))
+	if out.DiskIO.ReadBytesPerSec != 2*bytesPerMiB || out.DiskIO.WriteBytesPerSec != bytesPerMiB/2 {
+		t.Fatalf("disk io not converted to bytes/s: %+v", out.DiskIO)
+	}
+	if out.Network[0].RxBytesPerSec != bytesPerMiB || out.Network[0].TxBytesPerSec != bytesPerMiB/4 {
+		t.Fatalf("network not converted to bytes/s: %+v", out.Network[0])
+	}
+	if out.Error != "gpu: timeout" {
+		t.Fatalf("expected collection error carried, got %q", out.Error)
+	}
+
+	data, err := json.Marshal(out)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	for _, key := range []string{"collected_at", "uptime_seconds", "health", "cpu", "memory", "disks", "disk_io", "disk_health", "network", "gpu", "top_processes"} {
+		if _, ok := raw[key]; !ok {
+			t.Errorf("missing key %q in %s", key, data)
+		}
+	}
+	memory := raw["memory"].(map[string]any)
+	if memory["used_bytes"].(float64) != float64(8<<30) {
+		t.Errorf("memory.used_bytes = %v", memory["used_bytes"])
+	}
+	if gpus, ok := raw["gpu"].([]any); !ok || len(gpus) != 0 {
+		t.Errorf("expected empty gpu array rather than null, got %v", raw["gpu"])
+	}
+}
+
+func TestRunWatchStreamsNDJSON(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var buf bytes.Buffer
+	done := make(chan error, 1)
+	w := &signalWriter{buf: &buf, wrote: make(chan struct{}, 8)}
+
+	go func() { done <- runWatch(ctx, NewCollector(), 20*time.Millisecond, w) }()
+	for range 2 {
+		select {
+		case <-w.wrote:
+		case <-time.After(30 * time.Second):
+			t.Fatal("timed out waiting for snapshots")
+		}
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("runWatch: %v", err)
+	}
+
+	lines := 0
+	scanner := bufio.NewScanner(bytes.NewReader(buf.Bytes()))
+	scanner.Buffer(make([]byte, 0, 64<<10), 1<<20)
+	for scanner.Scan() {
+		var snap jsonSnapshot
+		if err := json.Unmarshal(scanner.Bytes(), &snap); err != nil {
+			t.Fatalf("line %d is not JSON: %v", lines+1, err)
+		}
+		if snap.CollectedAt.IsZero() {
+			t.Fatalf("line %d has no timestamp", lines+1)
+		}
+		lines++
+	}
+	if lines < 2 {
+		t.Fatalf("expected at least 2 NDJSON lines, got %d", lines)
+	}
+}
+
+func TestRunStaticJSONSnapshotSkipsSampling(t *testing.T) {
+	var buf bytes.Buffer
+	start := time.Now()
+	if err := runStaticJSONSnapshot(NewCollector(), &buf); err != nil {
+		t.Fatalf("runStaticJSONSnapshot: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed >= jsonSampleDelay {
+		t.Fatalf("static snapshot took %v, expected no sampling delay", elapsed)
+	}
+	var snap jsonSnapshot
+	if err := json.Unmarshal(buf.Bytes(), &snap); err != nil {
+		t.Fatalf("not JSON: %v", err)
+	}
+	if snap.Memory.TotalBytes == 0 || snap.UptimeSeconds == 0 {
+		t.Fatalf("expected memory and uptime, got %+v", snap)
+	}
+}
+
+// signalWriter signals each write. runWatch writes from a single goroutine
+// and the buffer is only read after it returns.
+type signalWriter struct {
+	buf   *bytes.Buffer
+	wrote chan struct{}
+}
+
+func (w *signalWriter) Write(p []byte) (int, error) {
+	n, err := w.buf.Write(p)
+	select {
+	case w.wrote <- struct{}{}:
+	default:
+	}
+	return n, err
+}
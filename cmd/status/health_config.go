@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// getHealthConfigPath returns the path to the health score config file.
+func getHealthConfigPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "mole", "status_health")
+}
+
+// loadHealthModel reads overrides from path on top of the defaults. The
+// file holds one key=value per line, for example:
+//
+//	# Build server: busy CPU is normal, swapping is not.
+//	cpu.normal=80
+//	cpu.high=98
+//	thermal.enabled=false
+//	swap.enabled=true
+//	swap.weight=20
+//
+// Keys are <component>.enabled|weight|normal|high for cpu, memory, disk,
+// thermal, io, network, swap, battery and wear, plus memory.pressure_warn and
+// memory.pressure_critical. A missing file yields the defaults. Bad lines
+// are skipped and reported together in the error.
+func loadHealthModel(path string) (healthModel, error) {
+	model := defaultHealthModel()
+	if path == "" {
+		return model, nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return model, nil
+		}
+		return model, err
+	}
+	defer file.Close() //nolint:errcheck
+
+	var problems []string
+	scanner := bufio.NewScanner(file)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			problems = append(problems, fmt.Sprintf("line %d: expected key=value", lineNo))
+			continue
+		}
+		if err := model.set(strings.TrimSpace(key), strings.TrimSpace(value)); err != nil {
+			problems = append(problems, fmt.Sprintf("line %d: %v", lineNo, err))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return model, err
+	}
+	problems = append(problems, model.validate()...)
+	if len(problems) > 0 {
+		return model, fmt.Errorf("%s: %s", filepath.Base(path), strings.Join(problems, "; "))
+	}
+	return model, nil
+}
+
+func (m *healthModel) set(key, value string) error {
+	name, field, ok := strings.Cut(strings.ToLower(key), ".")
+	if !ok {
+		return fmt.Errorf("unknown key %q", key)
+	}
+
+	if name == "memory" && (field == "pressure_warn" || field == "pressure_critical") {
+		penalty, err := strconv.ParseFloat(value, 64)
+		if err != nil || penalty < 0 {
+			return fmt.Errorf("%s needs a non-negative number", key)
+		}
+		if field == "pressure_warn" {
+			m.MemPressureWarnPenalty = penalty
+		} else {
+			m.MemPressureCritPenalty = penalty
+		}
+		return nil
+	}
+
+	component := m.component(name)
+	if component == nil {
+		return fmt.Errorf("unknown component %q", name)
+	}
+	if field == "enabled" {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s needs true or false", key)
+		}
+		component.Enabled = enabled
+		return nil
+	}
+
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fmt.Errorf("%s needs a number", key)
+	}
+	switch field {
+	case "weight":
+		if number < 0 {
+			return errors.New(key + " cannot be negative")
+		}
+		component.Weight = number
+	case "normal":
+		component.Normal = number
+	case "high":
+		component.High = number
+	default:
+		return fmt.Errorf("unknown key %q", key)
+	}
+	return nil
+}
+
+// validate resets components whose thresholds cannot form a penalty curve
+// back to their defaults and reports them.
+func (m *healthModel) validate() []string {
+	var problems []string
+	defaults := defaultHealthModel()
+	for _, name := range []string{"cpu", "memory", "disk", "thermal", "io", "network", "swap", "battery", "wear"} {
+		c := m.component(name)
+		valid := c.Normal > 0 && c.High > c.Normal
+		if name == "battery" {
+			valid = c.High > 0 && c.High < c.Normal
+		}
+		if name == "disk" {
+			valid = valid && c.Normal < 100
+		}
+		if !valid {
+			problems = append(problems, fmt.Sprintf("%s thresholds out of order (normal %g, high %g), using defaults", name, c.Normal, c.High))
+			d := defaults.component(name)
+			c.Normal, c.High = d.Normal, d.High
+		}
+	}
+	return problems
+}
+
+func (m *healthModel) component(name string) *healthComponent {
+	switch name {
+	case "cpu":
+		return &m.CPU
+	case "memory":
+		return &m.Memory
+	case "disk":
+		return &m.Disk
+	case "thermal":
+		return &m.Thermal
+	case "io":
+		return &m.IO
+	case "network":
+		return &m.Network
+	case "swap":
+		return &m.Swap
+	case "battery":
+		return &m.Battery
+	case "wear":
+		return &m.Wear
+	}
+	return nil
+}
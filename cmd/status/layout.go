@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Card IDs, in the default order.
+const (
+	cardCPU         = "cpu"
+	cardMemory      = "memory"
+	cardDisk        = "disk"
+	cardPower       = "power"
+	cardProcesses   = "processes"
+	cardNetwork     = "network"
+	cardGPU         = "gpu"
+	cardSensors     = "sensors"
+	cardPeripherals = "peripherals"
+	cardContainers  = "containers"
+)
+
+var defaultCardOrder = []string{cardCPU, cardMemory, cardDisk, cardPower, cardProcesses, cardNetwork, cardGPU, cardSensors, cardPeripherals, cardContainers}
+
+// cardLayout is the user's card order and hidden set.
+type cardLayout struct {
+	Order  []string
+	Hidden map[string]bool
+}
+
+func defaultCardLayout() cardLayout {
+	return cardLayout{Order: slices.Clone(defaultCardOrder), Hidden: map[string]bool{}}
+}
+
+// getLayoutPath returns the path to the card layout file, next to
+// status_prefs.
+func getLayoutPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "mole", "status_layout")
+}
+
+// loadCardLayout reads the layout file:
+//
+//	order=cpu,memory,network,disk,power,processes
+//	hidden=power
+//
+// Unknown cards are ignored and cards missing from order keep their
+// default place at the end, so the file survives new cards being added.
+func loadCardLayout(path string) cardLayout {
+	layout := defaultCardLayout()
+	if path == "" {
+		return layout
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return layout
+	}
+	defer file.Close() //nolint:errcheck
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok {
+			continue
+		}
+		var ids []string
+		for _, id := range strings.Split(value, ",") {
+			id = strings.TrimSpace(id)
+			if slices.Contains(defaultCardOrder, id) && !slices.Contains(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+		switch strings.TrimSpace(key) {
+		case "order":
+			for _, id := range defaultCardOrder {
+				if !slices.Contains(ids, id) {
+					ids = append(ids, id)
+				}
+			}
+			layout.Order = ids
+		case "hidden":
+			for _, id := range ids {
+				layout.Hidden[id] = true
+			}
+		}
+	}
+	return layout
+}
+
+// saveCardLayout writes layout to path, creating the config directory.
+func saveCardLayout(path string, layout cardLayout) error {
+	if path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	var hidden []string
+	for _, id := range layout.Order {
+		if layout.Hidden[id] {
+			hidden = append(hidden, id)
+		}
+	}
+	content := "order=" + strings.Join(layout.Order, ",") + "\nhidden=" + strings.Join(hidden, ",") + "\n"
+	return os.WriteFile(path, []byte(content), 0644)
+}
+
+// visible returns the shown card IDs in display order.
+func (l cardLayout) visible() []string {
+	var ids []string
+	for _, id := range l.Order {
+		if !l.Hidden[id] {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// move shifts id by delta places among the visible cards, landing next to
+// the visible card it passes. Hidden cards stay where they are.
+func (l cardLayout) move(id string, delta int) cardLayout {
+	visible := l.visible()
+	from := slices.Index(visible, id)
+	to := from + delta
+	if from < 0 || to < 0 || to >= len(visible) {
+		return l
+	}
+	order := slices.DeleteFunc(slices.Clone(l.Order), func(s string) bool { return s == id })
+	at := slices.Index(order, visible[to])
+	if delta > 0 {
+		at++
+	}
+	l.Order = slices.Insert(order, at, id)
+	return l
+}
+
+// hide returns the layout with id hidden.
+func (l cardLayout) hide(id string) cardLayout {
+	hidden := map[string]bool{id: true}
+	for k, v := range l.Hidden {
+		hidden[k] = v
+	}
+	l.Hidden = hidden
+	return l
+}
+
+// showAll returns the layout with every card visible.
+func (l cardLayout) showAll() cardLayout {
+	l.Hidden = map[string]bool{}
+	return l
+}
+
+// arrangeCards puts cards in layout order and drops hidden ones.
+func arrangeCards(cards []cardData, layout cardLayout) []cardData {
+	byID := make(map[string]cardData, len(cards))
+	for _, c := range cards {
+		byID[c.id] = c
+	}
+	arranged := make([]cardData, 0, len(cards))
+	for _, id := range layout.visible() {
+		if c, ok := byID[id]; ok {
+			arranged = append(arranged, c)
+		}
+	}
+	return arranged
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestLoadCardLayout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "status_layout")
+	if err := os.WriteFile(path, []byte("order=network,cpu,bogus,cpu\nhidden=power,bogus\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	layout := loadCardLayout(path)
+	want := []string{cardNetwork, cardCPU, cardMemory, cardDisk, cardPower, cardProcesses, cardGPU, cardSensors, cardPeripherals, cardContainers}
+	if !slices.Equal(layout.Order, want) {
+		t.Fatalf("order = %v, want %v", layout.Order, want)
+	}
+	if !layout.Hidden[cardPower] || len(layout.Hidden) != 1 {
+		t.Fatalf("hidden = %v, want only power", layout.Hidden)
+	}
+
+	missing := loadCardLayout(filepath.Join(t.TempDir(), "missing"))
+	if !slices.Equal(missing.Order, defaultCardOrder) || len(missing.Hidden) != 0 {
+		t.Fatalf("missing file should give the default layout, got %+v", missing)
+	}
+}
+
+func TestSaveCardLayoutRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mole", "status_layout")
+	layout := defaultCardLayout().move(cardDisk, -2).hide(cardProcesses)
+	if err := saveCardLayout(path, layout); err != nil {
+		t.Fatal(err)
+	}
+	loaded := loadCardLayout(path)
+	if !slices.Equal(loaded.Order, layout.Order) || !loaded.Hidden[cardProcesses] {
+		t.Fatalf("round trip = %+v, want %+v", loaded, layout)
+	}
+}
+
+func TestCardLayoutMoveSkipsHidden(t *testing.T) {
+	layout := defaultCardLayout().hide(cardMemory)
+	moved := layout.move(cardDisk, -1)
+	want := []string{cardDisk, cardCPU, cardMemory, cardPower, cardProcesses, cardNetwork, cardGPU, cardSensors, cardPeripherals, cardContainers}
+	if !slices.Equal(moved.Order, want) {
+		t.Fatalf("order = %v, want %v", moved.Order, want)
+	}
+	if !slices.Equal(layout.Order, defaultCardOrder) {
+		t.Fatalf("move should not change the original layout, got %v", layout.Order)
+	}
+	if edge := moved.move(cardDisk, -1); !slices.Equal(edge.Order, moved.Order) {
+		t.Fatalf("moving past the start should be a no-op, got %v", edge.Order)
+	}
+}
+
+func TestArrangeCards(t *testing.T) {
+	cards := buildCards(MetricsSnapshot{}, 0)
+	layout := defaultCardLayout().move(cardNetwork, -5).hide(cardPower)
+	arranged := arrangeCards(cards, layout)
+	var ids []string
+	for _, c := range arranged {
+		ids = append(ids, c.id)
+	}
+	want := []string{cardNetwork, cardCPU, cardMemory, cardDisk, cardProcesses}
+	if !slices.Equal(ids, want) {
+		t.Fatalf("arranged = %v, want %v", ids, want)
+	}
+}
+
+func TestCardFocusKeys(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	m := newModel()
+	m.ready = true
+	m.width = 120
+	m.metrics.CPU = CPUStatus{Usage: 20, PerCore: []float64{10, 20, 30, 40, 50}, LogicalCPU: 5}
+	m.metrics.Disks = []DiskStatus{
+		{Mount: "/", UsedPercent: 50}, {Mount: "/home"}, {Mount: "/data"}, {Mount: "/backup"},
+	}
+
+	press := func(key string) tea.Cmd {
+		t.Helper()
+		var msg tea.KeyMsg
+		switch key {
+		case "tab":
+			msg = tea.KeyMsg{Type: tea.KeyTab}
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		}
+		next, cmd := m.Update(msg)
+		m = next.(model)
+		return cmd
+	}
+
+	press("tab")
+	if !m.focusActive || m.focusCard != cardCPU {
+		t.Fatalf("first tab should focus the CPU card, got %q", m.focusCard)
+	}
+	if view := m.View(); !strings.Contains(view, "▶ "+iconCPU+" CPU") {
+		t.Fatalf("focused card should be highlighted:\n%s", view)
+	}
+
+	press("enter")
+	if m.detailCard != cardCPU {
+		t.Fatalf("enter should expand the CPU card, got %q", m.detailCard)
+	}
+	if view := m.View(); !strings.Contains(view, "Core5") {
+		t.Fatalf("CPU detail should list every core:\n%s", view)
+	}
+	press("esc")
+	if m.detailCard != "" {
+		t.Fatal("esc should leave the detail view")
+	}
+
+	press("tab")
+	press("tab")
+	press("enter")
+	if m.detailCard != cardDisk || !strings.Contains(m.View(), "/backup") {
+		t.Fatalf("disk detail should list every partition:\n%s", m.View())
+	}
+	press("esc")
+
+	press("]")
+	if m.layout.Order[3] != cardDisk {
+		t.Fatalf("] should move disk later, got %v", m.layout.Order)
+	}
+	press("x")
+	if !m.layout.Hidden[cardDisk] || m.focusCard != cardProcesses {
+		t.Fatalf("x should hide disk and focus the next card, got %+v focus %q", m.layout.Hidden, m.focusCard)
+	}
+	saved := loadCardLayout(getLayoutPath())
+	if !saved.Hidden[cardDisk] || saved.Order[3] != cardDisk {
+		t.Fatalf("layout should be saved, got %+v", saved)
+	}
+
+	press("a")
+	if len(m.layout.Hidden) != 0 {
+		t.Fatalf("a should show every card, got %v", m.layout.Hidden)
+	}
+
+	if cmd := press("esc"); cmd != nil || m.focusActive {
+		t.Fatal("esc should clear focus before quitting")
+	}
+
+	press("n")
+	if m.detailCard != cardNetwork || !strings.Contains(m.View(), "INTERFACE") {
+		t.Fatalf("n should open the network detail:\n%s", m.View())
+	}
+	press("esc")
+	if m.detailCard != "" {
+		t.Fatal("esc should close the network detail")
+	}
+}
+
+func TestFocusSkipsCardsWithoutData(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	m := newModel()
+	m.layout = defaultCardLayout()
+	m.focusCard, m.focusActive = cardCPU, true
+
+	m = m.moveFocus(-1)
+	if m.focusCard != cardNetwork {
+		t.Fatalf("focus should wrap to the last card with data, got %q", m.focusCard)
+	}
+
+	m.metrics.Sensors = []SensorReading{{Label: "acpitz", Value: 40, Unit: "°C"}}
+	m = m.moveFocus(1)
+	if m.focusCard != cardSensors {
+		t.Fatalf("focus should step over the empty GPU card, got %q", m.focusCard)
+	}
+}
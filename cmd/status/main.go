@@ -2,17 +2,26 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"slices"
 	"strings"
+	"syscall"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
-const refreshInterval = time.Second
+const (
+	refreshInterval = time.Second
+	// configNoticeTime is how long config file problems stay in the banner.
+	configNoticeTime = 10 * time.Second
+)
 
 var (
 	Version   = "dev"
@@ -27,6 +36,19 @@ type metricsMsg struct {
 	err  error
 }
 
+// replayMsg carries the next recorded snapshot and how long to show it.
+type replayMsg struct {
+	data  MetricsSnapshot
+	delay time.Duration
+	done  bool
+	err   error
+}
+
+type terminateMsg struct {
+	target ProcessInfo
+	err    error
+}
+
 type model struct {
 	collector   *Collector
 	width       int
@@ -38,6 +60,42 @@ type model struct {
 	collecting  bool
 	animFrame   int
 	catHidden   bool // true = hidden, false = visible
+
+	// Card focus and layout. focusCard stays set after esc so tab resumes
+	// where it left off; focusActive controls the highlight.
+	layout      cardLayout
+	focusCard   string
+	focusActive bool
+	detailCard  string // Card expanded to the full screen, if any
+
+	// Process pane.
+	showProcesses bool
+	procSort      processSort
+	procPID       int32        // Selected row; followed by PID across refreshes
+	confirmTerm   *ProcessInfo // Process awaiting terminate confirmation
+	procMessage   string
+
+	// Threshold alerts; firing rules show as a banner under the header.
+	alerts     *alertEngine
+	alertErr   error
+	alertSinks []alertSink
+
+	// Config file problems, shown in the banner for a while after startup.
+	configErr      error
+	configErrUntil time.Time
+
+	// Replay of a recording; nil when showing live metrics.
+	replay      *metricsReplay
+	replayFrame int
+	replayDone  bool
+
+	// Remote hosts streamed over SSH; when set nothing is collected
+	// locally. remoteOpen is the host whose cards are shown, -1 for the
+	// multi-host overview.
+	remotes    []*remoteHost
+	remoteFeed chan remoteUpdate
+	remoteOpen int
+	remoteSel  int
 }
 
 // getConfigPath returns the path to the status preferences file.
@@ -81,10 +139,34 @@ func saveCatHidden(hidden bool) {
 }
 
 func newModel() model {
+	rules, alertErr := loadAlertRules(getAlertConfigPath())
+	collector := NewCollector()
 	return model{
-		collector: NewCollector(),
-		catHidden: loadCatHidden(),
+		collector:      collector,
+		catHidden:      loadCatHidden(),
+		layout:         loadCardLayout(getLayoutPath()),
+		alerts:         newAlertEngine(rules),
+		alertErr:       alertErr,
+		configErr:      collector.ConfigErr(),
+		configErrUntil: time.Now().Add(configNoticeTime),
+	}
+}
+
+// withRemotes switches the model to hosts streamed over SSH, starting one
+// session per target until ctx is cancelled.
+func (m model) withRemotes(ctx context.Context, targets []string, command string) model {
+	m.remoteFeed = make(chan remoteUpdate, len(targets))
+	for i, target := range targets {
+		m.remotes = append(m.remotes, &remoteHost{target: target, alerts: newAlertEngine(m.alerts.rules)})
+		go runRemote(ctx, i, target, command, m.remoteFeed)
+	}
+	m.remoteOpen = 0
+	if len(targets) > 1 {
+		m.remoteOpen = -1
 	}
+	// Hosts show their own connecting state, so there is nothing to wait for.
+	m.ready = true
+	return m
 }
 
 func (m model) Init() tea.Cmd {
@@ -94,14 +176,86 @@ func (m model) Init() tea.Cmd {
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.remotes != nil && m.remoteOpen < 0 {
+			return m.updateOverviewKey(msg)
+		}
+		if m.showProcesses {
+			return m.updateProcessKey(msg)
+		}
+		if m.detailCard != "" {
+			return m.updateDetailKey(msg)
+		}
 		switch msg.String() {
-		case "q", "esc", "ctrl+c":
+		case "q", "ctrl+c":
+			return m, tea.Quit
+		case "esc":
+			if m.focusActive {
+				m.focusActive = false
+				return m, nil
+			}
+			if len(m.remotes) > 1 {
+				m.remoteOpen = -1
+				return m, nil
+			}
 			return m, tea.Quit
+		case "p":
+			m.showProcesses = true
+			m.procMessage = ""
+			return m, nil
+		case "n":
+			m.focusCard, m.detailCard = cardNetwork, cardNetwork
+			return m, nil
 		case "k":
 			// Toggle cat visibility and persist preference
 			m.catHidden = !m.catHidden
 			saveCatHidden(m.catHidden)
 			return m, nil
+		case "tab", "right", "down":
+			m = m.moveFocus(1)
+			return m, nil
+		case "shift+tab", "left", "up":
+			m = m.moveFocus(-1)
+			return m, nil
+		case "enter":
+			m = m.moveFocus(0)
+			if m.focusCard == cardProcesses {
+				m.showProcesses = true
+				m.procMessage = ""
+			} else if m.focusCard != "" {
+				m.detailCard = m.focusCard
+			}
+			return m, nil
+		case "x":
+			if m.focusActive && m.focusCard != "" {
+				hidden := m.focusCard
+				m = m.moveFocus(1)
+				m.layout = m.layout.hide(hidden)
+				if m.focusCard == hidden {
+					m.focusCard, m.focusActive = "", false
+				}
+				_ = saveCardLayout(getLayoutPath(), m.layout)
+			}
+			return m, nil
+		case "a":
+			m.layout = m.layout.showAll()
+			_ = saveCardLayout(getLayoutPath(), m.layout)
+			return m, nil
+		case "[", "]":
+			if m.focusActive && m.focusCard != "" {
+				delta := 1
+				if msg.String() == "[" {
+					delta = -1
+				}
+				// Step past cards with no data, which are not on screen.
+				shown := m.shownCards()
+				if to := slices.Index(shown, m.focusCard) + delta; to >= 0 && to < len(shown) {
+					visible := m.layout.visible()
+					delta = slices.Index(visible, shown[to]) - slices.Index(visible, m.focusCard)
+					m.layout = m.layout.move(m.focusCard, delta)
+				}
+				_ = saveCardLayout(getLayoutPath(), m.layout)
+			}
+			return m, nil
 		}
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
@@ -114,19 +268,47 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.collecting = true
 		return m, m.collectCmd()
 	case metricsMsg:
+		m = m.applyMetrics(msg.data, msg.err)
+		alertCmd := m.sendAlerts(m.alerts.Evaluate(msg.data))
+		return m, tea.Batch(tickAfter(refreshInterval), alertCmd)
+	case replayMsg:
+		if msg.done {
+			m.collecting = false
+			m.replayDone = true
+			m.ready = true
+			return m, nil
+		}
+		m.replayFrame++
+		m = m.applyMetrics(msg.data, msg.err)
+		alertCmd := m.sendAlerts(m.alerts.Evaluate(msg.data))
+		return m, tea.Batch(tickAfter(msg.delay), alertCmd)
+	case remoteUpdate:
+		host := m.remotes[msg.host]
+		var alertCmd tea.Cmd
 		if msg.err != nil {
-			m.errMessage = msg.err.Error()
+			host.err = msg.err
 		} else {
-			m.errMessage = ""
+			host.latest, host.err, host.updated = msg.data, nil, time.Now()
+			// Every host keeps its own rule state, open or not.
+			alertCmd = m.sendAlerts(host.evaluateAlerts(msg.data))
 		}
-		m.metrics = msg.data
-		m.lastUpdated = msg.data.CollectedAt
-		m.collecting = false
-		// Mark ready after first successful data collection.
-		if !m.ready {
-			m.ready = true
+		if msg.host == m.remoteOpen {
+			if msg.err != nil {
+				m.errMessage = msg.err.Error()
+			} else {
+				m = m.applyMetrics(msg.data, msg.collectErr)
+			}
 		}
-		return m, tickAfter(refreshInterval)
+		// Hosts push snapshots at their own pace; keep listening.
+		m.collecting = true
+		return m, tea.Batch(m.collectCmd(), alertCmd)
+	case terminateMsg:
+		if msg.err != nil {
+			m.procMessage = fmt.Sprintf("Could not terminate %s (%d): %v", msg.target.Name, msg.target.PID, msg.err)
+		} else {
+			m.procMessage = fmt.Sprintf("Sent SIGTERM to %s (%d)", msg.target.Name, msg.target.PID)
+		}
+		return m, nil
 	case animTickMsg:
 		m.animFrame++
 		return m, animTickWithSpeed(m.metrics.CPU.Usage)
@@ -134,17 +316,269 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// shownCards returns the visible card IDs that have data to draw.
+func (m model) shownCards() []string {
+	var ids []string
+	for _, id := range m.layout.visible() {
+		if cardPresent(id, m.metrics) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// moveFocus shows the focus highlight and moves it delta visible cards
+// along, wrapping at the ends. A focus on a card that was since hidden
+// starts over from the first card.
+func (m model) moveFocus(delta int) model {
+	visible := m.shownCards()
+	if len(visible) == 0 {
+		m.focusCard, m.focusActive = "", false
+		return m
+	}
+	i := slices.Index(visible, m.focusCard)
+	switch {
+	case i < 0:
+		i = 0
+	case m.focusActive:
+		i = (i + delta + len(visible)) % len(visible)
+	}
+	m.focusCard = visible[i]
+	m.focusActive = true
+	return m
+}
+
+// updateOverviewKey handles keys in the multi-host overview.
+func (m model) updateOverviewKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "ctrl+c", "esc":
+		return m, tea.Quit
+	case "up", "k":
+		m.remoteSel = max(m.remoteSel-1, 0)
+	case "down", "j":
+		m.remoteSel = min(m.remoteSel+1, len(m.remotes)-1)
+	case "enter":
+		host := m.remotes[m.remoteSel]
+		m.remoteOpen = m.remoteSel
+		m.detailCard, m.showProcesses, m.focusActive = "", false, false
+		m.metrics, m.errMessage = host.latest, ""
+		if host.err != nil {
+			m.errMessage = host.err.Error()
+		}
+	}
+	return m, nil
+}
+
+// updateDetailKey handles keys while a card is expanded.
+func (m model) updateDetailKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "esc", "enter", "backspace":
+		m.detailCard = ""
+	case "tab", "shift+tab":
+		delta := 1
+		if msg.String() == "shift+tab" {
+			delta = -1
+		}
+		m = m.moveFocus(delta)
+		// The process list has its own pane; step over it.
+		if m.focusCard == cardProcesses {
+			m = m.moveFocus(delta)
+		}
+		m.detailCard = m.focusCard
+		if m.detailCard == cardProcesses {
+			m.detailCard = ""
+		}
+	}
+	return m, nil
+}
+
+// updateProcessKey handles keys while the process pane is open. A pending
+// terminate confirmation takes every key: y confirms, anything else cancels.
+func (m model) updateProcessKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.confirmTerm != nil {
+		target := *m.confirmTerm
+		m.confirmTerm = nil
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "y", "Y", "enter":
+			m.procMessage = fmt.Sprintf("Terminating %s (%d)...", target.Name, target.PID)
+			return m, terminateCmd(target)
+		}
+		m.procMessage = ""
+		return m, nil
+	}
+
+	procs := m.sortedProcesses()
+	selected := selectedProcessIndex(procs, m.procPID)
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "esc", "p":
+		m.showProcesses = false
+		m.procMessage = ""
+	case "up", "k":
+		if selected > 0 {
+			m.procPID = procs[selected-1].PID
+		}
+	case "down", "j":
+		if selected+1 < len(procs) {
+			m.procPID = procs[selected+1].PID
+		}
+	case "home", "g":
+		if len(procs) > 0 {
+			m.procPID = procs[0].PID
+		}
+	case "end", "G":
+		if len(procs) > 0 {
+			m.procPID = procs[len(procs)-1].PID
+		}
+	case "s":
+		m.procSort = m.procSort.next()
+	case "c":
+		m.procSort = processSortCPU
+	case "m":
+		m.procSort = processSortMemory
+	case "i":
+		m.procSort = processSortIO
+	case "x", "delete":
+		if m.replay != nil {
+			m.procMessage = "Replaying a recording; processes cannot be terminated"
+		} else if m.remotes != nil {
+			m.procMessage = "Processes on a remote host cannot be terminated"
+		} else if selected < len(procs) {
+			target := procs[selected]
+			m.confirmTerm = &target
+			m.procMessage = ""
+		}
+	}
+	return m, nil
+}
+
+// sortedProcesses returns the latest process list in the pane's order.
+func (m model) sortedProcesses() []ProcessInfo {
+	procs := append([]ProcessInfo(nil), m.metrics.Processes...)
+	sortProcesses(procs, m.procSort)
+	return procs
+}
+
+// selectedProcessIndex finds pid in procs, falling back to the first row
+// when it has exited or nothing was selected yet.
+func selectedProcessIndex(procs []ProcessInfo, pid int32) int {
+	for i, p := range procs {
+		if p.PID == pid {
+			return i
+		}
+	}
+	return 0
+}
+
+func terminateCmd(target ProcessInfo) tea.Cmd {
+	return func() tea.Msg {
+		return terminateMsg{target: target, err: terminateProcess(target)}
+	}
+}
+
+// applyMetrics shows a new snapshot. Alert rules are run by the caller,
+// since remote hosts evaluate theirs whether or not they are on screen.
+func (m model) applyMetrics(data MetricsSnapshot, collectErr error) model {
+	var problems []string
+	for _, err := range []error{collectErr, m.alertErr} {
+		if err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	if m.configErr != nil && time.Now().Before(m.configErrUntil) {
+		problems = append(problems, m.configErr.Error())
+	}
+	m.errMessage = strings.Join(problems, "; ")
+	m.metrics = data
+	m.lastUpdated = data.CollectedAt
+	m.collecting = false
+	// Mark ready after first successful data collection.
+	if !m.ready {
+		m.ready = true
+	}
+	return m
+}
+
+// sendAlerts delivers events to the configured sinks, if any.
+func (m model) sendAlerts(events []alertEvent) tea.Cmd {
+	if len(events) == 0 || len(m.alertSinks) == 0 {
+		return nil
+	}
+	return alertCmd(m.alertSinks, events)
+}
+
+// firingAlerts returns the rules firing for the host on screen.
+func (m model) firingAlerts() []alertEvent {
+	if m.remotes != nil && m.remoteOpen >= 0 {
+		return m.remotes[m.remoteOpen].alerts.Firing()
+	}
+	return m.alerts.Firing()
+}
+
+// alertCmd delivers events off the UI goroutine; failures are dropped since
+// the banner already shows what fired.
+func alertCmd(sinks []alertSink, events []alertEvent) tea.Cmd {
+	return func() tea.Msg {
+		_ = dispatchAlerts(sinks, events)
+		return nil
+	}
+}
+
 func (m model) View() string {
 	if !m.ready {
 		return "Loading..."
 	}
 
+	if m.remotes != nil && m.remoteOpen < 0 {
+		return renderHostOverview(m.remotes, m.remoteSel, time.Now(), m.width)
+	}
+	if m.remotes != nil && m.remotes[m.remoteOpen].updated.IsZero() {
+		return renderRemoteConnecting(m.remotes[m.remoteOpen], len(m.remotes) > 1)
+	}
+
 	header := renderHeader(m.metrics, m.errMessage, m.animFrame, m.width, m.catHidden)
+	if m.remotes != nil {
+		header += "\n" + renderRemoteStatus(m.remotes[m.remoteOpen], time.Now(), len(m.remotes) > 1)
+	}
+	if m.replay != nil {
+		header += "\n" + renderReplayStatus(m.metrics.CollectedAt, m.replayFrame, m.replayDone)
+	}
+	if banner := renderAlertBanner(m.firingAlerts(), m.width); banner != "" {
+		header += "\n" + banner
+	}
+	if m.showProcesses {
+		height := 0
+		if m.height > 0 {
+			height = max(m.height-lipgloss.Height(header)-1, 8)
+		}
+		return header + "\n" + renderProcessPane(m.sortedProcesses(), m.procPID, m.procSort, m.confirmTerm, m.procMessage, m.width, height)
+	}
+	if m.detailCard != "" {
+		height := 0
+		if m.height > 0 {
+			height = max(m.height-lipgloss.Height(header)-1, 8)
+		}
+		return header + "\n" + renderCardDetail(m.detailCard, m.metrics, m.width, height)
+	}
 	cardWidth := 0
 	if m.width > 80 {
 		cardWidth = maxInt(24, m.width/2-4)
 	}
-	cards := buildCards(m.metrics, cardWidth)
+	cards := arrangeCards(buildCards(m.metrics, cardWidth), m.layout)
+	for i := range cards {
+		cards[i].focused = m.focusActive && cards[i].id == m.focusCard
+	}
+	if len(cards) == 0 {
+		return header + "\n\n" + subtleStyle.Render("All cards are hidden, press a to show them again")
+	}
+	if m.focusActive {
+		header += "\n" + subtleStyle.Render("tab Focus  enter Details  x Hide  a Show all  [ ] Move  esc Done")
+	}
 
 	if m.width <= 80 {
 		var rendered []string
@@ -171,6 +605,19 @@ func (m model) View() string {
 }
 
 func (m model) collectCmd() tea.Cmd {
+	if m.remotes != nil {
+		feed := m.remoteFeed
+		return func() tea.Msg { return <-feed }
+	}
+	if m.replay != nil {
+		replay := m.replay
+		return func() tea.Msg {
+			data, delay, ok, err := replay.Next()
+			return replayMsg{data: data, delay: delay, done: !ok, err: err}
+		}
+	}
+	m.collector.setProcessDetail(m.showProcesses)
+	m.collector.setConnectionDetail(m.detailCard == cardNetwork)
 	return func() tea.Msg {
 		data, err := m.collector.Collect()
 		return metricsMsg{data: data, err: err}
@@ -192,7 +639,116 @@ func animTickWithSpeed(cpuUsage float64) tea.Cmd {
 }
 
 func main() {
-	p := tea.NewProgram(newModel(), tea.WithAltScreen())
+	jsonOutput := flag.Bool("json", false, "print one metrics snapshot as JSON and exit")
+	static := flag.Bool("static", false, "with --json, print only host, memory and disk figures without sampling")
+	watch := flag.Bool("watch", false, "stream metrics snapshots as NDJSON until interrupted")
+	serveAddr := flag.String("serve", "", "serve OpenMetrics on `addr` (e.g. :9105) at /metrics")
+	alertsOnly := flag.Bool("alerts", false, "check alert rules in the background without the UI")
+	notify := flag.Bool("notify", false, "send desktop notifications when alert rules fire")
+	record := flag.Bool("record", false, "record every snapshot under ~/.cache/mole/status for later --replay")
+	replayPath := flag.String("replay", "", "replay a recorded segment `file` or directory in the UI")
+	speed := flag.Float64("speed", 1, "playback speed multiplier for --replay")
+	interval := flag.Duration("interval", defaultWatchInterval, "time between snapshots in --watch, --serve and --alerts modes")
+	var hosts hostList
+	flag.Var(&hosts, "host", "show `user@host` over SSH instead of this machine; repeat or comma-separate for an overview")
+	remoteCommand := flag.String("remote-command", defaultRemoteCommand, "`command` that runs Mole status on remote hosts")
+	flag.Parse()
+
+	if (*watch || *serveAddr != "" || *alertsOnly) && *interval <= 0 {
+		fmt.Fprintln(os.Stderr, "usage: status-go --watch|--serve :9105|--alerts [--interval 5s], interval must be positive")
+		os.Exit(2)
+	}
+
+	var recorder *metricsRecorder
+	if *record {
+		dir, err := getRecordDir()
+		if err == nil {
+			recorder, err = newMetricsRecorder(dir)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cannot record metrics: %v\n", err)
+			os.Exit(1)
+		}
+		defer recorder.Close() //nolint:errcheck
+	}
+	newCollector := func() *Collector {
+		c := NewCollector()
+		c.recorder = recorder
+		if err := c.ConfigErr(); err != nil {
+			fmt.Fprintf(os.Stderr, "config: %v\n", err)
+		}
+		return c
+	}
+
+	if *alertsOnly {
+		rules, err := loadAlertRules(getAlertConfigPath())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "alert rules: %v\n", err)
+		}
+		if len(rules) == 0 {
+			fmt.Fprintf(os.Stderr, "no alert rules found in %s\n", getAlertConfigPath())
+			os.Exit(1)
+		}
+		sinks := []alertSink{logAlertSink{w: os.Stdout}}
+		if *notify {
+			sinks = append(sinks, desktopAlertSink{})
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		runAlerts(ctx, newCollector(), newAlertEngine(rules), *interval, sinks, os.Stderr)
+		return
+	}
+
+	if *serveAddr != "" {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		if err := runServe(ctx, newCollector(), *serveAddr, *interval); err != nil {
+			fmt.Fprintf(os.Stderr, "system status error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if *watch {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		if err := runWatch(ctx, newCollector(), *interval, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "system status error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if *jsonOutput {
+		run := runJSONSnapshot
+		if *static {
+			run = runStaticJSONSnapshot
+		}
+		if err := run(newCollector(), os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "system status error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	m := newModel()
+	m.collector.recorder = recorder
+	if *replayPath != "" {
+		replay, err := newMetricsReplay(*replayPath, *speed)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cannot replay %s: %v\n", *replayPath, err)
+			os.Exit(1)
+		}
+		defer replay.Close()
+		m.replay = replay
+	}
+	if len(hosts) > 0 {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		m = m.withRemotes(ctx, hosts, *remoteCommand)
+	}
+	if *notify {
+		m.alertSinks = append(m.alertSinks, desktopAlertSink{})
+	}
+	p := tea.NewProgram(m, tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
 		fmt.Fprintf(os.Stderr, "system status error: %v\n", err)
 		os.Exit(1)
@@ -2,9 +2,12 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os/exec"
+	"runtime"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/shirou/gopsutil/v4/disk"
@@ -57,28 +60,35 @@ func (rb *RingBuffer) Slice() []float64 {
 }
 
 type MetricsSnapshot struct {
-	CollectedAt    time.Time
-	Host           string
-	Platform       string
-	Uptime         string
-	Procs          uint64
-	Hardware       HardwareInfo
-	HealthScore    int    // 0-100 system health score
-	HealthScoreMsg string // Brief explanation
+	CollectedAt     time.Time
+	Host            string
+	Platform        string
+	Uptime          string
+	UptimeSeconds   uint64
+	Procs           uint64
+	Hardware        HardwareInfo
+	HealthScore     int    // 0-100 system health score
+	HealthScoreMsg  string // Brief explanation
+	HealthPenalties []HealthPenalty
 
 	CPU            CPUStatus
 	GPU            []GPUStatus
 	Memory         MemoryStatus
 	Disks          []DiskStatus
 	DiskIO         DiskIOStatus
+	DiskHealth     []DiskHealth // smartctl results, refreshed every few minutes
 	Network        []NetworkStatus
 	NetworkHistory NetworkHistory
+	History        MetricHistory
 	Proxy          ProxyStatus
 	Batteries      []BatteryStatus
 	Thermal        ThermalStatus
 	Sensors        []SensorReading
 	Bluetooth      []BluetoothDevice
+	Containers     []ContainerStatus
+	Connections    []ConnectionInfo // Active TCP/UDP sockets, refreshed every few seconds
 	TopProcesses   []ProcessInfo
+	Processes      []ProcessInfo // Every readable process, sorted by CPU
 }
 
 type HardwareInfo struct {
@@ -93,12 +103,44 @@ type HardwareInfo struct {
 type DiskIOStatus struct {
 	ReadRate  float64 // MB/s
 	WriteRate float64 // MB/s
+	Devices   []DiskDeviceIO
+}
+
+// DiskDeviceIO is one whole disk's activity since the previous collection.
+type DiskDeviceIO struct {
+	Name      string
+	ReadRate  float64 // MB/s
+	WriteRate float64 // MB/s
+	ReadIOPS  float64
+	WriteIOPS float64
+	Await     float64 // Average milliseconds per completed IO, queueing included
+	Busy      float64 // Percent of the interval with IO in flight
+}
+
+// DiskHealth is a drive's SMART or NVMe health log as read by smartctl.
+type DiskHealth struct {
+	Device       string // /dev/nvme0
+	Model        string
+	Protocol     string // NVMe, ATA or SCSI
+	Passed       bool   // Overall SMART self-assessment
+	WearKnown    bool   // PercentUsed was reported
+	PercentUsed  float64
+	MediaErrors  uint64 // NVMe media errors, or reallocated+pending+uncorrectable ATA sectors
+	Temperature  float64
+	PowerOnHours uint64
 }
 
 type ProcessInfo struct {
-	Name   string
-	CPU    float64
-	Memory float64
+	PID        int32
+	Name       string
+	User       string
+	Command    string  // Full command line, or Name when it cannot be read
+	CPU        float64 // Percent of one core
+	Memory     float64 // Percent of total memory
+	RSS        uint64
+	Threads    int32
+	IORate     float64 // Disk read+write in MB/s
+	CreateTime int64   // Milliseconds since epoch; tells reused PIDs apart
 }
 
 type CPUStatus struct {
@@ -110,8 +152,11 @@ type CPUStatus struct {
 	Load15           float64
 	CoreCount        int
 	LogicalCPU       int
-	PCoreCount       int // Performance cores (Apple Silicon)
-	ECoreCount       int // Efficiency cores (Apple Silicon)
+	PCoreCount       int     // Performance cores (Apple Silicon)
+	ECoreCount       int     // Efficiency cores (Apple Silicon)
+	CgroupQuota      float64 // cgroup v2 cpu.max in cores, 0 when unlimited
+	CgroupUsage      float64 // cgroup CPU use as a percent of CgroupQuota
+	CgroupThrottled  float64 // Percent of cgroup scheduler periods throttled
 }
 
 type GPUStatus struct {
@@ -130,7 +175,9 @@ type MemoryStatus struct {
 	SwapUsed    uint64
 	SwapTotal   uint64
 	Cached      uint64 // File cache that can be freed if needed
-	Pressure    string // macOS memory pressure: normal/warn/critical
+	Pressure    string // Memory pressure: normal/warn/critical (memory_pressure on macOS, PSI on Linux)
+	CgroupLimit uint64 // cgroup v2 memory.max, 0 when unlimited
+	CgroupUsed  uint64 // cgroup v2 memory.current, set with CgroupLimit
 }
 
 type DiskStatus struct {
@@ -148,16 +195,45 @@ type NetworkStatus struct {
 	RxRateMBs float64
 	TxRateMBs float64
 	IP        string
+
+	// Counters since mole started watching the interface.
+	RxTotal uint64 // Bytes
+	TxTotal uint64 // Bytes
+	ErrIn   uint64
+	ErrOut  uint64
+	DropIn  uint64
+	DropOut uint64
 }
 
-// NetworkHistory holds the global network usage history.
+// NetworkHistory holds the global network usage history, and each
+// interface's for the detail view.
 type NetworkHistory struct {
-	RxHistory []float64
-	TxHistory []float64
+	RxHistory  []float64
+	TxHistory  []float64
+	Interfaces map[string]InterfaceHistory
+}
+
+type InterfaceHistory struct {
+	Rx []float64 // MB/s
+	Tx []float64 // MB/s
 }
 
 const NetworkHistorySize = 120 // Increased history size for wider graph
 
+// MetricHistory holds the recent samples behind the card trend lines,
+// oldest first.
+type MetricHistory struct {
+	CPU       []float64   // Total usage percent
+	PerCore   [][]float64 // Usage percent per core index
+	Memory    []float64   // Used percent
+	Swap      []float64   // Swap used percent
+	DiskRead  []float64   // MB/s
+	DiskWrite []float64   // MB/s
+	GPU       []float64   // Usage percent of the first GPU
+}
+
+const MetricHistorySize = 120
+
 type ProxyStatus struct {
 	Enabled bool
 	Type    string // HTTP, SOCKS, System
@@ -178,7 +254,7 @@ type ThermalStatus struct {
 	GPUTemp      float64
 	FanSpeed     int
 	FanCount     int
-	SystemPower  float64 // System power consumption in Watts
+	SystemPower  float64 // System power consumption in Watts (RAPL package or platform power on Linux)
 	AdapterPower float64 // AC adapter max power in Watts
 	BatteryPower float64 // Battery charge/discharge power in Watts (positive = discharging)
 }
@@ -190,6 +266,26 @@ type SensorReading struct {
 	Note  string
 }
 
+type ContainerStatus struct {
+	ID       string
+	Name     string
+	Image    string
+	Engine   string // docker or podman
+	State    string
+	CPU      float64 // Percent of one core
+	MemUsed  uint64
+	MemLimit uint64 // 0 when unlimited
+}
+
+type ConnectionInfo struct {
+	PID     int32
+	Process string
+	Proto   string // tcp, tcp6, udp or udp6
+	Local   string
+	Remote  string // Empty for unconnected UDP sockets
+	Status  string
+}
+
 type BluetoothDevice struct {
 	Name      string
 	Connected bool
@@ -197,6 +293,15 @@ type BluetoothDevice struct {
 }
 
 type Collector struct {
+	// Health score model and noise interfaces from the config files.
+	health  healthModel
+	network networkConfig
+	// Problems in those files; see ConfigErr.
+	configErr error
+
+	// Optional on-disk recording of every snapshot.
+	recorder *metricsRecorder
+
 	// Static cache.
 	cachedHW  HardwareInfo
 	lastHWAt  time.Time
@@ -206,25 +311,83 @@ type Collector struct {
 	lastBTAt time.Time
 	lastBT   []BluetoothDevice
 
+	// SMART data barely changes and smartctl is slow, so it is refreshed
+	// in the background; smartMu guards these against that goroutine.
+	smartMu     sync.Mutex
+	smartBusy   bool
+	smartDone   sync.WaitGroup
+	lastSmartAt time.Time
+	lastSmart   []DiskHealth
+
+	// Connections are walked every few seconds while the network detail
+	// view is open; see setConnectionDetail.
+	lastConnAt       time.Time
+	lastConns        []ConnectionInfo
+	connectionDetail atomic.Bool
+
 	// Fast metrics (1s).
 	prevNet      map[string]net.IOCountersStat
+	startNet     map[string]net.IOCountersStat // First counters seen per interface
 	lastNetAt    time.Time
 	rxHistoryBuf *RingBuffer
 	txHistoryBuf *RingBuffer
-	lastGPUAt    time.Time
-	cachedGPU    []GPUStatus
-	prevDiskIO   disk.IOCountersStat
-	lastDiskAt   time.Time
+	ifaceHistory map[string]*interfaceHistoryBuf
+
+	// Card trend history.
+	cpuHistoryBuf    *RingBuffer
+	coreHistoryBufs  []*RingBuffer
+	memHistoryBuf    *RingBuffer
+	swapHistoryBuf   *RingBuffer
+	readHistoryBuf   *RingBuffer
+	writeHistoryBuf  *RingBuffer
+	gpuHistoryBuf    *RingBuffer
+	lastGPUAt        time.Time
+	cachedGPU        []GPUStatus
+	prevDiskIO       map[string]disk.IOCountersStat
+	lastDiskAt       time.Time
+	prevProcs        map[int32]processSample
+	processDetail    atomic.Bool // Process pane is open; see setProcessDetail
+	lastProcAt       time.Time
+	prevRAPL         raplSample
+	prevCgroup       cgroupSample
+	prevContainers   map[string]containerCPUSample
+	lastContainersAt time.Time
+	lastContainers   []ContainerStatus
 }
 
 func NewCollector() *Collector {
+	health, configErr := loadHealthModel(getHealthConfigPath())
+	network, networkErr := loadNetworkConfig(getNetworkConfigPath())
+	if configErr == nil {
+		configErr = networkErr
+	} else if networkErr != nil {
+		configErr = fmt.Errorf("%v; %w", configErr, networkErr)
+	}
 	return &Collector{
+		health:       health,
+		network:      network,
+		configErr:    configErr,
 		prevNet:      make(map[string]net.IOCountersStat),
+		startNet:     make(map[string]net.IOCountersStat),
 		rxHistoryBuf: NewRingBuffer(NetworkHistorySize),
 		txHistoryBuf: NewRingBuffer(NetworkHistorySize),
+
+		cpuHistoryBuf:   NewRingBuffer(MetricHistorySize),
+		memHistoryBuf:   NewRingBuffer(MetricHistorySize),
+		swapHistoryBuf:  NewRingBuffer(MetricHistorySize),
+		readHistoryBuf:  NewRingBuffer(MetricHistorySize),
+		writeHistoryBuf: NewRingBuffer(MetricHistorySize),
+		gpuHistoryBuf:   NewRingBuffer(MetricHistorySize),
 	}
 }
 
+// ConfigErr reports problems in the config files read by NewCollector.
+// Bad lines are skipped, so Collect works regardless and leaves it to the
+// caller to show these once.
+func (c *Collector) ConfigErr() error {
+	return c.configErr
+}
+
 func (c *Collector) Collect() (MetricsSnapshot, error) {
 	now := time.Now()
 
@@ -240,6 +403,7 @@ func (c *Collector) Collect() (MetricsSnapshot, error) {
 		memStats     MemoryStatus
 		diskStats    []DiskStatus
 		diskIO       DiskIOStatus
+		diskHealth   []DiskHealth
 		netStats     []NetworkStatus
 		proxyStats   ProxyStatus
 		batteryStats []BatteryStatus
@@ -247,7 +411,10 @@ func (c *Collector) Collect() (MetricsSnapshot, error) {
 		sensorStats  []SensorReading
 		gpuStats     []GPUStatus
 		btStats      []BluetoothDevice
-		topProcs     []ProcessInfo
+		procStats    []ProcessInfo
+		cgroupStats  cgroupStatus
+		containers   []ContainerStatus
+		connStats    []ConnectionInfo
 	)
 
 	// Helper to launch concurrent collection.
@@ -272,25 +439,31 @@ func (c *Collector) Collect() (MetricsSnapshot, error) {
 	collect(func() (err error) { memStats, err = collectMemory(); return })
 	collect(func() (err error) { diskStats, err = collectDisks(); return })
 	collect(func() (err error) { diskIO = c.collectDiskIO(now); return nil })
+	// smartctl is slow and often needs root; collectDiskHealth refreshes
+	// in the background every few minutes.
+	diskHealth = c.collectDiskHealth(now)
 	collect(func() (err error) { netStats, err = c.collectNetwork(now); return })
+	// Walking sockets is slow: only for the network detail view, and
+	// collectConnections caches for a few seconds.
+	if c.connectionDetail.Load() {
+		collect(func() (err error) { connStats, _ = c.collectConnections(now); return nil })
+	} else {
+		c.lastConnAt = time.Time{}
+	}
 	collect(func() (err error) { proxyStats = collectProxy(); return nil })
 	collect(func() (err error) { batteryStats, _ = collectBatteries(); return nil })
-	collect(func() (err error) { thermalStats = collectThermal(); return nil })
-	// Sensors disabled - CPU temp already shown in CPU card
-	// collect(func() (err error) { sensorStats, _ = collectSensors(); return nil })
+	collect(func() (err error) { thermalStats = c.collectThermal(now); return nil })
+	collect(func() (err error) { sensorStats, _ = collectSensors(); return nil })
 	collect(func() (err error) { gpuStats, err = c.collectGPU(now); return })
-	collect(func() (err error) {
-		// Bluetooth is slow; cache for 30s.
-		if now.Sub(c.lastBTAt) > 30*time.Second || len(c.lastBT) == 0 {
-			btStats = c.collectBluetooth(now)
-			c.lastBT = btStats
-			c.lastBTAt = now
-		} else {
-			btStats = c.lastBT
-		}
-		return nil
-	})
-	collect(func() (err error) { topProcs = collectTopProcesses(); return nil })
+	// Bluetooth is slow; collectBluetooth caches for 30s.
+	collect(func() (err error) { btStats = c.collectBluetooth(now); return nil })
+	collect(func() (err error) { procStats = c.collectProcesses(now); return nil })
+	if runtime.GOOS == "linux" {
+		collect(func() (err error) { cgroupStats = c.collectCgroup(sysfsRoot(), procfsRoot(), now); return nil })
+	}
+	// Engines that refuse the socket (no docker group) just show nothing;
+	// collectContainers caches for a few seconds.
+	collect(func() (err error) { containers, _ = c.collectContainers(containerEngines(), now); return nil })
 
 	// Wait for all to complete.
 	wg.Wait()
@@ -304,34 +477,119 @@ func (c *Collector) Collect() (MetricsSnapshot, error) {
 	}
 	hwInfo := c.cachedHW
 
-	score, scoreMsg := calculateHealthScore(cpuStats, memStats, diskStats, diskIO, thermalStats)
+	cgroupStats.apply(&cpuStats, &memStats)
+	// Docker reports the host's memory as the limit of unlimited containers.
+	for i := range containers {
+		if memStats.Total > 0 && containers[i].MemLimit >= memStats.Total {
+			containers[i].MemLimit = 0
+		}
+	}
+
+	setMemoryPercent(procStats, memStats.Total)
+	topProcs := procStats[:min(len(procStats), topProcessCount)]
+
+	history := c.updateHistory(cpuStats, memStats, diskIO, gpuStats)
+
+	health := scoreHealth(c.health, cpuStats, memStats, diskStats, diskIO, thermalStats, netStats, batteryStats, diskHealth)
+
+	snapshot := MetricsSnapshot{
+		CollectedAt:     now,
+		Host:            hostInfo.Hostname,
+		Platform:        fmt.Sprintf("%s %s", hostInfo.Platform, hostInfo.PlatformVersion),
+		Uptime:          formatUptime(hostInfo.Uptime),
+		UptimeSeconds:   hostInfo.Uptime,
+		Procs:           hostInfo.Procs,
+		Hardware:        hwInfo,
+		HealthScore:     health.Score,
+		HealthScoreMsg:  health.Message,
+		HealthPenalties: health.Penalties,
+		CPU:             cpuStats,
+		GPU:             gpuStats,
+		Memory:          memStats,
+		Disks:           diskStats,
+		DiskIO:          diskIO,
+		DiskHealth:      diskHealth,
+		Network:         netStats,
+		NetworkHistory:  c.networkHistory(),
+		History:         history,
+		Proxy:           proxyStats,
+		Batteries:       batteryStats,
+		Thermal:         thermalStats,
+		Sensors:         sensorStats,
+		Bluetooth:       btStats,
+		Containers:      containers,
+		Connections:     namedConnections(connStats, c.prevProcs),
+		TopProcesses:    topProcs,
+		Processes:       procStats,
+	}
+	if c.recorder != nil {
+		if err := c.recorder.Record(snapshot); err != nil {
+			if mergeErr == nil {
+				mergeErr = err
+			} else {
+				mergeErr = fmt.Errorf("%v; %w", mergeErr, err)
+			}
+		}
+	}
+	return snapshot, mergeErr
+}
 
+// CollectStatic reads host, memory and disk figures only. It needs no
+// earlier sample and runs none of the slow probes, for callers that want
+// those numbers quickly.
+func (c *Collector) CollectStatic() (MetricsSnapshot, error) {
+	hostInfo, _ := host.Info()
+	memStats, memErr := collectMemory()
+	diskStats, diskErr := collectDisks()
 	return MetricsSnapshot{
-		CollectedAt:    now,
-		Host:           hostInfo.Hostname,
-		Platform:       fmt.Sprintf("%s %s", hostInfo.Platform, hostInfo.PlatformVersion),
-		Uptime:         formatUptime(hostInfo.Uptime),
-		Procs:          hostInfo.Procs,
-		Hardware:       hwInfo,
-		HealthScore:    score,
-		HealthScoreMsg: scoreMsg,
-		CPU:            cpuStats,
-		GPU:            gpuStats,
-		Memory:         memStats,
-		Disks:          diskStats,
-		DiskIO:         diskIO,
-		Network:        netStats,
-		NetworkHistory: NetworkHistory{
-			RxHistory: c.rxHistoryBuf.Slice(),
-			TxHistory: c.txHistoryBuf.Slice(),
-		},
-		Proxy:        proxyStats,
-		Batteries:    batteryStats,
-		Thermal:      thermalStats,
-		Sensors:      sensorStats,
-		Bluetooth:    btStats,
-		TopProcesses: topProcs,
-	}, mergeErr
+		CollectedAt:   time.Now(),
+		Host:          hostInfo.Hostname,
+		Platform:      fmt.Sprintf("%s %s", hostInfo.Platform, hostInfo.PlatformVersion),
+		Uptime:        formatUptime(hostInfo.Uptime),
+		UptimeSeconds: hostInfo.Uptime,
+		Procs:         hostInfo.Procs,
+		Memory:        memStats,
+		Disks:         diskStats,
+	}, errors.Join(memErr, diskErr)
+}
+
+// updateHistory adds this collection to the trend buffers and returns
+// their contents. Per-core buffers follow the current core count.
+func (c *Collector) updateHistory(cpu CPUStatus, mem MemoryStatus, diskIO DiskIOStatus, gpus []GPUStatus) MetricHistory {
+	c.cpuHistoryBuf.Add(cpu.Usage)
+	c.memHistoryBuf.Add(mem.UsedPercent)
+	swapPercent := 0.0
+	if mem.SwapTotal > 0 {
+		swapPercent = float64(mem.SwapUsed) / float64(mem.SwapTotal) * 100
+	}
+	c.swapHistoryBuf.Add(swapPercent)
+	c.readHistoryBuf.Add(diskIO.ReadRate)
+	c.writeHistoryBuf.Add(diskIO.WriteRate)
+	if len(gpus) > 0 && gpus[0].Usage >= 0 {
+		c.gpuHistoryBuf.Add(gpus[0].Usage)
+	}
+
+	if len(c.coreHistoryBufs) != len(cpu.PerCore) {
+		c.coreHistoryBufs = make([]*RingBuffer, len(cpu.PerCore))
+		for i := range c.coreHistoryBufs {
+			c.coreHistoryBufs[i] = NewRingBuffer(MetricHistorySize)
+		}
+	}
+	perCore := make([][]float64, len(cpu.PerCore))
+	for i, usage := range cpu.PerCore {
+		c.coreHistoryBufs[i].Add(usage)
+		perCore[i] = c.coreHistoryBufs[i].Slice()
+	}
+
+	return MetricHistory{
+		CPU:       c.cpuHistoryBuf.Slice(),
+		PerCore:   perCore,
+		Memory:    c.memHistoryBuf.Slice(),
+		Swap:      c.swapHistoryBuf.Slice(),
+		DiskRead:  c.readHistoryBuf.Slice(),
+		DiskWrite: c.writeHistoryBuf.Slice(),
+		GPU:       c.gpuHistoryBuf.Slice(),
+	}
 }
 
 func runCmd(ctx context.Context, name string, args ...string) (string, error) {
@@ -4,8 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
@@ -39,26 +37,7 @@ func collectBatteries() (batts []BatteryStatus, err error) {
 	}
 
 	// Linux: /sys/class/power_supply.
-	matches, _ := filepath.Glob("/sys/class/power_supply/BAT*/capacity")
-	for _, capFile := range matches {
-		statusFile := filepath.Join(filepath.Dir(capFile), "status")
-		capData, err := os.ReadFile(capFile)
-		if err != nil {
-			continue
-		}
-		statusData, _ := os.ReadFile(statusFile)
-		percentStr := strings.TrimSpace(string(capData))
-		percent, _ := strconv.ParseFloat(percentStr, 64)
-		status := strings.TrimSpace(string(statusData))
-		if status == "" {
-			status = "Unknown"
-		}
-		batts = append(batts, BatteryStatus{
-			Percent: percent,
-			Status:  status,
-		})
-	}
-	if len(batts) > 0 {
+	if batts := readLinuxBatteries(sysfsRoot()); len(batts) > 0 {
 		return batts, nil
 	}
 
@@ -169,11 +148,17 @@ func getSystemPowerOutput() string {
 	return cachedPower
 }
 
-func collectThermal() ThermalStatus {
-	if runtime.GOOS != "darwin" {
-		return ThermalStatus{}
+func (c *Collector) collectThermal(now time.Time) ThermalStatus {
+	switch runtime.GOOS {
+	case "darwin":
+		return collectMacThermal()
+	case "linux":
+		return c.collectLinuxThermal(sysfsRoot(), now)
 	}
+	return ThermalStatus{}
+}
 
+func collectMacThermal() ThermalStatus {
 	var thermal ThermalStatus
 
 	// Fan info from cached system_profiler.
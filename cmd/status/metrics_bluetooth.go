@@ -14,7 +14,9 @@ const (
 )
 
 func (c *Collector) collectBluetooth(now time.Time) []BluetoothDevice {
-	if len(c.lastBT) > 0 && !c.lastBTAt.IsZero() && now.Sub(c.lastBTAt) < bluetoothCacheTTL {
+	// An empty result is cached too, so machines without Bluetooth do not
+	// rerun the probes every tick.
+	if !c.lastBTAt.IsZero() && now.Sub(c.lastBTAt) < bluetoothCacheTTL {
 		return c.lastBT
 	}
 
@@ -31,9 +33,6 @@ func (c *Collector) collectBluetooth(now time.Time) []BluetoothDevice {
 	}
 
 	c.lastBTAt = now
-	if len(c.lastBT) == 0 {
-		c.lastBT = []BluetoothDevice{{Name: "No Bluetooth info", Connected: false}}
-	}
 	return c.lastBT
 }
 
@@ -104,9 +103,6 @@ func parseSPBluetooth(raw string) []BluetoothDevice {
 	if currentName != "" {
 		devices = append(devices, BluetoothDevice{Name: currentName, Connected: connected, Battery: battery})
 	}
-	if len(devices) == 0 {
-		return []BluetoothDevice{{Name: "No devices", Connected: false}}
-	}
 	return devices
 }
 
@@ -131,8 +127,5 @@ func parseBluetoothctl(raw string) []BluetoothDevice {
 	if current.Name != "" {
 		devices = append(devices, current)
 	}
-	if len(devices) == 0 {
-		return []BluetoothDevice{{Name: "No devices", Connected: false}}
-	}
 	return devices
 }
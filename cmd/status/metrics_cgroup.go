@@ -0,0 +1,153 @@
+package main
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cgroupSample is one reading of the cgroup's cpu.stat counters.
+type cgroupSample struct {
+	at        time.Time
+	usageUsec uint64
+	periods   uint64
+	throttled uint64
+}
+
+// cgroupStatus is what the collector's own cgroup allows and uses. Zero
+// limits mean unlimited.
+type cgroupStatus struct {
+	cpuQuota  float64 // Cores
+	cpuUsage  float64 // Percent of cpuQuota
+	throttled float64 // Percent of periods throttled
+	memLimit  uint64
+	memUsed   uint64
+}
+
+// apply copies the cgroup view into the host CPU and memory stats.
+func (s cgroupStatus) apply(cpu *CPUStatus, mem *MemoryStatus) {
+	cpu.CgroupQuota = s.cpuQuota
+	cpu.CgroupUsage = s.cpuUsage
+	cpu.CgroupThrottled = s.throttled
+	mem.CgroupLimit = s.memLimit
+	mem.CgroupUsed = s.memUsed
+}
+
+// cgroupDir returns the cgroup v2 directory of this process and the
+// unified mount it lives under, or "" on cgroup v1 hosts. Inside a
+// container without a cgroup namespace the listed path does not exist
+// under the container's mount, which is then the cgroup itself.
+func cgroupDir(sysRoot, procRoot string) (dir, mount string) {
+	mount = filepath.Join(sysRoot, "fs", "cgroup")
+	if _, err := os.Stat(filepath.Join(mount, "cgroup.controllers")); err != nil {
+		return "", ""
+	}
+	data, err := os.ReadFile(filepath.Join(procRoot, "self", "cgroup"))
+	if err != nil {
+		return mount, mount
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		path, ok := strings.CutPrefix(strings.TrimSpace(line), "0::")
+		if !ok {
+			continue
+		}
+		dir = filepath.Join(mount, path)
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir, mount
+		}
+	}
+	return mount, mount
+}
+
+// readCgroupLimits returns the tightest cpu.max and memory.max between dir
+// and the mount, since a limit on any ancestor slice applies too.
+func readCgroupLimits(dir, mount string) (quota float64, memLimit uint64) {
+	for {
+		if fields := strings.Fields(readSysfsString(filepath.Join(dir, "cpu.max"))); len(fields) == 2 && fields[0] != "max" {
+			limit, errL := strconv.ParseFloat(fields[0], 64)
+			period, errP := strconv.ParseFloat(fields[1], 64)
+			if errL == nil && errP == nil && period > 0 {
+				if cores := limit / period; quota == 0 || cores < quota {
+					quota = cores
+				}
+			}
+		}
+		if limit, err := strconv.ParseUint(readSysfsString(filepath.Join(dir, "memory.max")), 10, 64); err == nil {
+			if memLimit == 0 || limit < memLimit {
+				memLimit = limit
+			}
+		}
+		if dir == mount || len(dir) <= len(mount) {
+			return quota, memLimit
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+func readCgroupCPUStat(dir string, now time.Time) cgroupSample {
+	sample := cgroupSample{at: now}
+	data, err := os.ReadFile(filepath.Join(dir, "cpu.stat"))
+	if err != nil {
+		return sample
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			continue
+		}
+		switch key {
+		case "usage_usec":
+			sample.usageUsec = n
+		case "nr_periods":
+			sample.periods = n
+		case "nr_throttled":
+			sample.throttled = n
+		}
+	}
+	return sample
+}
+
+// collectCgroup reads the limits and usage of the collector's own cgroup.
+// Nothing is reported unless a CPU or memory limit applies, which is the
+// case inside containers and limited systemd slices.
+func (c *Collector) collectCgroup(sysRoot, procRoot string, now time.Time) cgroupStatus {
+	var status cgroupStatus
+	dir, mount := cgroupDir(sysRoot, procRoot)
+	if dir == "" {
+		return status
+	}
+	status.cpuQuota, status.memLimit = readCgroupLimits(dir, mount)
+	if status.memLimit > 0 {
+		if used, err := strconv.ParseUint(readSysfsString(filepath.Join(dir, "memory.current")), 10, 64); err == nil {
+			status.memUsed = used
+		}
+	}
+	if status.cpuQuota > 0 {
+		sample := readCgroupCPUStat(dir, now)
+		status.cpuUsage, status.throttled = cgroupCPU(c.prevCgroup, sample, status.cpuQuota)
+		c.prevCgroup = sample
+	}
+	return status
+}
+
+// cgroupCPU returns usage as a percent of the quota and the share of
+// scheduler periods that were throttled between two samples.
+func cgroupCPU(prev, cur cgroupSample, quota float64) (usage, throttled float64) {
+	elapsed := cur.at.Sub(prev.at).Seconds()
+	if prev.at.IsZero() || elapsed <= 0 || cur.usageUsec < prev.usageUsec {
+		return 0, 0
+	}
+	used := float64(cur.usageUsec-prev.usageUsec) / 1e6 / elapsed
+	usage = math.Min(used/quota*100, 100)
+	if cur.periods > prev.periods && cur.throttled >= prev.throttled {
+		throttled = float64(cur.throttled-prev.throttled) / float64(cur.periods-prev.periods) * 100
+	}
+	return usage, throttled
+}
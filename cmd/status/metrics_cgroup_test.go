@@ -0,0 +1,75 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCollectCgroup(t *testing.T) {
+	sys, proc := t.TempDir(), t.TempDir()
+	slice := "fs/cgroup/user.slice/"
+	scope := slice + "docker-abc.scope/"
+	writeSysfs(t, sys, map[string]string{
+		"fs/cgroup/cgroup.controllers": "cpuset cpu io memory pids",
+		// The slice caps memory; the scope caps CPU more tightly.
+		slice + "cpu.max":        "400000 100000",
+		slice + "memory.max":     "4294967296",
+		scope + "cpu.max":        "150000 100000",
+		scope + "memory.max":     "max",
+		scope + "memory.current": "1073741824",
+		scope + "cpu.stat":       "usage_usec 1000000\nuser_usec 800000\nsystem_usec 200000\nnr_periods 100\nnr_throttled 0\nthrottled_usec 0",
+	})
+	writeSysfs(t, proc, map[string]string{"self/cgroup": "0::/user.slice/docker-abc.scope\n"})
+
+	c := &Collector{}
+	start := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	first := c.collectCgroup(sys, proc, start)
+	if first.cpuQuota != 1.5 || first.memLimit != 4<<30 || first.memUsed != 1<<30 {
+		t.Fatalf("unexpected limits %+v", first)
+	}
+	if first.cpuUsage != 0 || first.throttled != 0 {
+		t.Errorf("first sample should report no rates, got %+v", first)
+	}
+
+	// 1.2s of CPU over 2s is 0.6 cores, 40% of 1.5; 5 of 20 periods throttled.
+	writeSysfs(t, sys, map[string]string{scope + "cpu.stat": "usage_usec 2200000\nnr_periods 120\nnr_throttled 5"})
+	second := c.collectCgroup(sys, proc, start.Add(2*time.Second))
+	if second.cpuUsage < 39.99 || second.cpuUsage > 40.01 || second.throttled != 25 {
+		t.Errorf("usage = %v, throttled = %v, want 40 and 25", second.cpuUsage, second.throttled)
+	}
+
+	var cpu CPUStatus
+	var mem MemoryStatus
+	second.apply(&cpu, &mem)
+	if cpu.CgroupQuota != 1.5 || mem.CgroupLimit != 4<<30 || mem.CgroupUsed != 1<<30 {
+		t.Errorf("apply lost fields: %+v %+v", cpu, mem)
+	}
+}
+
+func TestCollectCgroupWithoutLimits(t *testing.T) {
+	c := &Collector{}
+	now := time.Now()
+
+	// cgroup v1 hosts have no unified hierarchy.
+	if got := c.collectCgroup(t.TempDir(), t.TempDir(), now); got != (cgroupStatus{}) {
+		t.Errorf("v1 host should report nothing, got %+v", got)
+	}
+
+	// An unlimited container whose path is not visible in its namespace
+	// falls back to the mount itself.
+	sys, proc := t.TempDir(), t.TempDir()
+	writeSysfs(t, sys, map[string]string{
+		"fs/cgroup/cgroup.controllers": "cpu memory",
+		"fs/cgroup/cpu.max":            "max 100000",
+		"fs/cgroup/memory.max":         "max",
+		"fs/cgroup/memory.current":     "1000",
+	})
+	writeSysfs(t, proc, map[string]string{"self/cgroup": "0::/system.slice/docker-def.scope\n"})
+	if dir, mount := cgroupDir(sys, proc); dir != mount || !strings.HasSuffix(dir, "fs/cgroup") {
+		t.Errorf("cgroupDir = %q, %q", dir, mount)
+	}
+	if got := c.collectCgroup(sys, proc, now); got != (cgroupStatus{}) {
+		t.Errorf("unlimited cgroup should report nothing, got %+v", got)
+	}
+}
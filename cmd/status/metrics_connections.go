@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/net"
+)
+
+const (
+	connectionsTTL     = 5 * time.Second
+	connectionsTimeout = 2 * time.Second
+)
+
+// setConnectionDetail asks later collections for the connection list, which
+// only the network detail view shows.
+func (c *Collector) setConnectionDetail(on bool) {
+	c.connectionDetail.Store(on)
+}
+
+// collectConnections lists active TCP and UDP sockets with their owning
+// PIDs. Mapping sockets to processes walks every process's descriptors
+// (lsof on macOS), so Collect only asks while the network detail view is
+// open, and results are cached for connectionsTTL.
+func (c *Collector) collectConnections(now time.Time) ([]ConnectionInfo, error) {
+	if !c.lastConnAt.IsZero() && now.Sub(c.lastConnAt) < connectionsTTL {
+		return c.lastConns, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), connectionsTimeout)
+	defer cancel()
+
+	c.lastConnAt = now
+	conns, err := net.ConnectionsWithoutUidsWithContext(ctx, "inet")
+	if err != nil {
+		c.lastConns = nil
+		return nil, err
+	}
+	c.lastConns = activeConnections(conns)
+	return c.lastConns, nil
+}
+
+// activeConnections keeps sockets that carry traffic: listening TCP
+// sockets and those of processes we cannot see (PID 0) are dropped.
+func activeConnections(conns []net.ConnectionStat) []ConnectionInfo {
+	var result []ConnectionInfo
+	for _, conn := range conns {
+		if conn.Pid <= 0 {
+			continue
+		}
+		var proto string
+		switch conn.Type {
+		case syscall.SOCK_STREAM:
+			proto = "tcp"
+			if conn.Status == "LISTEN" || conn.Status == "CLOSE" || conn.Raddr.IP == "" {
+				continue
+			}
+		case syscall.SOCK_DGRAM:
+			proto = "udp"
+		default:
+			continue
+		}
+		if conn.Family == syscall.AF_INET6 {
+			proto += "6"
+		}
+		info := ConnectionInfo{PID: conn.Pid, Proto: proto, Local: formatAddr(conn.Laddr), Status: conn.Status}
+		if conn.Raddr.IP != "" {
+			info.Remote = formatAddr(conn.Raddr)
+		}
+		result = append(result, info)
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].PID < result[j].PID })
+	return result
+}
+
+func formatAddr(addr net.Addr) string {
+	if strings.Contains(addr.IP, ":") {
+		return fmt.Sprintf("[%s]:%d", addr.IP, addr.Port)
+	}
+	return fmt.Sprintf("%s:%d", addr.IP, addr.Port)
+}
+
+// namedConnections returns conns with process names from this collection's
+// process samples, which cover every process even when only the top few
+// are listed. The cached slice itself is left alone.
+func namedConnections(conns []ConnectionInfo, samples map[int32]processSample) []ConnectionInfo {
+	if len(conns) == 0 {
+		return nil
+	}
+	result := make([]ConnectionInfo, len(conns))
+	for i, conn := range conns {
+		conn.Process = samples[conn.PID].name
+		if conn.Process == "" {
+			conn.Process = fmt.Sprintf("pid %d", conn.PID)
+		}
+		result[i] = conn
+	}
+	return result
+}
+
+// processConnections is one process's share of the connection list.
+type processConnections struct {
+	PID     int32
+	Name    string
+	TCP     int
+	UDP     int
+	Remotes []string // Distinct remote addresses, first seen first
+}
+
+// groupConnections groups connections by process, most connections first.
+func groupConnections(conns []ConnectionInfo) []processConnections {
+	index := make(map[int32]int)
+	var groups []processConnections
+	for _, conn := range conns {
+		i, ok := index[conn.PID]
+		if !ok {
+			i = len(groups)
+			index[conn.PID] = i
+			groups = append(groups, processConnections{PID: conn.PID, Name: conn.Process})
+		}
+		g := &groups[i]
+		if strings.HasPrefix(conn.Proto, "tcp") {
+			g.TCP++
+		} else {
+			g.UDP++
+		}
+		if conn.Remote != "" && !slices.Contains(g.Remotes, conn.Remote) {
+			g.Remotes = append(g.Remotes, conn.Remote)
+		}
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].TCP+groups[i].UDP > groups[j].TCP+groups[j].UDP
+	})
+	return groups
+}
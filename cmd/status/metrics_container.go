@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	containerTimeout = 800 * time.Millisecond
+	// containerCacheTTL spaces out engine queries, which cost one stats
+	// request per container; CPU is averaged over the same span.
+	containerCacheTTL = 5 * time.Second
+)
+
+// containerEngine is a local Docker API socket. Podman serves the same API.
+type containerEngine struct {
+	name   string // docker or podman
+	socket string
+}
+
+// containerCPUSample is a container's cumulative CPU time at one collection.
+type containerCPUSample struct {
+	at    time.Time
+	usage uint64 // Nanoseconds
+}
+
+// containerEngines returns the Docker and Podman sockets that exist, from
+// DOCKER_HOST/CONTAINER_HOST and the usual rootful and rootless paths.
+// Sockets that resolve to the same file, such as podman-docker's
+// docker.sock link, are listed once.
+func containerEngines() []containerEngine {
+	var candidates []containerEngine
+	add := func(name, socket string) {
+		if filepath.IsAbs(socket) {
+			candidates = append(candidates, containerEngine{name: name, socket: socket})
+		}
+	}
+	add("docker", unixSocketPath(os.Getenv("DOCKER_HOST")))
+	add("docker", "/var/run/docker.sock")
+	if home, err := os.UserHomeDir(); err == nil {
+		add("docker", filepath.Join(home, ".docker", "run", "docker.sock"))
+	}
+	add("podman", unixSocketPath(os.Getenv("CONTAINER_HOST")))
+	if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+		add("podman", filepath.Join(runtimeDir, "podman", "podman.sock"))
+	}
+	add("podman", "/run/podman/podman.sock")
+
+	var engines []containerEngine
+	seen := map[string]bool{}
+	for _, e := range candidates {
+		resolved, err := filepath.EvalSymlinks(e.socket)
+		if err != nil || seen[resolved] {
+			continue
+		}
+		if info, err := os.Stat(resolved); err != nil || info.Mode()&os.ModeSocket == 0 {
+			continue
+		}
+		seen[resolved] = true
+		engines = append(engines, e)
+	}
+	return engines
+}
+
+// unixSocketPath returns the path of a unix:// host URL, or "" for TCP and
+// SSH hosts, which are not local.
+func unixSocketPath(host string) string {
+	path, ok := strings.CutPrefix(host, "unix://")
+	if !ok {
+		return ""
+	}
+	return path
+}
+
+func engineClient(socket string) *http.Client {
+	return &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", socket)
+		},
+		DisableKeepAlives: true,
+	}}
+}
+
+func engineGet(ctx context.Context, client *http.Client, path string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://localhost"+path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", path, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// Subsets of the Docker API's container list and stats responses.
+type engineContainer struct {
+	ID    string   `json:"Id"`
+	Names []string `json:"Names"`
+	Image string   `json:"Image"`
+	State string   `json:"State"`
+}
+
+type engineStats struct {
+	CPUStats struct {
+		CPUUsage struct {
+			TotalUsage uint64 `json:"total_usage"`
+		} `json:"cpu_usage"`
+	} `json:"cpu_stats"`
+	MemoryStats struct {
+		Usage uint64            `json:"usage"`
+		Limit uint64            `json:"limit"`
+		Stats map[string]uint64 `json:"stats"`
+	} `json:"memory_stats"`
+}
+
+// listContainers returns the running containers of one engine with their
+// memory use and cumulative CPU time. A container whose stats cannot be
+// read is still listed.
+func listContainers(ctx context.Context, e containerEngine) ([]ContainerStatus, []uint64, error) {
+	client := engineClient(e.socket)
+	var list []engineContainer
+	if err := engineGet(ctx, client, "/containers/json", &list); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", e.name, err)
+	}
+
+	containers := make([]ContainerStatus, len(list))
+	usage := make([]uint64, len(list))
+	var wg sync.WaitGroup
+	for i, ct := range list {
+		name := ct.ID[:min(len(ct.ID), 12)]
+		if len(ct.Names) > 0 {
+			name = strings.TrimPrefix(ct.Names[0], "/")
+		}
+		containers[i] = ContainerStatus{ID: ct.ID, Name: name, Image: ct.Image, Engine: e.name, State: ct.State}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// one-shot skips the second sample Docker otherwise waits a
+			// second for; CPU rates come from the collector's own deltas.
+			var stats engineStats
+			if err := engineGet(ctx, client, "/containers/"+ct.ID+"/stats?stream=false&one-shot=true", &stats); err != nil {
+				return
+			}
+			mem := stats.MemoryStats
+			// Page cache that can be reclaimed is not counted, as in docker stats.
+			cache := mem.Stats["inactive_file"]
+			if cache == 0 {
+				cache = mem.Stats["total_inactive_file"]
+			}
+			if cache < mem.Usage {
+				containers[i].MemUsed = mem.Usage - cache
+			}
+			containers[i].MemLimit = mem.Limit
+			usage[i] = stats.CPUStats.CPUUsage.TotalUsage
+		}()
+	}
+	wg.Wait()
+	return containers, usage, nil
+}
+
+// collectContainers lists running containers across engines, busiest
+// first. CPU is the percent of one core used since the last query. Engines
+// are queried together and at most every containerCacheTTL; in between the
+// last list is returned, empty lists included.
+func (c *Collector) collectContainers(engines []containerEngine, now time.Time) ([]ContainerStatus, error) {
+	if !c.lastContainersAt.IsZero() && now.Sub(c.lastContainersAt) < containerCacheTTL {
+		// Collect adjusts limits in place; the cached list stays as read.
+		return slices.Clone(c.lastContainers), nil
+	}
+	c.lastContainersAt = now
+	c.lastContainers = nil
+	if len(engines) == 0 {
+		return nil, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), containerTimeout)
+	defer cancel()
+
+	type engineResult struct {
+		containers []ContainerStatus
+		usage      []uint64
+		err        error
+	}
+	results := make([]engineResult, len(engines))
+	var wg sync.WaitGroup
+	for i, e := range engines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r := &results[i]
+			r.containers, r.usage, r.err = listContainers(ctx, e)
+		}()
+	}
+	wg.Wait()
+
+	var all []ContainerStatus
+	var errs []error
+	seen := make(map[string]bool)
+	samples := make(map[string]containerCPUSample)
+	// Engines are merged in order, so a container two sockets share is
+	// reported by the first.
+	for _, r := range results {
+		containers, usage := r.containers, r.usage
+		if r.err != nil {
+			errs = append(errs, r.err)
+			continue
+		}
+		for i, ct := range containers {
+			if seen[ct.ID] {
+				continue
+			}
+			seen[ct.ID] = true
+			// No usage means the stats request failed; keep no sample so
+			// the next one is not measured from zero.
+			if usage[i] == 0 {
+				all = append(all, ct)
+				continue
+			}
+			samples[ct.ID] = containerCPUSample{at: now, usage: usage[i]}
+			if prev, ok := c.prevContainers[ct.ID]; ok && usage[i] >= prev.usage {
+				if elapsed := now.Sub(prev.at).Seconds(); elapsed > 0 {
+					ct.CPU = float64(usage[i]-prev.usage) / 1e9 / elapsed * 100
+				}
+			}
+			all = append(all, ct)
+		}
+	}
+	c.prevContainers = samples
+
+	sort.SliceStable(all, func(i, j int) bool { return all[i].CPU > all[j].CPU })
+	c.lastContainers = all
+	return slices.Clone(all), errors.Join(errs...)
+}
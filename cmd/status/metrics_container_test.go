@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeEngine serves a Docker API handler on a unix socket.
+func fakeEngine(t *testing.T, name string, handler http.Handler) containerEngine {
+	t.Helper()
+	socket := filepath.Join(t.TempDir(), name+".sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	server := httptest.NewUnstartedServer(handler)
+	server.Listener.Close() //nolint:errcheck
+	server.Listener = listener
+	server.Start()
+	t.Cleanup(server.Close)
+	return containerEngine{name: name, socket: socket}
+}
+
+func TestCollectContainers(t *testing.T) {
+	var webUsage, lists atomic.Uint64
+	mux := http.NewServeMux()
+	mux.HandleFunc("/containers/json", func(w http.ResponseWriter, r *http.Request) {
+		lists.Add(1)
+		fmt.Fprint(w, `[
+			{"Id":"aaaaaaaaaaaaaaaa","Names":["/db"],"Image":"postgres:16","State":"running"},
+			{"Id":"bbbbbbbbbbbbbbbb","Names":["/web"],"Image":"nginx:latest","State":"running"}
+		]`)
+	})
+	mux.HandleFunc("/containers/aaaaaaaaaaaaaaaa/stats", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	})
+	mux.HandleFunc("/containers/bbbbbbbbbbbbbbbb/stats", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("one-shot") != "true" || r.URL.Query().Get("stream") != "false" {
+			t.Errorf("unexpected stats query %q", r.URL.RawQuery)
+		}
+		fmt.Fprintf(w, `{"cpu_stats":{"cpu_usage":{"total_usage":%d}},
+			"memory_stats":{"usage":314572800,"limit":536870912,"stats":{"inactive_file":104857600}}}`, webUsage.Load())
+	})
+	docker := fakeEngine(t, "docker", mux)
+	// podman-docker style: the same containers behind a second socket.
+	podman := fakeEngine(t, "podman", mux)
+	gone := containerEngine{name: "podman", socket: filepath.Join(t.TempDir(), "missing.sock")}
+
+	webUsage.Store(4e9)
+	c := &Collector{}
+	start := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	containers, err := c.collectContainers([]containerEngine{docker, podman, gone}, start)
+	if err == nil || !strings.HasPrefix(err.Error(), "podman:") {
+		t.Errorf("unreachable engine should be reported, got %v", err)
+	}
+	if len(containers) != 2 {
+		t.Fatalf("expected 2 containers once each, got %+v", containers)
+	}
+	web := containers[1]
+	if web.Name != "web" || web.Engine != "docker" || web.MemUsed != 200<<20 || web.MemLimit != 512<<20 || web.CPU != 0 {
+		t.Errorf("unexpected first web sample %+v", web)
+	}
+
+	// Inside the TTL the engines are not asked again.
+	asked := lists.Load()
+	cached, err := c.collectContainers([]containerEngine{docker}, start.Add(time.Second))
+	if err != nil || len(cached) != 2 || lists.Load() != asked {
+		t.Errorf("expected the cached list without engine requests, got %+v %v (%d lists)", cached, err, lists.Load()-asked)
+	}
+	cached[0].MemLimit = 1
+	if again, _ := c.collectContainers(nil, start.Add(2*time.Second)); again[0].MemLimit == 1 {
+		t.Error("callers must not be able to change the cached list")
+	}
+
+	webUsage.Store(4e9 + 2.5e9)
+	containers, _ = c.collectContainers([]containerEngine{docker}, start.Add(containerCacheTTL))
+	if containers[0].Name != "web" || containers[0].CPU != 50 {
+		t.Errorf("web should lead at 50%% of a core, got %+v", containers)
+	}
+	if db := containers[1]; db.Name != "db" || db.Image != "postgres:16" || db.MemUsed != 0 || db.CPU != 0 {
+		t.Errorf("db without stats should still be listed, got %+v", db)
+	}
+}
+
+func TestContainerEngines(t *testing.T) {
+	dir := t.TempDir()
+	engine := fakeEngine(t, "podman", http.NotFoundHandler())
+	link := filepath.Join(dir, "docker.sock")
+	if err := os.Symlink(engine.socket, link); err != nil {
+		t.Fatal(err)
+	}
+	plain := filepath.Join(dir, "plain")
+	if err := os.WriteFile(plain, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_RUNTIME_DIR", plain)
+	t.Setenv("DOCKER_HOST", "unix://"+link)
+	t.Setenv("CONTAINER_HOST", "unix://"+engine.socket)
+
+	var found []containerEngine
+	for _, e := range containerEngines() {
+		if e.socket == link || e.socket == engine.socket || strings.HasPrefix(e.socket, dir) {
+			found = append(found, e)
+		}
+	}
+	if len(found) != 1 || found[0].socket != link {
+		t.Errorf("expected the linked socket once, got %+v", found)
+	}
+
+	if got := unixSocketPath("tcp://10.0.0.2:2375"); got != "" {
+		t.Errorf("TCP hosts are not local sockets, got %q", got)
+	}
+}
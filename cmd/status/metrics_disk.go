@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"runtime"
 	"sort"
 	"strings"
@@ -85,10 +87,6 @@ func collectDisks() ([]DiskStatus, error) {
 		return disks[i].Total > disks[j].Total
 	})
 
-	if len(disks) > 3 {
-		disks = disks[:3]
-	}
-
 	return disks, nil
 }
 
@@ -179,36 +177,82 @@ func (c *Collector) collectDiskIO(now time.Time) DiskIOStatus {
 	if err != nil || len(counters) == 0 {
 		return DiskIOStatus{}
 	}
+	if runtime.GOOS == "linux" {
+		sysRoot := sysfsRoot()
+		for name := range counters {
+			if !isWholeDisk(sysRoot, name) {
+				delete(counters, name)
+			}
+		}
+	}
+	return c.diskIORates(counters, now)
+}
 
-	var total disk.IOCountersStat
-	for _, v := range counters {
-		total.ReadBytes += v.ReadBytes
-		total.WriteBytes += v.WriteBytes
+// isWholeDisk reports whether a Linux block device carries its own IO:
+// partitions, loop and RAM disks, and device-mapper or md volumes stacked
+// on other disks would count the same bytes twice.
+func isWholeDisk(sysRoot, name string) bool {
+	if strings.HasPrefix(name, "loop") || strings.HasPrefix(name, "ram") {
+		return false
 	}
+	dir := filepath.Join(sysRoot, "class", "block", name)
+	if _, err := os.Stat(filepath.Join(dir, "partition")); err == nil {
+		return false
+	}
+	if slaves, err := os.ReadDir(filepath.Join(dir, "slaves")); err == nil && len(slaves) > 0 {
+		return false
+	}
+	return true
+}
 
-	if c.lastDiskAt.IsZero() {
-		c.prevDiskIO = total
-		c.lastDiskAt = now
+// diskIORates turns per-device counters into rates since the previous
+// call, busiest device first, with the system total summed from them.
+// The first call only records counters and returns nothing.
+func (c *Collector) diskIORates(counters map[string]disk.IOCountersStat, now time.Time) DiskIOStatus {
+	prev := c.prevDiskIO
+	last := c.lastDiskAt
+	c.prevDiskIO = counters
+	c.lastDiskAt = now
+	if last.IsZero() {
 		return DiskIOStatus{}
 	}
 
-	elapsed := now.Sub(c.lastDiskAt).Seconds()
+	elapsed := now.Sub(last).Seconds()
 	if elapsed <= 0 {
 		elapsed = 1
 	}
 
-	readRate := float64(total.ReadBytes-c.prevDiskIO.ReadBytes) / 1024 / 1024 / elapsed
-	writeRate := float64(total.WriteBytes-c.prevDiskIO.WriteBytes) / 1024 / 1024 / elapsed
-
-	c.prevDiskIO = total
-	c.lastDiskAt = now
-
-	if readRate < 0 {
-		readRate = 0
-	}
-	if writeRate < 0 {
-		writeRate = 0
+	var status DiskIOStatus
+	for name, cur := range counters {
+		old, ok := prev[name]
+		if !ok {
+			continue
+		}
+		reads := counterDelta(cur.ReadCount, old.ReadCount)
+		writes := counterDelta(cur.WriteCount, old.WriteCount)
+		dev := DiskDeviceIO{
+			Name:      name,
+			ReadRate:  float64(counterDelta(cur.ReadBytes, old.ReadBytes)) / 1024 / 1024 / elapsed,
+			WriteRate: float64(counterDelta(cur.WriteBytes, old.WriteBytes)) / 1024 / 1024 / elapsed,
+			ReadIOPS:  float64(reads) / elapsed,
+			WriteIOPS: float64(writes) / elapsed,
+			Busy:      min(float64(counterDelta(cur.IoTime, old.IoTime))/(elapsed*1000)*100, 100),
+		}
+		if ios := reads + writes; ios > 0 {
+			waited := counterDelta(cur.ReadTime, old.ReadTime) + counterDelta(cur.WriteTime, old.WriteTime)
+			dev.Await = float64(waited) / float64(ios)
+		}
+		status.ReadRate += dev.ReadRate
+		status.WriteRate += dev.WriteRate
+		status.Devices = append(status.Devices, dev)
 	}
 
-	return DiskIOStatus{ReadRate: readRate, WriteRate: writeRate}
+	sort.Slice(status.Devices, func(i, j int) bool {
+		a, b := status.Devices[i], status.Devices[j]
+		if a.ReadRate+a.WriteRate != b.ReadRate+b.WriteRate {
+			return a.ReadRate+a.WriteRate > b.ReadRate+b.WriteRate
+		}
+		return a.Name < b.Name
+	})
+	return status
 }
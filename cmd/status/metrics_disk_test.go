@@ -0,0 +1,56 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/disk"
+)
+
+func TestDiskIORates(t *testing.T) {
+	c := &Collector{}
+	start := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	counters := func(nvmeRead, nvmeReads, nvmeTime, sdaWrite uint64) map[string]disk.IOCountersStat {
+		return map[string]disk.IOCountersStat{
+			"nvme0n1": {Name: "nvme0n1", ReadBytes: nvmeRead, ReadCount: nvmeReads, ReadTime: nvmeTime, IoTime: nvmeTime / 2},
+			"sda":     {Name: "sda", WriteBytes: sdaWrite, WriteCount: sdaWrite / 4096, WriteTime: 10},
+		}
+	}
+
+	if got := c.diskIORates(counters(100<<20, 1000, 500, 0), start); got.ReadRate != 0 || got.Devices != nil {
+		t.Fatalf("first call should only prime counters, got %+v", got)
+	}
+	got := c.diskIORates(counters(120<<20, 1400, 1300, 4<<20), start.Add(2*time.Second))
+	if got.ReadRate != 10 || got.WriteRate != 2 || len(got.Devices) != 2 {
+		t.Fatalf("unexpected totals %+v", got)
+	}
+	nvme := got.Devices[0]
+	// 400 reads waited 800ms in all: 2ms each; 400ms of the 2s had IO in flight.
+	if nvme.Name != "nvme0n1" || nvme.ReadRate != 10 || nvme.ReadIOPS != 200 || nvme.Await != 2 || nvme.Busy != 20 {
+		t.Errorf("unexpected nvme0n1 rates %+v", nvme)
+	}
+	if sda := got.Devices[1]; sda.WriteIOPS != 512 || sda.Await != 0 {
+		t.Errorf("sda wrote 1024 IOs without new wait time, got %+v", sda)
+	}
+
+	// A counter reset, as after a device is re-attached, reads as idle.
+	got = c.diskIORates(counters(1<<20, 10, 5, 4<<20), start.Add(3*time.Second))
+	if got.ReadRate != 0 || got.Devices[0].Await != 0 {
+		t.Errorf("reset counters should not wrap, got %+v", got)
+	}
+}
+
+func TestIsWholeDisk(t *testing.T) {
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		"class/block/nvme0n1/size":          "1953525168",
+		"class/block/nvme0n1p2/partition":   "2",
+		"class/block/dm-0/slaves/nvme0n1p2": "",
+		"class/block/md0/size":              "100",
+	})
+	for name, want := range map[string]bool{"nvme0n1": true, "nvme0n1p2": false, "dm-0": false, "md0": true, "loop3": false, "ram0": false, "sdb": true} {
+		if got := isWholeDisk(root, name); got != want {
+			t.Errorf("isWholeDisk(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -49,11 +49,10 @@ func (c *Collector) collectGPU(now time.Time) ([]GPUStatus, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 600*time.Millisecond)
 	defer cancel()
 
+	// Without a source of GPU metrics report none, so the GPU card stays
+	// hidden instead of showing a placeholder.
 	if !commandExists("nvidia-smi") {
-		return []GPUStatus{{
-			Name: "No GPU metrics available",
-			Note: "Install nvidia-smi or use platform-specific metrics",
-		}}, nil
+		return nil, nil
 	}
 
 	out, err := runCmd(ctx, "nvidia-smi", "--query-gpu=utilization.gpu,memory.used,memory.total,name", "--format=csv,noheader,nounits")
@@ -80,13 +79,6 @@ func (c *Collector) collectGPU(now time.Time) ([]GPUStatus, error) {
 		})
 	}
 
-	if len(gpus) == 0 {
-		return []GPUStatus{{
-			Name: "GPU read failed",
-			Note: "Verify nvidia-smi availability",
-		}}, nil
-	}
-
 	return gpus, nil
 }
 
@@ -141,13 +133,6 @@ func readMacGPUInfo() ([]GPUStatus, error) {
 		})
 	}
 
-	if len(gpus) == 0 {
-		return []GPUStatus{{
-			Name: "GPU info unavailable",
-			Note: "Unable to parse system_profiler output",
-		}}, nil
-	}
-
 	return gpus, nil
 }
 
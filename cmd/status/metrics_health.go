@@ -5,123 +5,200 @@ import (
 	"strings"
 )
 
-// Health score weights and thresholds.
-const (
-	// Weights.
-	healthCPUWeight     = 30.0
-	healthMemWeight     = 25.0
-	healthDiskWeight    = 20.0
-	healthThermalWeight = 15.0
-	healthIOWeight      = 10.0
-
-	// CPU.
-	cpuNormalThreshold = 30.0
-	cpuHighThreshold   = 70.0
-
-	// Memory.
-	memNormalThreshold     = 50.0
-	memHighThreshold       = 80.0
-	memPressureWarnPenalty = 5.0
-	memPressureCritPenalty = 15.0
-
-	// Disk.
-	diskWarnThreshold = 70.0
-	diskCritThreshold = 90.0
-
-	// Thermal.
-	thermalNormalThreshold = 60.0
-	thermalHighThreshold   = 85.0
-
-	// Disk IO (MB/s).
-	ioNormalThreshold = 50.0
-	ioHighThreshold   = 150.0
-)
+// healthComponent configures one part of the health score. Penalties
+// start once a reading passes Normal and reach Weight around High. For
+// battery, where lower capacity is worse, High is below Normal.
+type healthComponent struct {
+	Enabled bool
+	Weight  float64
+	Normal  float64
+	High    float64
+}
+
+// healthModel holds the weights and thresholds behind the health score.
+// Defaults reproduce the original fixed model plus drive wear, which only
+// applies when smartctl reports it; network, swap and battery take part
+// only when enabled in the config file.
+type healthModel struct {
+	CPU     healthComponent // Usage percent
+	Memory  healthComponent // Used percent
+	Disk    healthComponent // Used percent of the first disk
+	Thermal healthComponent // CPU °C
+	IO      healthComponent // Disk read+write MB/s
+	Network healthComponent // Receive+transmit MB/s
+	Swap    healthComponent // Used percent of swap
+	Battery healthComponent // Maximum capacity percent
+	Wear    healthComponent // Worst drive's SMART percentage used
+
+	MemPressureWarnPenalty float64
+	MemPressureCritPenalty float64
+}
+
+func defaultHealthModel() healthModel {
+	return healthModel{
+		CPU:     healthComponent{Enabled: true, Weight: 30, Normal: 30, High: 70},
+		Memory:  healthComponent{Enabled: true, Weight: 25, Normal: 50, High: 80},
+		Disk:    healthComponent{Enabled: true, Weight: 20, Normal: 70, High: 90},
+		Thermal: healthComponent{Enabled: true, Weight: 15, Normal: 60, High: 85},
+		IO:      healthComponent{Enabled: true, Weight: 10, Normal: 50, High: 150},
+		Network: healthComponent{Weight: 10, Normal: 50, High: 100},
+		Swap:    healthComponent{Weight: 10, Normal: 25, High: 75},
+		Battery: healthComponent{Weight: 10, Normal: 80, High: 60},
+		Wear:    healthComponent{Enabled: true, Weight: 15, Normal: 70, High: 90},
+
+		MemPressureWarnPenalty: 5,
+		MemPressureCritPenalty: 15,
+	}
+}
+
+// HealthPenalty is the number of points one component took off the score.
+type HealthPenalty struct {
+	Component string
+	Penalty   float64
+}
+
+type healthResult struct {
+	Score     int
+	Message   string
+	Penalties []HealthPenalty // Components that cost points, in model order
+}
 
-func calculateHealthScore(cpu CPUStatus, mem MemoryStatus, disks []DiskStatus, diskIO DiskIOStatus, thermal ThermalStatus) (int, string) {
+func scoreHealth(model healthModel, cpu CPUStatus, mem MemoryStatus, disks []DiskStatus, diskIO DiskIOStatus, thermal ThermalStatus, netStats []NetworkStatus, batts []BatteryStatus, drives []DiskHealth) healthResult {
 	score := 100.0
 	issues := []string{}
+	var penalties []HealthPenalty
+	charge := func(component string, penalty float64) {
+		if penalty <= 0 {
+			return
+		}
+		score -= penalty
+		penalties = append(penalties, HealthPenalty{Component: component, Penalty: penalty})
+	}
 
 	// CPU penalty.
-	cpuPenalty := 0.0
-	if cpu.Usage > cpuNormalThreshold {
-		if cpu.Usage > cpuHighThreshold {
-			cpuPenalty = healthCPUWeight * (cpu.Usage - cpuNormalThreshold) / cpuHighThreshold
-		} else {
-			cpuPenalty = (healthCPUWeight / 2) * (cpu.Usage - cpuNormalThreshold) / (cpuHighThreshold - cpuNormalThreshold)
+	if c := model.CPU; c.Enabled {
+		cpuPenalty := 0.0
+		if cpu.Usage > c.Normal {
+			if cpu.Usage > c.High {
+				cpuPenalty = c.Weight * (cpu.Usage - c.Normal) / c.High
+			} else {
+				cpuPenalty = (c.Weight / 2) * (cpu.Usage - c.Normal) / (c.High - c.Normal)
+			}
+		}
+		charge("CPU", cpuPenalty)
+		if cpu.Usage > c.High {
+			issues = append(issues, "High CPU")
 		}
-	}
-	score -= cpuPenalty
-	if cpu.Usage > cpuHighThreshold {
-		issues = append(issues, "High CPU")
 	}
 
-	// Memory penalty.
-	memPenalty := 0.0
-	if mem.UsedPercent > memNormalThreshold {
-		if mem.UsedPercent > memHighThreshold {
-			memPenalty = healthMemWeight * (mem.UsedPercent - memNormalThreshold) / memNormalThreshold
-		} else {
-			memPenalty = (healthMemWeight / 2) * (mem.UsedPercent - memNormalThreshold) / (memHighThreshold - memNormalThreshold)
+	// Memory penalty, including pressure.
+	if c := model.Memory; c.Enabled {
+		memPenalty := 0.0
+		if mem.UsedPercent > c.Normal {
+			if mem.UsedPercent > c.High {
+				memPenalty = c.Weight * (mem.UsedPercent - c.Normal) / c.Normal
+			} else {
+				memPenalty = (c.Weight / 2) * (mem.UsedPercent - c.Normal) / (c.High - c.Normal)
+			}
+		}
+		if mem.UsedPercent > c.High {
+			issues = append(issues, "High Memory")
 		}
-	}
-	score -= memPenalty
-	if mem.UsedPercent > memHighThreshold {
-		issues = append(issues, "High Memory")
-	}
 
-	// Memory pressure penalty.
-	switch mem.Pressure {
-	case "warn":
-		score -= memPressureWarnPenalty
-		issues = append(issues, "Memory Pressure")
-	case "critical":
-		score -= memPressureCritPenalty
-		issues = append(issues, "Critical Memory")
+		switch mem.Pressure {
+		case "warn":
+			memPenalty += model.MemPressureWarnPenalty
+			issues = append(issues, "Memory Pressure")
+		case "critical":
+			memPenalty += model.MemPressureCritPenalty
+			issues = append(issues, "Critical Memory")
+		}
+		charge("Memory", memPenalty)
 	}
 
 	// Disk penalty.
-	diskPenalty := 0.0
-	if len(disks) > 0 {
+	if c := model.Disk; c.Enabled && len(disks) > 0 {
+		diskPenalty := 0.0
 		diskUsage := disks[0].UsedPercent
-		if diskUsage > diskWarnThreshold {
-			if diskUsage > diskCritThreshold {
-				diskPenalty = healthDiskWeight * (diskUsage - diskWarnThreshold) / (100 - diskWarnThreshold)
+		if diskUsage > c.Normal {
+			if diskUsage > c.High {
+				diskPenalty = c.Weight * (diskUsage - c.Normal) / (100 - c.Normal)
 			} else {
-				diskPenalty = (healthDiskWeight / 2) * (diskUsage - diskWarnThreshold) / (diskCritThreshold - diskWarnThreshold)
+				diskPenalty = (c.Weight / 2) * (diskUsage - c.Normal) / (c.High - c.Normal)
 			}
 		}
-		score -= diskPenalty
-		if diskUsage > diskCritThreshold {
+		charge("Disk", diskPenalty)
+		if diskUsage > c.High {
 			issues = append(issues, "Disk Almost Full")
 		}
 	}
 
 	// Thermal penalty.
-	thermalPenalty := 0.0
-	if thermal.CPUTemp > 0 {
-		if thermal.CPUTemp > thermalNormalThreshold {
-			if thermal.CPUTemp > thermalHighThreshold {
-				thermalPenalty = healthThermalWeight
-				issues = append(issues, "Overheating")
-			} else {
-				thermalPenalty = healthThermalWeight * (thermal.CPUTemp - thermalNormalThreshold) / (thermalHighThreshold - thermalNormalThreshold)
-			}
+	if c := model.Thermal; c.Enabled && thermal.CPUTemp > 0 {
+		charge("Thermal", linearPenalty(c, thermal.CPUTemp))
+		if thermal.CPUTemp > c.High {
+			issues = append(issues, "Overheating")
 		}
-		score -= thermalPenalty
 	}
 
 	// Disk IO penalty.
-	ioPenalty := 0.0
-	totalIO := diskIO.ReadRate + diskIO.WriteRate
-	if totalIO > ioNormalThreshold {
-		if totalIO > ioHighThreshold {
-			ioPenalty = healthIOWeight
+	if c := model.IO; c.Enabled {
+		totalIO := diskIO.ReadRate + diskIO.WriteRate
+		charge("Disk IO", linearPenalty(c, totalIO))
+		if totalIO > c.High {
 			issues = append(issues, "Heavy Disk IO")
+		}
+	}
+
+	// Network saturation penalty.
+	if c := model.Network; c.Enabled {
+		totalNet := 0.0
+		for _, n := range busiestInterfaces(netStats) {
+			totalNet += n.RxRateMBs + n.TxRateMBs
+		}
+		charge("Network", linearPenalty(c, totalNet))
+		if totalNet > c.High {
+			issues = append(issues, "Network Saturated")
+		}
+	}
+
+	// Swap penalty.
+	if c := model.Swap; c.Enabled && mem.SwapTotal > 0 {
+		swapPercent := float64(mem.SwapUsed) / float64(mem.SwapTotal) * 100
+		charge("Swap", linearPenalty(c, swapPercent))
+		if swapPercent > c.High {
+			issues = append(issues, "Heavy Swap")
+		}
+	}
+
+	// Battery health penalty; capacity 0 means it could not be read.
+	if c := model.Battery; c.Enabled && len(batts) > 0 && batts[0].Capacity > 0 {
+		capacity := float64(batts[0].Capacity)
+		charge("Battery", linearPenalty(c, capacity))
+		if capacity < c.High {
+			issues = append(issues, "Battery Worn")
+		}
+	}
+
+	// Drive wear penalty; a failed SMART self-assessment costs the full weight.
+	if c := model.Wear; c.Enabled && len(drives) > 0 {
+		worst, failing := 0.0, false
+		for _, d := range drives {
+			if d.WearKnown {
+				worst = max(worst, d.PercentUsed)
+			}
+			failing = failing || !d.Passed
+		}
+		if failing {
+			charge("Disk Wear", c.Weight)
+			issues = append(issues, "Disk Failing")
 		} else {
-			ioPenalty = healthIOWeight * (totalIO - ioNormalThreshold) / (ioHighThreshold - ioNormalThreshold)
+			charge("Disk Wear", linearPenalty(c, worst))
+			if worst > c.High {
+				issues = append(issues, "Disk Worn")
+			}
 		}
 	}
-	score -= ioPenalty
 
 	// Clamp score.
 	if score < 0 {
@@ -150,7 +227,20 @@ func calculateHealthScore(cpu CPUStatus, mem MemoryStatus, disks []DiskStatus, d
 		msg = msg + ": " + strings.Join(issues, ", ")
 	}
 
-	return int(score), msg
+	return healthResult{Score: int(score), Message: msg, Penalties: penalties}
+}
+
+// linearPenalty scales from nothing at Normal to the full weight at High,
+// in whichever direction High lies.
+func linearPenalty(c healthComponent, value float64) float64 {
+	if c.High == c.Normal {
+		if value > c.High {
+			return c.Weight
+		}
+		return 0
+	}
+	frac := (value - c.Normal) / (c.High - c.Normal)
+	return c.Weight * min(max(frac, 0), 1)
 }
 
 func formatUptime(secs uint64) string {
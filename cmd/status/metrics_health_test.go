@@ -1,18 +1,23 @@
 package main
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
-func TestCalculateHealthScorePerfect(t *testing.T) {
-	score, msg := calculateHealthScore(
+func TestScoreHealthPerfect(t *testing.T) {
+	result := scoreHealth(defaultHealthModel(),
 		CPUStatus{Usage: 10},
 		MemoryStatus{UsedPercent: 20, Pressure: "normal"},
 		[]DiskStatus{{UsedPercent: 30}},
 		DiskIOStatus{ReadRate: 5, WriteRate: 5},
 		ThermalStatus{CPUTemp: 40},
+		nil, nil, nil,
 	)
+	score, msg := result.Score, result.Message
 
 	if score != 100 {
 		t.Fatalf("expected perfect score 100, got %d", score)
@@ -22,14 +27,16 @@ func TestCalculateHealthScorePerfect(t *testing.T) {
 	}
 }
 
-func TestCalculateHealthScoreDetectsIssues(t *testing.T) {
-	score, msg := calculateHealthScore(
+func TestScoreHealthDetectsIssues(t *testing.T) {
+	result := scoreHealth(defaultHealthModel(),
 		CPUStatus{Usage: 95},
 		MemoryStatus{UsedPercent: 90, Pressure: "critical"},
 		[]DiskStatus{{UsedPercent: 95}},
 		DiskIOStatus{ReadRate: 120, WriteRate: 80},
 		ThermalStatus{CPUTemp: 90},
+		nil, nil, nil,
 	)
+	score, msg := result.Score, result.Message
 
 	if score >= 40 {
 		t.Fatalf("expected heavy penalties bringing score down, got %d", score)
@@ -105,7 +112,7 @@ func TestColorizeTempStyleRanges(t *testing.T) {
 	}
 }
 
-func TestCalculateHealthScoreEdgeCases(t *testing.T) {
+func TestScoreHealthEdgeCases(t *testing.T) {
 	tests := []struct {
 		name    string
 		cpu     CPUStatus
@@ -160,9 +167,9 @@ func TestCalculateHealthScoreEdgeCases(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			score, _ := calculateHealthScore(tt.cpu, tt.mem, tt.disks, tt.diskIO, tt.thermal)
+			score := scoreHealth(defaultHealthModel(), tt.cpu, tt.mem, tt.disks, tt.diskIO, tt.thermal, nil, nil, nil).Score
 			if score < tt.wantMin || score > tt.wantMax {
-				t.Errorf("calculateHealthScore() = %d, want range [%d, %d]", score, tt.wantMin, tt.wantMax)
+				t.Errorf("scoreHealth() = %d, want range [%d, %d]", score, tt.wantMin, tt.wantMax)
 			}
 		})
 	}
@@ -194,3 +201,157 @@ func TestFormatUptimeEdgeCases(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadHealthModelOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "status_health")
+	config := "# build server\ncpu.normal=80\ncpu.high=98\nthermal.enabled=false\nswap.enabled=true\nswap.weight=20\n"
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	model, err := loadHealthModel(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if model.CPU.Normal != 80 || model.CPU.High != 98 {
+		t.Fatalf("cpu thresholds not applied: %+v", model.CPU)
+	}
+	if model.Thermal.Enabled {
+		t.Fatalf("thermal should be disabled")
+	}
+	if !model.Swap.Enabled || model.Swap.Weight != 20 {
+		t.Fatalf("swap override not applied: %+v", model.Swap)
+	}
+	if model.Memory != defaultHealthModel().Memory {
+		t.Fatalf("untouched components should keep defaults: %+v", model.Memory)
+	}
+}
+
+func TestLoadHealthModelMissingFile(t *testing.T) {
+	model, err := loadHealthModel(filepath.Join(t.TempDir(), "missing"))
+	if err != nil {
+		t.Fatalf("missing file should not be an error: %v", err)
+	}
+	if model != defaultHealthModel() {
+		t.Fatalf("expected defaults, got %+v", model)
+	}
+}
+
+func TestLoadHealthModelReportsBadLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "status_health")
+	config := "cpu.weight=abc\ngpu.weight=5\nnonsense\ndisk.normal=95\ndisk.high=80\nio.weight=7\n"
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	model, err := loadHealthModel(path)
+	if err == nil {
+		t.Fatalf("expected error for bad lines")
+	}
+	for _, want := range []string{"line 1", "line 2", "line 3", "disk thresholds"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("error %q should mention %q", err, want)
+		}
+	}
+	if model.Disk != defaultHealthModel().Disk {
+		t.Fatalf("invalid disk thresholds should fall back to defaults: %+v", model.Disk)
+	}
+	if model.IO.Weight != 7 {
+		t.Fatalf("valid lines should still apply, io weight %v", model.IO.Weight)
+	}
+}
+
+func TestConfigErrorsLeaveTheBanner(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dir := filepath.Join(home, ".config", "mole")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "status_health"), []byte("cpu.weight=abc\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := newModel()
+	if m.collector.ConfigErr() == nil {
+		t.Fatal("expected the bad line to be reported")
+	}
+	m = m.applyMetrics(MetricsSnapshot{}, nil)
+	if !strings.Contains(m.errMessage, "status_health: line 1") {
+		t.Fatalf("banner should show the config problem at startup, got %q", m.errMessage)
+	}
+	m.configErrUntil = time.Now().Add(-time.Second)
+	m = m.applyMetrics(MetricsSnapshot{}, nil)
+	if m.errMessage != "" {
+		t.Fatalf("banner should clear once the startup notice is over, got %q", m.errMessage)
+	}
+}
+
+func TestScoreHealthOptionalComponents(t *testing.T) {
+	model := defaultHealthModel()
+	model.Network.Enabled = true
+	model.Swap.Enabled = true
+	model.Battery.Enabled = true
+
+	result := scoreHealth(model,
+		CPUStatus{Usage: 10},
+		MemoryStatus{UsedPercent: 20, SwapUsed: 90, SwapTotal: 100},
+		[]DiskStatus{{UsedPercent: 30}},
+		DiskIOStatus{},
+		ThermalStatus{},
+		[]NetworkStatus{{RxRateMBs: 80, TxRateMBs: 40}},
+		[]BatteryStatus{{Capacity: 50}},
+		nil,
+	)
+
+	got := map[string]float64{}
+	for _, p := range result.Penalties {
+		got[p.Component] = p.Penalty
+	}
+	if len(got) != 3 || got["Network"] != 10 || got["Swap"] != 10 || got["Battery"] != 10 {
+		t.Fatalf("unexpected penalties: %+v", result.Penalties)
+	}
+	if result.Score != 70 {
+		t.Fatalf("expected score 70, got %d", result.Score)
+	}
+	for _, want := range []string{"Network Saturated", "Heavy Swap", "Battery Worn"} {
+		if !strings.Contains(result.Message, want) {
+			t.Fatalf("message %q should mention %q", result.Message, want)
+		}
+	}
+}
+
+func TestScoreHealthDiskWear(t *testing.T) {
+	score := func(drives []DiskHealth) healthResult {
+		return scoreHealth(defaultHealthModel(), CPUStatus{Usage: 10}, MemoryStatus{UsedPercent: 20},
+			[]DiskStatus{{UsedPercent: 30}}, DiskIOStatus{}, ThermalStatus{}, nil, nil, drives)
+	}
+
+	if result := score([]DiskHealth{{Passed: true, WearKnown: true, PercentUsed: 7}, {Passed: true}}); result.Score != 100 {
+		t.Errorf("healthy drives should cost nothing, got %+v", result)
+	}
+	result := score([]DiskHealth{{Passed: true, WearKnown: true, PercentUsed: 7}, {Passed: true, WearKnown: true, PercentUsed: 95}})
+	if len(result.Penalties) != 1 || result.Penalties[0] != (HealthPenalty{"Disk Wear", 15}) || !strings.Contains(result.Message, "Disk Worn") {
+		t.Errorf("worst drive should set the penalty, got %+v", result)
+	}
+	result = score([]DiskHealth{{Passed: false, WearKnown: true, PercentUsed: 1}})
+	if result.Score != 85 || !strings.Contains(result.Message, "Disk Failing") {
+		t.Errorf("failed self-assessment should cost the full weight, got %+v", result)
+	}
+
+	path := filepath.Join(t.TempDir(), "status_health")
+	if err := os.WriteFile(path, []byte("wear.enabled=false\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	model, err := loadHealthModel(path)
+	if err != nil || model.Wear.Enabled {
+		t.Fatalf("wear should be configurable, got %+v %v", model.Wear, err)
+	}
+}
+
+func TestFormatHealthBreakdown(t *testing.T) {
+	got := formatHealthBreakdown([]HealthPenalty{{"CPU", 4.2}, {"Disk IO", 0.3}, {"Memory", 11.6}})
+	if got != "Memory −12 · CPU −4" {
+		t.Fatalf("unexpected breakdown %q", got)
+	}
+}
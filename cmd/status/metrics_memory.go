@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
@@ -76,6 +78,9 @@ func getFileBackedMemory() uint64 {
 }
 
 func getMemoryPressure() string {
+	if runtime.GOOS == "linux" {
+		return readPSIMemoryPressure(procfsRoot())
+	}
 	if runtime.GOOS != "darwin" {
 		return ""
 	}
@@ -97,3 +102,76 @@ func getMemoryPressure() string {
 	}
 	return ""
 }
+
+// PSI stall percentages that map onto macOS pressure levels. "some" is the
+// share of time at least one task waited on memory, "full" the share all
+// of them did, so full stalls escalate sooner.
+const (
+	psiWarnSome10 = 10.0
+	psiWarnSome60 = 5.0
+	psiWarnFull10 = 1.0
+	psiCritFull10 = 10.0
+	psiCritFull60 = 5.0
+)
+
+// psiLine holds one line of a /proc/pressure file.
+type psiLine struct {
+	avg10, avg60 float64
+}
+
+// readPSIMemoryPressure maps /proc/pressure/memory onto normal, warn or
+// critical. Kernels without PSI (before 4.20, or psi=0) report "".
+func readPSIMemoryPressure(root string) string {
+	data, err := os.ReadFile(filepath.Join(root, "pressure", "memory"))
+	if err != nil {
+		return ""
+	}
+	some, full, ok := parsePSI(string(data))
+	if !ok {
+		return ""
+	}
+	switch {
+	case full.avg10 >= psiCritFull10 || full.avg60 >= psiCritFull60:
+		return "critical"
+	case some.avg10 >= psiWarnSome10 || some.avg60 >= psiWarnSome60 || full.avg10 >= psiWarnFull10:
+		return "warn"
+	}
+	return "normal"
+}
+
+// parsePSI reads the some and full lines:
+//
+//	some avg10=0.00 avg60=0.00 avg300=0.00 total=0
+//	full avg10=0.00 avg60=0.00 avg300=0.00 total=0
+func parsePSI(raw string) (some, full psiLine, ok bool) {
+	for line := range strings.Lines(raw) {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		var stat psiLine
+		for _, field := range fields[1:] {
+			key, value, found := strings.Cut(field, "=")
+			if !found {
+				continue
+			}
+			v, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			switch key {
+			case "avg10":
+				stat.avg10 = v
+			case "avg60":
+				stat.avg60 = v
+			}
+		}
+		switch fields[0] {
+		case "some":
+			some, ok = stat, true
+		case "full":
+			full = stat
+		}
+	}
+	return some, full, ok
+}
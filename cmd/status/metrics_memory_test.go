@@ -0,0 +1,36 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestReadPSIMemoryPressure(t *testing.T) {
+	tests := []struct {
+		name string
+		psi  string
+		want string
+	}{
+		{"idle", "some avg10=0.00 avg60=0.00 avg300=0.00 total=0\nfull avg10=0.00 avg60=0.00 avg300=0.00 total=0\n", "normal"},
+		{"some stalls", "some avg10=12.50 avg60=3.10 avg300=0.90 total=812345\nfull avg10=0.40 avg60=0.10 avg300=0.02 total=40123\n", "warn"},
+		{"sustained some", "some avg10=2.00 avg60=6.00 avg300=1.00 total=1\nfull avg10=0.00 avg60=0.00 avg300=0.00 total=0\n", "warn"},
+		{"full stalls", "some avg10=55.00 avg60=30.00 avg300=8.00 total=9\nfull avg10=21.00 avg60=4.00 avg300=1.00 total=9\n", "critical"},
+		{"sustained full", "some avg10=9.00 avg60=9.00 avg300=9.00 total=9\nfull avg10=4.00 avg60=5.50 avg300=2.00 total=9\n", "critical"},
+		// Older kernels only have the some line.
+		{"some only", "some avg10=0.10 avg60=0.00 avg300=0.00 total=5\n", "normal"},
+		{"garbage", "not psi\n", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeSysfs(t, root, map[string]string{"pressure/memory": tt.psi})
+			if got := readPSIMemoryPressure(root); got != tt.want {
+				t.Errorf("readPSIMemoryPressure = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := readPSIMemoryPressure(filepath.Join(t.TempDir(), "missing")); got != "" {
+		t.Errorf("kernel without PSI should report no pressure, got %q", got)
+	}
+}
@@ -19,13 +19,33 @@ func (c *Collector) collectNetwork(now time.Time) ([]NetworkStatus, error) {
 
 	// Map interface IPs.
 	ifAddrs := getInterfaceIPs()
+	result := c.networkRates(stats, ifAddrs, now)
+	if result != nil {
+		c.addNetworkHistory(result)
+	}
+	return result, nil
+}
+
+// networkRates turns interface counters into rates since the previous
+// call and totals since each interface was first seen, busiest first.
+// The first call only records counters and returns nil.
+func (c *Collector) networkRates(stats []net.IOCountersStat, ifAddrs map[string]string, now time.Time) []NetworkStatus {
+	if c.startNet == nil {
+		c.startNet = make(map[string]net.IOCountersStat)
+	}
+	for _, s := range stats {
+		// Counters that went backwards mean the interface was recreated.
+		if start, ok := c.startNet[s.Name]; !ok || s.BytesRecv < start.BytesRecv || s.BytesSent < start.BytesSent {
+			c.startNet[s.Name] = s
+		}
+	}
 
 	if c.lastNetAt.IsZero() {
 		c.lastNetAt = now
 		for _, s := range stats {
 			c.prevNet[s.Name] = s
 		}
-		return nil, nil
+		return nil
 	}
 
 	elapsed := now.Sub(c.lastNetAt).Seconds()
@@ -33,28 +53,27 @@ func (c *Collector) collectNetwork(now time.Time) ([]NetworkStatus, error) {
 		elapsed = 1
 	}
 
-	var result []NetworkStatus
+	result := []NetworkStatus{}
 	for _, cur := range stats {
-		if isNoiseInterface(cur.Name) {
+		if c.network.isNoise(cur.Name) {
 			continue
 		}
 		prev, ok := c.prevNet[cur.Name]
 		if !ok {
 			continue
 		}
-		rx := float64(cur.BytesRecv-prev.BytesRecv) / 1024.0 / 1024.0 / elapsed
-		tx := float64(cur.BytesSent-prev.BytesSent) / 1024.0 / 1024.0 / elapsed
-		if rx < 0 {
-			rx = 0
-		}
-		if tx < 0 {
-			tx = 0
-		}
+		start := c.startNet[cur.Name]
 		result = append(result, NetworkStatus{
 			Name:      cur.Name,
-			RxRateMBs: rx,
-			TxRateMBs: tx,
+			RxRateMBs: float64(counterDelta(cur.BytesRecv, prev.BytesRecv)) / 1024.0 / 1024.0 / elapsed,
+			TxRateMBs: float64(counterDelta(cur.BytesSent, prev.BytesSent)) / 1024.0 / 1024.0 / elapsed,
 			IP:        ifAddrs[cur.Name],
+			RxTotal:   counterDelta(cur.BytesRecv, start.BytesRecv),
+			TxTotal:   counterDelta(cur.BytesSent, start.BytesSent),
+			ErrIn:     counterDelta(cur.Errin, start.Errin),
+			ErrOut:    counterDelta(cur.Errout, start.Errout),
+			DropIn:    counterDelta(cur.Dropin, start.Dropin),
+			DropOut:   counterDelta(cur.Dropout, start.Dropout),
 		})
 	}
 
@@ -66,21 +85,75 @@ func (c *Collector) collectNetwork(now time.Time) ([]NetworkStatus, error) {
 	sort.Slice(result, func(i, j int) bool {
 		return result[i].RxRateMBs+result[i].TxRateMBs > result[j].RxRateMBs+result[j].TxRateMBs
 	})
-	if len(result) > 3 {
-		result = result[:3]
+	return result
+}
+
+// counterDelta is cur-prev for counters that only grow, or 0 when the
+// counter was reset in between.
+func counterDelta(cur, prev uint64) uint64 {
+	if cur < prev {
+		return 0
 	}
+	return cur - prev
+}
+
+// interfaceHistoryBuf holds one interface's recent rates.
+type interfaceHistoryBuf struct {
+	rx, tx *RingBuffer
+}
 
+// addNetworkHistory records the busiest interfaces' combined rates and each
+// interface's own. Interfaces that went away drop their history.
+func (c *Collector) addNetworkHistory(stats []NetworkStatus) {
 	var totalRx, totalTx float64
-	for _, r := range result {
-		totalRx += r.RxRateMBs
-		totalTx += r.TxRateMBs
+	for _, n := range busiestInterfaces(stats) {
+		totalRx += n.RxRateMBs
+		totalTx += n.TxRateMBs
 	}
-
-	// Update history using the global/aggregated stats
 	c.rxHistoryBuf.Add(totalRx)
 	c.txHistoryBuf.Add(totalTx)
 
-	return result, nil
+	if c.ifaceHistory == nil {
+		c.ifaceHistory = make(map[string]*interfaceHistoryBuf)
+	}
+	seen := make(map[string]bool, len(stats))
+	for _, n := range stats {
+		buf := c.ifaceHistory[n.Name]
+		if buf == nil {
+			buf = &interfaceHistoryBuf{rx: NewRingBuffer(NetworkHistorySize), tx: NewRingBuffer(NetworkHistorySize)}
+			c.ifaceHistory[n.Name] = buf
+		}
+		buf.rx.Add(n.RxRateMBs)
+		buf.tx.Add(n.TxRateMBs)
+		seen[n.Name] = true
+	}
+	for name := range c.ifaceHistory {
+		if !seen[name] {
+			delete(c.ifaceHistory, name)
+		}
+	}
+}
+
+func (c *Collector) networkHistory() NetworkHistory {
+	history := NetworkHistory{
+		RxHistory:  c.rxHistoryBuf.Slice(),
+		TxHistory:  c.txHistoryBuf.Slice(),
+		Interfaces: make(map[string]InterfaceHistory, len(c.ifaceHistory)),
+	}
+	for name, buf := range c.ifaceHistory {
+		history.Interfaces[name] = InterfaceHistory{Rx: buf.rx.Slice(), Tx: buf.tx.Slice()}
+	}
+	return history
+}
+
+// busiestInterfaceCount bounds the interfaces summed into network totals
+// and shown on the card; the detail view lists them all.
+const busiestInterfaceCount = 3
+
+// busiestInterfaces returns the leading entries of stats, which
+// collectNetwork sorts busiest first.
+func busiestInterfaces(stats []NetworkStatus) []NetworkStatus {
+	return stats[:min(len(stats), busiestInterfaceCount)]
 }
 
 func getInterfaceIPs() map[string]string {
@@ -102,17 +175,6 @@ func getInterfaceIPs() map[string]string {
 	return result
 }
 
-func isNoiseInterface(name string) bool {
-	lower := strings.ToLower(name)
-	noiseList := []string{"lo", "awdl", "utun", "llw", "bridge", "gif", "stf", "xhc", "anpi", "ap"}
-	for _, prefix := range noiseList {
-		if strings.HasPrefix(lower, prefix) {
-			return true
-		}
-	}
-	return false
-}
-
 func collectProxy() ProxyStatus {
 	// Check environment variables first.
 	for _, env := range []string{"https_proxy", "HTTPS_PROXY", "http_proxy", "HTTP_PROXY"} {
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/net"
+)
+
+func TestLoadNetworkConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "status_network")
+	content := "# container host\nnoise+=veth, Docker\nnoise-=utun,ap\nbogus\nshow=en0\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := loadNetworkConfig(path)
+	if err == nil || !strings.Contains(err.Error(), "line 4") || !strings.Contains(err.Error(), `line 5: unknown key "show"`) {
+		t.Errorf("bad lines should be reported, got %v", err)
+	}
+	for name, want := range map[string]bool{"veth12ab": true, "docker0": true, "lo0": true, "utun3": false, "ap1": false, "en0": false} {
+		if got := cfg.isNoise(name); got != want {
+			t.Errorf("isNoise(%q) = %v, want %v", name, got, want)
+		}
+	}
+
+	if err := os.WriteFile(path, []byte("noise=\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if cfg, err := loadNetworkConfig(path); err != nil || cfg.isNoise("lo0") {
+		t.Errorf("noise= should count every interface, got %v %v", cfg.noise, err)
+	}
+	if cfg, err := loadNetworkConfig(filepath.Join(t.TempDir(), "missing")); err != nil || !slices.Equal(cfg.noise, defaultNoiseInterfaces) {
+		t.Errorf("missing file should yield defaults, got %v %v", cfg.noise, err)
+	}
+}
+
+func TestNetworkRatesAndTotals(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	c := NewCollector()
+	start := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	counters := func(recv, sent, errin, dropin uint64) []net.IOCountersStat {
+		return []net.IOCountersStat{
+			{Name: "en0", BytesRecv: recv, BytesSent: sent, Errin: errin, Dropin: dropin},
+			{Name: "lo0", BytesRecv: recv * 10, BytesSent: sent * 10},
+		}
+	}
+
+	if got := c.networkRates(counters(100<<20, 10<<20, 7, 1), nil, start); got != nil {
+		t.Fatalf("first call should only prime counters, got %+v", got)
+	}
+	stats := c.networkRates(counters(102<<20, 11<<20, 9, 1), map[string]string{"en0": "10.0.0.2"}, start.Add(time.Second))
+	if len(stats) != 1 || stats[0].Name != "en0" {
+		t.Fatalf("noise interfaces should be left out, got %+v", stats)
+	}
+	en0 := stats[0]
+	if en0.RxRateMBs != 2 || en0.TxRateMBs != 1 || en0.IP != "10.0.0.2" {
+		t.Errorf("unexpected rates %+v", en0)
+	}
+	if en0.RxTotal != 2<<20 || en0.TxTotal != 1<<20 || en0.ErrIn != 2 || en0.DropIn != 0 {
+		t.Errorf("totals should count from the first sample, got %+v", en0)
+	}
+
+	// A recreated interface restarts its counters instead of wrapping.
+	stats = c.networkRates(counters(1<<20, 1<<20, 0, 0), nil, start.Add(2*time.Second))
+	if stats[0].RxRateMBs != 0 || stats[0].RxTotal != 0 {
+		t.Errorf("reset counters should not wrap, got %+v", stats[0])
+	}
+
+	c.addNetworkHistory(stats)
+	c.addNetworkHistory([]NetworkStatus{{Name: "en0", RxRateMBs: 3}, {Name: "en1", TxRateMBs: 1}})
+	c.addNetworkHistory([]NetworkStatus{{Name: "en1", TxRateMBs: 2}})
+	history := c.networkHistory()
+	if _, ok := history.Interfaces["en0"]; ok {
+		t.Error("history of an interface that went away should be dropped")
+	}
+	if got := history.Interfaces["en1"].Tx; !slices.Equal(got, []float64{1, 2}) {
+		t.Errorf("en1 tx history = %v", got)
+	}
+	if !slices.Equal(history.RxHistory, []float64{0, 3, 0}) {
+		t.Errorf("total rx history = %v", history.RxHistory)
+	}
+}
+
+func TestActiveConnections(t *testing.T) {
+	conns := activeConnections([]net.ConnectionStat{
+		{Type: syscall.SOCK_STREAM, Family: syscall.AF_INET, Pid: 20, Status: "ESTABLISHED",
+			Laddr: net.Addr{IP: "10.0.0.2", Port: 50000}, Raddr: net.Addr{IP: "140.82.112.3", Port: 443}},
+		{Type: syscall.SOCK_STREAM, Family: syscall.AF_INET6, Pid: 20, Status: "ESTABLISHED",
+			Laddr: net.Addr{IP: "::1", Port: 50001}, Raddr: net.Addr{IP: "2606:4700::6810:84e5", Port: 443}},
+		{Type: syscall.SOCK_STREAM, Family: syscall.AF_INET, Pid: 20, Status: "ESTABLISHED",
+			Laddr: net.Addr{IP: "10.0.0.2", Port: 50002}, Raddr: net.Addr{IP: "140.82.112.3", Port: 443}},
+		{Type: syscall.SOCK_DGRAM, Family: syscall.AF_INET, Pid: 10, Status: "NONE", Laddr: net.Addr{IP: "0.0.0.0", Port: 5353}},
+		// Listening sockets and other users' sockets are not shown.
+		{Type: syscall.SOCK_STREAM, Family: syscall.AF_INET, Pid: 30, Status: "LISTEN", Laddr: net.Addr{IP: "0.0.0.0", Port: 22}},
+		{Type: syscall.SOCK_STREAM, Family: syscall.AF_INET, Pid: 0, Status: "ESTABLISHED", Raddr: net.Addr{IP: "1.1.1.1", Port: 53}},
+	})
+	if len(conns) != 4 || conns[0].PID != 10 || conns[0].Proto != "udp" || conns[0].Remote != "" {
+		t.Fatalf("unexpected connections %+v", conns)
+	}
+	if conns[2].Proto != "tcp6" || conns[2].Remote != "[2606:4700::6810:84e5]:443" {
+		t.Errorf("IPv6 connection = %+v", conns[2])
+	}
+
+	named := namedConnections(conns, map[int32]processSample{20: {name: "git-remote-https"}})
+	if named[1].Process != "git-remote-https" || named[0].Process != "pid 10" || conns[1].Process != "" {
+		t.Errorf("names not applied to a copy: %+v / %+v", named, conns)
+	}
+	groups := groupConnections(named)
+	if len(groups) != 2 || groups[0].Name != "git-remote-https" || groups[0].TCP != 3 || len(groups[0].Remotes) != 2 || groups[1].UDP != 1 {
+		t.Errorf("unexpected groups %+v", groups)
+	}
+}
+
+func TestCollectWalksConnectionsOnlyForNetworkDetail(t *testing.T) {
+	c := NewCollector()
+	if data, _ := c.Collect(); !c.lastConnAt.IsZero() || data.Connections != nil {
+		t.Fatalf("connections should not be walked with the detail view closed")
+	}
+	c.setConnectionDetail(true)
+	_, _ = c.Collect()
+	if c.lastConnAt.IsZero() {
+		t.Fatal("connections should be walked while the detail view is open")
+	}
+	c.setConnectionDetail(false)
+	_, _ = c.Collect()
+	if !c.lastConnAt.IsZero() {
+		t.Fatal("closing the view should expire the cached connections")
+	}
+}
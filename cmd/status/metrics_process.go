@@ -2,52 +2,260 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"runtime"
-	"strconv"
+	"slices"
+	"sort"
 	"strings"
 	"time"
+
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+const (
+	topProcessCount = 5
+	// processTimeout bounds the reads for one process, so a process that
+	// hangs (a stuck FUSE mount, say) costs its own row and not the rest.
+	processTimeout = 500 * time.Millisecond
 )
 
-func collectTopProcesses() []ProcessInfo {
-	if runtime.GOOS != "darwin" {
+// processSample is what the previous collection saw for one PID. CPU and IO
+// rates are deltas against it; name, user and command line rarely change, so
+// they are looked up once per process instead of every second.
+type processSample struct {
+	createTime int64
+	cpuSeconds float64
+	ioBytes    uint64
+	hasIO      bool
+	name       string
+	user       string
+	command    string
+}
+
+// setProcessDetail asks later collections for every process in full, as the
+// process pane shows, instead of only the busiest few.
+func (c *Collector) setProcessDetail(on bool) {
+	c.processDetail.Store(on)
+}
+
+// collectProcesses returns processes sorted by CPU. Every process is timed
+// so CPU rates stay continuous, but memory, threads, IO and command lines
+// are read only for the top few unless the process pane asked for all of
+// them. Rates are zero on the first collection and for processes started
+// since the previous one.
+func (c *Collector) collectProcesses(now time.Time) []ProcessInfo {
+	ctx, cancel := context.WithTimeout(context.Background(), processTimeout)
+	pids, err := process.PidsWithContext(ctx)
+	cancel()
+	if err != nil {
 		return nil
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+
+	elapsed := now.Sub(c.lastProcAt).Seconds()
+	if c.lastProcAt.IsZero() || elapsed <= 0 {
+		elapsed = 0
+	}
+
+	samples := make(map[int32]processSample, len(pids))
+	result := make([]ProcessInfo, 0, len(pids))
+	handles := make(map[int32]*process.Process, len(pids))
+	for _, pid := range pids {
+		info, sample, p, ok := c.sampleProcess(pid, elapsed)
+		if !ok {
+			continue
+		}
+		samples[pid] = sample
+		handles[pid] = p
+		result = append(result, info)
+	}
+
+	sortProcesses(result, processSortCPU)
+	if !c.processDetail.Load() {
+		result = result[:min(len(result), topProcessCount)]
+	}
+	for i := range result {
+		pid := result[i].PID
+		sample := samples[pid]
+		c.detailProcess(handles[pid], &result[i], &sample, elapsed)
+		samples[pid] = sample
+	}
+	result = slices.DeleteFunc(result, func(p ProcessInfo) bool { return p.Name == "" })
+
+	c.prevProcs = samples
+	c.lastProcAt = now
+	return result
+}
+
+// sampleProcess reads what every process needs for CPU ranking.
+func (c *Collector) sampleProcess(pid int32, elapsed float64) (ProcessInfo, processSample, *process.Process, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), processTimeout)
 	defer cancel()
 
-	// Use ps to get top processes by CPU.
-	out, err := runCmd(ctx, "ps", "-Aceo", "pcpu,pmem,comm", "-r")
+	p, err := process.NewProcessWithContext(ctx, pid)
 	if err != nil {
-		return nil
+		return ProcessInfo{}, processSample{}, nil, false
+	}
+	createTime, err := p.CreateTimeWithContext(ctx)
+	if err != nil {
+		return ProcessInfo{}, processSample{}, nil, false
+	}
+	prev, seen := c.prevProcs[pid]
+	if seen && prev.createTime != createTime {
+		// PID was reused by a new process.
+		seen = false
 	}
 
-	var procs []ProcessInfo
-	i := 0
-	for line := range strings.Lines(strings.TrimSpace(out)) {
-		if i == 0 {
-			i++
-			continue
+	sample := processSample{createTime: createTime}
+	if seen {
+		sample.name, sample.user, sample.command = prev.name, prev.user, prev.command
+	} else {
+		sample.name, _ = p.NameWithContext(ctx)
+	}
+	if times, err := p.TimesWithContext(ctx); err == nil {
+		sample.cpuSeconds = times.User + times.System
+	}
+
+	info := ProcessInfo{PID: pid, CreateTime: createTime, Name: sample.name}
+	if seen && elapsed > 0 {
+		info.CPU = max((sample.cpuSeconds-prev.cpuSeconds)/elapsed*100, 0)
+	}
+	return info, sample, p, true
+}
+
+// detailProcess fills in the fields only shown for listed processes.
+func (c *Collector) detailProcess(p *process.Process, info *ProcessInfo, sample *processSample, elapsed float64) {
+	ctx, cancel := context.WithTimeout(context.Background(), processTimeout)
+	defer cancel()
+
+	prev, seen := c.prevProcs[info.PID]
+	seen = seen && prev.createTime == sample.createTime
+	if sample.command == "" {
+		sample.user, _ = p.UsernameWithContext(ctx)
+		sample.command, _ = p.CmdlineWithContext(ctx)
+	}
+	info.User, info.Command = sample.user, sample.command
+	if info.Name == "" {
+		info.Name = commandName(info.Command)
+		sample.name = info.Name
+	}
+	if info.Command == "" {
+		info.Command = info.Name
+	}
+
+	if mem, err := p.MemoryInfoWithContext(ctx); err == nil {
+		info.RSS = mem.RSS
+	}
+	if threads, err := p.NumThreadsWithContext(ctx); err == nil {
+		info.Threads = threads
+	}
+	// IO counters need elevated rights for other users' processes on
+	// Linux and are not exposed on macOS; those rows show no IO.
+	if io, err := p.IOCountersWithContext(ctx); err == nil {
+		sample.ioBytes = diskIOBytes(io)
+		sample.hasIO = true
+		if seen && prev.hasIO && elapsed > 0 && sample.ioBytes >= prev.ioBytes {
+			info.IORate = float64(sample.ioBytes-prev.ioBytes) / 1024.0 / 1024.0 / elapsed
 		}
-		if i > 5 {
-			break
+	}
+}
+
+// diskIOBytes returns the bytes a process moved to and from storage. On
+// Linux ReadBytes and WriteBytes are rchar and wchar, which also count
+// page-cache hits, pipes and sockets; read_bytes and write_bytes are the
+// part that reached the disk.
+func diskIOBytes(io *process.IOCountersStat) uint64 {
+	if runtime.GOOS == "linux" {
+		return io.DiskReadBytes + io.DiskWriteBytes
+	}
+	return io.ReadBytes + io.WriteBytes
+}
+
+// commandName returns the executable name from a command line.
+func commandName(cmdline string) string {
+	fields := strings.Fields(cmdline)
+	if len(fields) == 0 {
+		return ""
+	}
+	name := fields[0]
+	if idx := strings.LastIndex(name, "/"); idx >= 0 {
+		name = name[idx+1:]
+	}
+	return name
+}
+
+// setMemoryPercent fills Memory from RSS once total memory is known.
+func setMemoryPercent(procs []ProcessInfo, total uint64) {
+	if total == 0 {
+		return
+	}
+	for i := range procs {
+		procs[i].Memory = float64(procs[i].RSS) / float64(total) * 100
+	}
+}
+
+type processSort int
+
+const (
+	processSortCPU processSort = iota
+	processSortMemory
+	processSortIO
+)
+
+func (s processSort) String() string {
+	switch s {
+	case processSortMemory:
+		return "Memory"
+	case processSortIO:
+		return "IO"
+	default:
+		return "CPU"
+	}
+}
+
+func (s processSort) next() processSort {
+	return (s + 1) % 3
+}
+
+// sortProcesses orders procs by key, descending, with PID as tie-break so
+// rows do not jump around between refreshes.
+func sortProcesses(procs []ProcessInfo, key processSort) {
+	value := func(p ProcessInfo) float64 {
+		switch key {
+		case processSortMemory:
+			return float64(p.RSS)
+		case processSortIO:
+			return p.IORate
+		default:
+			return p.CPU
 		}
-		i++
-		fields := strings.Fields(line)
-		if len(fields) < 3 {
-			continue
+	}
+	sort.SliceStable(procs, func(i, j int) bool {
+		a, b := value(procs[i]), value(procs[j])
+		if a != b {
+			return a > b
+		}
+		return procs[i].PID < procs[j].PID
+	})
+}
+
+// terminateProcess sends SIGTERM to target, refusing if the PID now belongs
+// to a different process than the one the user confirmed.
+func terminateProcess(target ProcessInfo) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	p, err := process.NewProcessWithContext(ctx, target.PID)
+	if err != nil {
+		if errors.Is(err, process.ErrorProcessNotRunning) {
+			return fmt.Errorf("process %d has already exited", target.PID)
 		}
-		cpuVal, _ := strconv.ParseFloat(fields[0], 64)
-		memVal, _ := strconv.ParseFloat(fields[1], 64)
-		name := fields[len(fields)-1]
-		// Strip path from command name.
-		if idx := strings.LastIndex(name, "/"); idx >= 0 {
-			name = name[idx+1:]
+		return err
+	}
+	if target.CreateTime != 0 {
+		if createTime, err := p.CreateTimeWithContext(ctx); err == nil && createTime != target.CreateTime {
+			return fmt.Errorf("process %d has already exited", target.PID)
 		}
-		procs = append(procs, ProcessInfo{
-			Name:   name,
-			CPU:    cpuVal,
-			Memory: memVal,
-		})
 	}
-	return procs
+	return p.TerminateWithContext(ctx)
 }
@@ -0,0 +1,284 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSortProcesses(t *testing.T) {
+	procs := []ProcessInfo{
+		{PID: 3, CPU: 5, RSS: 300, IORate: 0},
+		{PID: 1, CPU: 50, RSS: 100, IORate: 2},
+		{PID: 2, CPU: 5, RSS: 200, IORate: 1},
+	}
+
+	tests := []struct {
+		key  processSort
+		want []int32
+	}{
+		{processSortCPU, []int32{1, 2, 3}},
+		{processSortMemory, []int32{3, 2, 1}},
+		{processSortIO, []int32{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key.String(), func(t *testing.T) {
+			sorted := append([]ProcessInfo(nil), procs...)
+			sortProcesses(sorted, tt.key)
+			for i, pid := range tt.want {
+				if sorted[i].PID != pid {
+					t.Fatalf("sortProcesses(%s) order = %v, want %v", tt.key, pids(sorted), tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestProcessSortCycles(t *testing.T) {
+	key := processSortCPU
+	seen := []string{}
+	for range 4 {
+		seen = append(seen, key.String())
+		key = key.next()
+	}
+	if got := strings.Join(seen, ","); got != "CPU,Memory,IO,CPU" {
+		t.Fatalf("sort cycle = %s", got)
+	}
+}
+
+func TestCommandName(t *testing.T) {
+	tests := map[string]string{
+		"/usr/bin/python3 -m http.server": "python3",
+		"nginx: worker":                   "nginx:",
+		"":                                "",
+	}
+	for in, want := range tests {
+		if got := commandName(in); got != want {
+			t.Errorf("commandName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestCollectProcessesIncludesSelf(t *testing.T) {
+	c := NewCollector()
+	c.setProcessDetail(true)
+	now := time.Now()
+	_ = c.collectProcesses(now)
+	procs := c.collectProcesses(now.Add(time.Second))
+
+	self := int32(os.Getpid())
+	for _, p := range procs {
+		if p.PID != self {
+			continue
+		}
+		if p.Name == "" || p.Command == "" {
+			t.Fatalf("own process missing name or command: %+v", p)
+		}
+		if p.RSS == 0 || p.Threads == 0 {
+			t.Fatalf("own process missing RSS or threads: %+v", p)
+		}
+		if p.CPU < 0 || p.IORate < 0 {
+			t.Fatalf("negative rates: %+v", p)
+		}
+		return
+	}
+	t.Fatalf("own PID %d not in %d processes", self, len(procs))
+}
+
+func TestCollectProcessesTopOnlyWhenPaneClosed(t *testing.T) {
+	c := NewCollector()
+	now := time.Now()
+	_ = c.collectProcesses(now)
+	top := c.collectProcesses(now.Add(time.Second))
+	if len(top) > topProcessCount {
+		t.Fatalf("expected at most %d processes with the pane closed, got %d", topProcessCount, len(top))
+	}
+	for _, p := range top {
+		if p.Name == "" || p.Command == "" {
+			t.Fatalf("top process missing details: %+v", p)
+		}
+	}
+	if len(c.prevProcs) <= topProcessCount {
+		t.Skipf("only %d processes running, too few to tell the modes apart", len(c.prevProcs))
+	}
+
+	c.setProcessDetail(true)
+	if all := c.collectProcesses(now.Add(2 * time.Second)); len(all) <= len(top) {
+		t.Fatalf("expected the full list with the pane open, got %d processes", len(all))
+	}
+}
+
+func TestTerminateProcess(t *testing.T) {
+	cmd := exec.Command("sleep", "30")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("sleep unavailable: %v", err)
+	}
+	pid := int32(cmd.Process.Pid)
+
+	// A stale create time means the PID now names another process.
+	if err := terminateProcess(ProcessInfo{PID: pid, CreateTime: 1}); err == nil {
+		t.Fatal("terminateProcess should refuse a reused PID")
+	}
+
+	c := NewCollector()
+	c.setProcessDetail(true)
+	var target ProcessInfo
+	for _, p := range c.collectProcesses(time.Now()) {
+		if p.PID == pid {
+			target = p
+		}
+	}
+	if target.PID == 0 {
+		t.Fatalf("spawned process %d not collected", pid)
+	}
+	if err := terminateProcess(target); err != nil {
+		t.Fatalf("terminateProcess: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	select {
+	case err := <-done:
+		if err == nil || !strings.Contains(err.Error(), "terminated") {
+			t.Fatalf("sleep exited with %v, want SIGTERM", err)
+		}
+	case <-time.After(5 * time.Second):
+		_ = cmd.Process.Kill()
+		t.Fatal("process did not exit after SIGTERM")
+	}
+}
+
+func TestProcessPaneKeys(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	m := newModel()
+	m.ready = true
+	m.metrics.Processes = []ProcessInfo{
+		{PID: 10, Name: "busy", Command: "/bin/busy", CPU: 90, RSS: 1 << 20},
+		{PID: 20, Name: "big", Command: "/bin/big --cache", CPU: 1, RSS: 1 << 30},
+	}
+
+	press := func(key string) tea.Cmd {
+		t.Helper()
+		var msg tea.KeyMsg
+		switch key {
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		}
+		next, cmd := m.Update(msg)
+		m = next.(model)
+		return cmd
+	}
+
+	press("p")
+	if !m.showProcesses {
+		t.Fatal("p should open the process pane")
+	}
+	view := m.View()
+	if !strings.Contains(view, "/bin/big --cache") || !strings.Contains(view, "Sort: CPU") {
+		t.Fatalf("pane missing rows or sort label:\n%s", view)
+	}
+
+	press("m")
+	if m.procSort != processSortMemory {
+		t.Fatalf("m should sort by memory, got %s", m.procSort)
+	}
+	if procs := m.sortedProcesses(); procs[0].PID != 20 {
+		t.Fatalf("memory sort first PID = %d, want 20", procs[0].PID)
+	}
+
+	press("down")
+	if m.procPID != 10 {
+		t.Fatalf("down should select PID 10, got %d", m.procPID)
+	}
+
+	press("x")
+	if m.confirmTerm == nil || m.confirmTerm.PID != 10 {
+		t.Fatalf("x should ask to terminate PID 10, got %+v", m.confirmTerm)
+	}
+	if view := m.View(); !strings.Contains(view, "Terminate busy (PID 10)?") {
+		t.Fatalf("confirm prompt missing:\n%s", view)
+	}
+	if cmd := press("n"); cmd != nil || m.confirmTerm != nil {
+		t.Fatal("any key other than y should cancel without terminating")
+	}
+
+	press("x")
+	if cmd := press("y"); cmd == nil {
+		t.Fatal("y should start terminating")
+	}
+
+	press("esc")
+	if m.showProcesses {
+		t.Fatal("esc should close the pane instead of quitting")
+	}
+}
+
+func pids(procs []ProcessInfo) []int32 {
+	out := make([]int32, len(procs))
+	for i, p := range procs {
+		out[i] = p.PID
+	}
+	return out
+}
+
+func TestCollectProcessesFromProcFixture(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("procfs is Linux only")
+	}
+	// gopsutil checks that a PID is alive before reading a procfs tree that
+	// is not a mount, so the fixture borrows the test's own PID.
+	pid := os.Getpid()
+	dir := strconv.Itoa(pid) + "/"
+	stat := func(utime int) string {
+		return fmt.Sprintf("%d (postgres) S 1 %d %d 0 -1 4194560 100 0 0 0 %d 0 0 0 20 0 6 0 500 104857600 2560 18446744073709551615 0 0 0 0 0 0 0 0 0 0 0 0 17 0 0 0 0 0 0", pid, pid, pid, utime)
+	}
+	// rchar also counts pipe and page-cache reads; only read_bytes hit the disk.
+	io := func(read int) string {
+		return fmt.Sprintf("rchar: %d\nwchar: 0\nsyscr: 0\nsyscw: 0\nread_bytes: %d\nwrite_bytes: 0\ncancelled_write_bytes: 0\n", 50*read, read)
+	}
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		"stat":           "cpu  0 0 0 0 0 0 0 0 0 0\nbtime 1767225600",
+		dir + "stat":     stat(100),
+		dir + "status":   "Name:\tpostgres\nState:\tS (sleeping)\nPPid:\t1\nUid:\t0\t0\t0\t0\nGid:\t0\t0\t0\t0\nThreads:\t6",
+		dir + "statm":    "25600 2560 512 10 0 3000 0",
+		dir + "cmdline":  "postgres\x00-D\x00/var/lib/postgres\x00",
+		dir + "io":       io(1 << 20),
+		"not-a-pid/stat": "ignored",
+	})
+	t.Setenv("HOST_PROC", root)
+
+	c := NewCollector()
+	now := time.Now()
+	if procs := c.collectProcesses(now); len(procs) != 1 {
+		t.Fatalf("expected the fixture process only, got %+v", procs)
+	}
+	// One second later the process has used 50 more ticks and read 1MB from
+	// disk, among 50MB read in total.
+	writeSysfs(t, root, map[string]string{dir + "stat": stat(150), dir + "io": io(2 << 20)})
+	procs := c.collectProcesses(now.Add(time.Second))
+	if len(procs) != 1 {
+		t.Fatalf("expected one process, got %+v", procs)
+	}
+	p := procs[0]
+	if p.PID != int32(pid) || p.Name != "postgres" || p.Command != "postgres -D /var/lib/postgres" || p.User != "root" {
+		t.Errorf("unexpected identity %+v", p)
+	}
+	if p.Threads != 6 || p.RSS != 2560*uint64(os.Getpagesize()) {
+		t.Errorf("threads/RSS = %d/%d", p.Threads, p.RSS)
+	}
+	if p.CPU != 50 || p.IORate != 1 {
+		t.Errorf("CPU/IO = %v/%v, want 50%% and 1MB/s", p.CPU, p.IORate)
+	}
+}
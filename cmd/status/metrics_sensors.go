@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/sensors"
+)
+
+const (
+	sensorsTimeout = 500 * time.Millisecond
+	// Readings outside this range are disconnected or broken probes.
+	minSensorTemp = 1.0
+	maxSensorTemp = 150.0
+)
+
+// collectSensors reads temperature sensors through gopsutil, which uses
+// hwmon (falling back to thermal zones) on Linux and SMC/IOKit on macOS.
+func collectSensors() ([]SensorReading, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), sensorsTimeout)
+	defer cancel()
+
+	temps, err := sensors.TemperaturesWithContext(ctx)
+	// gopsutil reports unreadable files as warnings next to the readings
+	// it did get; keep those.
+	readings := sensorReadings(temps)
+	if len(readings) > 0 {
+		return readings, nil
+	}
+	return nil, err
+}
+
+// sensorReadings turns raw temperatures into readings, dropping implausible
+// values and repeated keys, sorted by label.
+func sensorReadings(temps []sensors.TemperatureStat) []SensorReading {
+	seen := make(map[string]bool, len(temps))
+	var readings []SensorReading
+	for _, t := range temps {
+		label := strings.TrimSpace(t.SensorKey)
+		if label == "" || seen[label] || t.Temperature < minSensorTemp || t.Temperature > maxSensorTemp {
+			continue
+		}
+		seen[label] = true
+
+		var notes []string
+		if t.High > 0 && t.High <= maxSensorTemp {
+			notes = append(notes, fmt.Sprintf("high %.0f°C", t.High))
+		}
+		if t.Critical > 0 && t.Critical <= maxSensorTemp {
+			notes = append(notes, fmt.Sprintf("crit %.0f°C", t.Critical))
+		}
+		readings = append(readings, SensorReading{
+			Label: label,
+			Value: t.Temperature,
+			Unit:  "°C",
+			Note:  strings.Join(notes, " · "),
+		})
+	}
+	sort.Slice(readings, func(i, j int) bool { return readings[i].Label < readings[j].Label })
+	return readings
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	smartCacheTTL = 5 * time.Minute
+	smartTimeout  = 3 * time.Second
+	// smartStandbyStatus is the exit status asked of `-n standby`, distinct
+	// from the default 2 that also means the device could not be opened.
+	smartStandbyStatus = 3
+)
+
+// collectDiskHealth returns the latest drive readings and, every
+// smartCacheTTL, starts a refresh in the background so smartctl never holds
+// up a collection. Health logs change slowly, and without root smartctl
+// fails the same way every time, so failures wait out the TTL as well.
+func (c *Collector) collectDiskHealth(now time.Time) []DiskHealth {
+	c.smartMu.Lock()
+	defer c.smartMu.Unlock()
+	if !c.smartBusy && (c.lastSmartAt.IsZero() || now.Sub(c.lastSmartAt) >= smartCacheTTL) {
+		c.lastSmartAt = now
+		c.smartBusy = true
+		c.smartDone.Add(1)
+		previous := c.lastSmart
+		go func() {
+			defer c.smartDone.Done()
+			health, _ := readDiskHealth(previous)
+			c.smartMu.Lock()
+			defer c.smartMu.Unlock()
+			c.lastSmart, c.smartBusy = health, false
+		}()
+	}
+	return c.lastSmart
+}
+
+// readDiskHealth reads every drive smartctl can find. Drives asleep in
+// standby are not woken; they keep their reading from previous, if any.
+func readDiskHealth(previous []DiskHealth) ([]DiskHealth, error) {
+	if !commandExists("smartctl") {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), smartTimeout)
+	defer cancel()
+	out, err := smartctlJSON(ctx, "--scan", "--json")
+	if err != nil {
+		return nil, err
+	}
+	devices, err := parseSmartctlScan(out)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		health   []DiskHealth
+		problems []error
+	)
+	for _, dev := range devices {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			out, err := smartctlJSON(ctx, "--json", "-a", "-n", fmt.Sprintf("standby,%d", smartStandbyStatus), "-d", dev.Type, dev.Name)
+			var h DiskHealth
+			if err == nil {
+				h, err = parseSmartctl(out)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if errors.Is(err, errSmartStandby) {
+				if i := slices.IndexFunc(previous, func(d DiskHealth) bool { return d.Device == dev.Name }); i >= 0 {
+					health = append(health, previous[i])
+				}
+				return
+			}
+			if err != nil {
+				problems = append(problems, fmt.Errorf("smartctl %s: %w", dev.Name, err))
+				return
+			}
+			health = append(health, h)
+		}()
+	}
+	wg.Wait()
+
+	sort.Slice(health, func(i, j int) bool { return health[i].Device < health[j].Device })
+	return health, errors.Join(problems...)
+}
+
+// errSmartStandby marks a drive smartctl skipped to avoid spinning it up.
+var errSmartStandby = errors.New("drive in standby")
+
+// smartctlJSON runs smartctl and returns its JSON output. smartctl's exit
+// status is a bit mask that is also set for failing drives and logged
+// errors, so output that came with a non-zero exit is still returned; only
+// smartStandbyStatus, which -n standby was told to use, means skipped.
+func smartctlJSON(ctx context.Context, args ...string) ([]byte, error) {
+	out, err := exec.CommandContext(ctx, "smartctl", args...).Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == smartStandbyStatus && slices.Contains(args, "-n") {
+		return nil, errSmartStandby
+	}
+	if err != nil && (!errors.As(err, &exitErr) || len(out) == 0) {
+		return nil, err
+	}
+	return out, nil
+}
+
+type smartDevice struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Protocol string `json:"protocol"`
+}
+
+func parseSmartctlScan(data []byte) ([]smartDevice, error) {
+	var scan struct {
+		Devices []smartDevice `json:"devices"`
+	}
+	if err := json.Unmarshal(data, &scan); err != nil {
+		return nil, err
+	}
+	return scan.Devices, nil
+}
+
+// smartctlReport is the part of `smartctl --json -a` that mole reads.
+type smartctlReport struct {
+	Smartctl struct {
+		Messages []struct {
+			String string `json:"string"`
+		} `json:"messages"`
+	} `json:"smartctl"`
+	Device       smartDevice `json:"device"`
+	ModelName    string      `json:"model_name"`
+	RotationRate *int        `json:"rotation_rate"` // 0 for SSDs, RPM for disks
+
+	SmartStatus *struct {
+		Passed bool `json:"passed"`
+	} `json:"smart_status"`
+	Temperature struct {
+		Current float64 `json:"current"`
+	} `json:"temperature"`
+	PowerOnTime struct {
+		Hours uint64 `json:"hours"`
+	} `json:"power_on_time"`
+	NVMeLog *struct {
+		PercentageUsed float64 `json:"percentage_used"`
+		MediaErrors    uint64  `json:"media_errors"`
+	} `json:"nvme_smart_health_information_log"`
+	ATAAttributes struct {
+		Table []struct {
+			ID    int     `json:"id"`
+			Value float64 `json:"value"`
+			Raw   struct {
+				Value uint64 `json:"value"`
+			} `json:"raw"`
+		} `json:"table"`
+	} `json:"ata_smart_attributes"`
+}
+
+// ATA attributes whose normalised value is the life left in percent, by
+// vendor: Samsung, Crucial/Micron, generic SSD_Life_Left and Intel. 202 and
+// 231 mean other things on hard disks (data address marks, temperature), so
+// they count only for drives reporting no rotation.
+var (
+	ataLifeLeftAttributes = []int{177, 202, 231, 233}
+	ataSSDOnlyAttributes  = []int{202, 231}
+)
+
+// ATA attributes whose raw values count bad sectors: reallocated, pending
+// and offline uncorrectable.
+var ataBadSectorAttributes = []int{5, 197, 198}
+
+// parseSmartctl reads one drive's `smartctl --json -a` report. A report
+// without a SMART status, which is what smartctl prints when it cannot
+// open the device, is an error carrying smartctl's own message.
+func parseSmartctl(data []byte) (DiskHealth, error) {
+	var report smartctlReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return DiskHealth{}, err
+	}
+	if report.SmartStatus == nil {
+		if msgs := report.Smartctl.Messages; len(msgs) > 0 {
+			return DiskHealth{}, errors.New(msgs[0].String)
+		}
+		return DiskHealth{}, errors.New("no SMART data")
+	}
+
+	h := DiskHealth{
+		Device:       report.Device.Name,
+		Model:        report.ModelName,
+		Protocol:     report.Device.Protocol,
+		Passed:       report.SmartStatus.Passed,
+		Temperature:  report.Temperature.Current,
+		PowerOnHours: report.PowerOnTime.Hours,
+	}
+	if log := report.NVMeLog; log != nil {
+		h.WearKnown = true
+		h.PercentUsed = log.PercentageUsed
+		h.MediaErrors = log.MediaErrors
+		return h, nil
+	}
+	ssd := report.RotationRate != nil && *report.RotationRate == 0
+	for _, attr := range report.ATAAttributes.Table {
+		switch {
+		case slices.Contains(ataSSDOnlyAttributes, attr.ID) && !ssd:
+			continue
+		case slices.Contains(ataLifeLeftAttributes, attr.ID) && !h.WearKnown:
+			h.WearKnown = true
+			h.PercentUsed = min(max(100-attr.Value, 0), 100)
+		case slices.Contains(ataBadSectorAttributes, attr.ID):
+			h.MediaErrors += attr.Raw.Value
+		}
+	}
+	return h, nil
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestParseSmartctl(t *testing.T) {
+	nvme, err := parseSmartctl(readFixture(t, "smartctl_nvme.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := DiskHealth{Device: "/dev/nvme0", Model: "Samsung SSD 980 PRO 1TB", Protocol: "NVMe", Passed: true,
+		WearKnown: true, PercentUsed: 7, Temperature: 41, PowerOnHours: 6342}
+	if nvme != want {
+		t.Errorf("nvme = %+v, want %+v", nvme, want)
+	}
+
+	ata, err := parseSmartctl(readFixture(t, "smartctl_ata.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Wear_Leveling_Count 18 means 82% of rated endurance is gone; 6
+	// reallocated and 2 pending sectors are media errors.
+	if !ata.Passed || !ata.WearKnown || ata.PercentUsed != 82 || ata.MediaErrors != 8 || ata.Temperature != 34 || ata.PowerOnHours != 58001 {
+		t.Errorf("unexpected ata health %+v", ata)
+	}
+
+	if _, err := parseSmartctl(readFixture(t, "smartctl_denied.json")); err == nil || !strings.Contains(err.Error(), "Permission denied") {
+		t.Errorf("unreadable device should report smartctl's message, got %v", err)
+	}
+	if _, err := parseSmartctl([]byte("smartctl: unrecognized option '--json'")); err == nil {
+		t.Error("non-JSON output should be an error")
+	}
+
+	devices, err := parseSmartctlScan(readFixture(t, "smartctl_scan.json"))
+	if err != nil || len(devices) != 2 || devices[1] != (smartDevice{Name: "/dev/nvme0", Type: "nvme", Protocol: "NVMe"}) {
+		t.Errorf("unexpected scan %+v %v", devices, err)
+	}
+}
+
+func TestCollectDiskHealthOverFakeSmartctl(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a shell script standing in for smartctl")
+	}
+	fixtures, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	calls := filepath.Join(dir, "calls")
+	// The SATA drive exits 64 (error log has entries) with complete JSON.
+	script := "#!/bin/sh\necho \"$@\" >> " + calls + "\n" +
+		"case \"$*\" in\n" +
+		"*--scan*) cat " + filepath.Join(fixtures, "smartctl_scan.json") + " ;;\n" +
+		"*/dev/nvme0*) cat " + filepath.Join(fixtures, "smartctl_nvme.json") + " ;;\n" +
+		"*/dev/sda*) cat " + filepath.Join(fixtures, "smartctl_ata.json") + "; exit 64 ;;\n" +
+		"esac\n"
+	if err := os.WriteFile(filepath.Join(dir, "smartctl"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	c := &Collector{}
+	start := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	if drives := c.collectDiskHealth(start); drives != nil {
+		t.Fatalf("first pass should not wait for smartctl, got %+v", drives)
+	}
+	c.smartDone.Wait()
+	drives := c.collectDiskHealth(start.Add(time.Minute))
+	if len(drives) != 2 || drives[0].Device != "/dev/nvme0" || drives[1].Device != "/dev/sda" || drives[1].MediaErrors != 8 {
+		t.Fatalf("unexpected drives %+v", drives)
+	}
+
+	log, _ := os.ReadFile(calls)
+	if got := strings.Count(string(log), "\n"); got != 3 {
+		t.Errorf("expected one scan and two device reads, smartctl ran %d times:\n%s", got, log)
+	}
+	if !strings.Contains(string(log), "--json -a -n standby,3 -d sat /dev/sda") {
+		t.Errorf("device type and standby check should be passed through:\n%s", log)
+	}
+}
+
+func TestCollectDiskHealthSkipsDrivesInStandby(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a shell script standing in for smartctl")
+	}
+	fixtures, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	asleep := filepath.Join(dir, "asleep")
+	// The SATA drive answers once, then sleeps: smartctl exits with the
+	// status -n standby asked for and prints nothing.
+	script := "#!/bin/sh\n" +
+		"case \"$*\" in\n" +
+		"*--scan*) cat " + filepath.Join(fixtures, "smartctl_scan.json") + " ;;\n" +
+		"*/dev/nvme0*) cat " + filepath.Join(fixtures, "smartctl_nvme.json") + " ;;\n" +
+		"*/dev/sda*) [ -e " + asleep + " ] && exit 3; cat " + filepath.Join(fixtures, "smartctl_ata.json") + " ;;\n" +
+		"esac\n"
+	if err := os.WriteFile(filepath.Join(dir, "smartctl"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	if err := os.WriteFile(asleep, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	drives, err := readDiskHealth(nil)
+	if err != nil || len(drives) != 1 || drives[0].Device != "/dev/nvme0" {
+		t.Fatalf("sleeping drive without a reading should be left out quietly, got %+v %v", drives, err)
+	}
+
+	_ = os.Remove(asleep)
+	awake, err := readDiskHealth(nil)
+	if err != nil || len(awake) != 2 {
+		t.Fatalf("unexpected drives %+v %v", awake, err)
+	}
+	if err := os.WriteFile(asleep, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	drives, err = readDiskHealth(awake)
+	if err != nil || len(drives) != 2 || drives[1] != awake[1] {
+		t.Errorf("sleeping drive should keep its last reading, got %+v %v", drives, err)
+	}
+}
+
+func TestParseSmartctlIgnoresSSDAttributesOnDisks(t *testing.T) {
+	// On hard disks 231 is commonly a temperature, not life left.
+	hdd := []byte(`{"device":{"name":"/dev/sdb","protocol":"ATA"},"rotation_rate":7200,
+		"smart_status":{"passed":true},
+		"ata_smart_attributes":{"table":[{"id":231,"value":35,"raw":{"value":35}}]}}`)
+	h, err := parseSmartctl(hdd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.WearKnown {
+		t.Errorf("attribute 231 on a spinning disk should not count as wear: %+v", h)
+	}
+
+	ssd := []byte(`{"device":{"name":"/dev/sdb","protocol":"ATA"},"rotation_rate":0,
+		"smart_status":{"passed":true},
+		"ata_smart_attributes":{"table":[{"id":231,"value":35,"raw":{"value":35}}]}}`)
+	if h, err := parseSmartctl(ssd); err != nil || !h.WearKnown || h.PercentUsed != 65 {
+		t.Errorf("attribute 231 on an SSD is life left, got %+v %v", h, err)
+	}
+}
@@ -0,0 +1,356 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Linux thermal, fan, battery and power readings straight from sysfs. Every
+// reader takes the sysfs root so tests can point it at a fake tree.
+
+// sysfsRoot returns the sysfs mount, honouring HOST_SYS like gopsutil so a
+// containerised collector can read the host's /sys.
+func sysfsRoot() string {
+	if root := os.Getenv("HOST_SYS"); root != "" {
+		return root
+	}
+	return "/sys"
+}
+
+// procfsRoot returns the procfs mount, honouring HOST_PROC the same way.
+func procfsRoot() string {
+	if root := os.Getenv("HOST_PROC"); root != "" {
+		return root
+	}
+	return "/proc"
+}
+
+func readSysfsString(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+func readSysfsInt(path string) (int64, bool) {
+	v, err := strconv.ParseInt(readSysfsString(path), 10, 64)
+	return v, err == nil
+}
+
+// Chips whose temperatures describe the CPU and GPU, the labels that name
+// the package-level reading on them, and CPU thermal zones, best first.
+var (
+	cpuHwmonChips     = []string{"coretemp", "k10temp", "zenpower", "cpu_thermal", "cpu-thermal"}
+	gpuHwmonChips     = []string{"amdgpu", "radeon", "nouveau"}
+	packageTempLabels = []string{"package id 0", "tdie", "tctl", "edge"}
+	cpuThermalZones   = []string{"x86_pkg_temp", "cpu-thermal", "cpu_thermal", "soc_thermal", "acpitz"}
+)
+
+// readLinuxBatteries reads every system battery under power_supply. Device
+// scoped supplies, such as a wireless mouse, are skipped.
+func readLinuxBatteries(root string) []BatteryStatus {
+	dirs, _ := filepath.Glob(filepath.Join(root, "class", "power_supply", "*"))
+	var batts []BatteryStatus
+	for _, dir := range dirs {
+		if readSysfsString(filepath.Join(dir, "type")) != "Battery" || readSysfsString(filepath.Join(dir, "scope")) == "Device" {
+			continue
+		}
+		supply := readPowerSupply(dir)
+		percent, ok := readSysfsInt(filepath.Join(dir, "capacity"))
+		if !ok && supply.full <= 0 {
+			continue
+		}
+		b := BatteryStatus{
+			Percent: float64(percent),
+			Status:  linuxBatteryStatus(readSysfsString(filepath.Join(dir, "status"))),
+			Health:  readSysfsString(filepath.Join(dir, "health")),
+		}
+		if !ok {
+			b.Percent = math.Min(supply.now/supply.full*100, 100)
+		}
+		if supply.full > 0 && supply.design > 0 {
+			b.Capacity = min(int(math.Round(supply.full/supply.design*100)), 100)
+		}
+		if cycles, ok := readSysfsInt(filepath.Join(dir, "cycle_count")); ok && cycles > 0 {
+			b.CycleCount = int(cycles)
+		}
+		b.TimeLeft = batteryTimeLeft(supply, b.Status)
+		batts = append(batts, b)
+	}
+	return batts
+}
+
+// powerSupply holds one battery's charge in either energy (µWh, µW) or
+// charge (µAh, µA) units; the ratios between them are what matter.
+type powerSupply struct {
+	now, full, design, rate float64
+}
+
+func readPowerSupply(dir string) powerSupply {
+	read := func(name string) float64 {
+		v, _ := readSysfsInt(filepath.Join(dir, name))
+		return float64(v)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "energy_full")); err == nil {
+		return powerSupply{
+			now:    read("energy_now"),
+			full:   read("energy_full"),
+			design: read("energy_full_design"),
+			rate:   math.Abs(read("power_now")),
+		}
+	}
+	return powerSupply{
+		now:    read("charge_now"),
+		full:   read("charge_full"),
+		design: read("charge_full_design"),
+		rate:   math.Abs(read("current_now")),
+	}
+}
+
+// linuxBatteryStatus maps the kernel status onto the words pmset uses, so
+// the battery card treats both platforms alike.
+func linuxBatteryStatus(raw string) string {
+	switch strings.ToLower(raw) {
+	case "charging":
+		return "charging"
+	case "discharging":
+		return "discharging"
+	case "full":
+		return "charged"
+	case "not charging":
+		return "AC attached"
+	case "":
+		return "Unknown"
+	}
+	return raw
+}
+
+// batteryTimeLeft estimates time to empty or full in pmset's h:mm format.
+func batteryTimeLeft(s powerSupply, status string) string {
+	if s.rate <= 0 {
+		return ""
+	}
+	var hours float64
+	switch status {
+	case "discharging":
+		hours = s.now / s.rate
+	case "charging":
+		hours = (s.full - s.now) / s.rate
+	default:
+		return ""
+	}
+	if hours <= 0 || hours > 99 {
+		return ""
+	}
+	minutes := int(math.Round(hours * 60))
+	return fmt.Sprintf("%d:%02d", minutes/60, minutes%60)
+}
+
+// readLinuxBatteryPower returns battery power in watts, positive when
+// discharging and negative when charging, matching ioreg's BatteryPower.
+func readLinuxBatteryPower(root string) float64 {
+	dirs, _ := filepath.Glob(filepath.Join(root, "class", "power_supply", "*"))
+	var total float64
+	for _, dir := range dirs {
+		if readSysfsString(filepath.Join(dir, "type")) != "Battery" || readSysfsString(filepath.Join(dir, "scope")) == "Device" {
+			continue
+		}
+		var watts float64
+		if power, ok := readSysfsInt(filepath.Join(dir, "power_now")); ok {
+			watts = math.Abs(float64(power)) / 1e6
+		} else {
+			current, okC := readSysfsInt(filepath.Join(dir, "current_now"))
+			voltage, okV := readSysfsInt(filepath.Join(dir, "voltage_now"))
+			if !okC || !okV {
+				continue
+			}
+			watts = math.Abs(float64(current)) * float64(voltage) / 1e12
+		}
+		switch linuxBatteryStatus(readSysfsString(filepath.Join(dir, "status"))) {
+		case "discharging":
+			total += watts
+		case "charging":
+			total -= watts
+		}
+	}
+	return total
+}
+
+// readLinuxAdapterPower returns the rated power of the online AC or USB-PD
+// supply, the closest match to the adapter wattage macOS reports.
+func readLinuxAdapterPower(root string) float64 {
+	dirs, _ := filepath.Glob(filepath.Join(root, "class", "power_supply", "*"))
+	var best float64
+	for _, dir := range dirs {
+		switch readSysfsString(filepath.Join(dir, "type")) {
+		case "Mains", "USB":
+		default:
+			continue
+		}
+		if online, _ := readSysfsInt(filepath.Join(dir, "online")); online != 1 {
+			continue
+		}
+		current, okC := readSysfsInt(filepath.Join(dir, "current_max"))
+		voltage, okV := readSysfsInt(filepath.Join(dir, "voltage_max"))
+		if okC && okV {
+			best = math.Max(best, float64(current)*float64(voltage)/1e12)
+		}
+	}
+	return best
+}
+
+// readHwmonThermal fills CPU and GPU temperatures and fan speeds from
+// hwmon. The CPU temperature is the package reading when the chip has one,
+// otherwise its hottest core. FanSpeed is the fastest fan.
+func readHwmonThermal(root string) ThermalStatus {
+	var thermal ThermalStatus
+	chips, _ := filepath.Glob(filepath.Join(root, "class", "hwmon", "hwmon*"))
+	for _, chip := range chips {
+		name := readSysfsString(filepath.Join(chip, "name"))
+
+		fans, _ := filepath.Glob(filepath.Join(chip, "fan*_input"))
+		for _, fan := range fans {
+			if rpm, ok := readSysfsInt(fan); ok {
+				thermal.FanCount++
+				thermal.FanSpeed = max(thermal.FanSpeed, int(rpm))
+			}
+		}
+
+		isCPU, isGPU := slices.Contains(cpuHwmonChips, name), slices.Contains(gpuHwmonChips, name)
+		if !isCPU && !isGPU {
+			continue
+		}
+		var hottest, pkg float64
+		pkgRank := len(packageTempLabels)
+		inputs, _ := filepath.Glob(filepath.Join(chip, "temp*_input"))
+		for _, input := range inputs {
+			milli, ok := readSysfsInt(input)
+			if !ok || milli <= 0 {
+				continue
+			}
+			temp := float64(milli) / 1000
+			hottest = math.Max(hottest, temp)
+			label := strings.ToLower(readSysfsString(strings.TrimSuffix(input, "_input") + "_label"))
+			if rank := slices.Index(packageTempLabels, label); rank >= 0 && rank < pkgRank {
+				pkg, pkgRank = temp, rank
+			}
+		}
+		if pkg == 0 {
+			pkg = hottest
+		}
+		switch {
+		case isCPU && thermal.CPUTemp == 0:
+			thermal.CPUTemp = pkg
+		case isGPU && thermal.GPUTemp == 0:
+			thermal.GPUTemp = pkg
+		}
+	}
+	return thermal
+}
+
+// readThermalZoneCPUTemp falls back to the thermal zones when no hwmon chip
+// is a known CPU sensor, as on many ARM boards and older laptops.
+func readThermalZoneCPUTemp(root string) float64 {
+	zones, _ := filepath.Glob(filepath.Join(root, "class", "thermal", "thermal_zone*"))
+	best, bestRank := 0.0, len(cpuThermalZones)
+	for _, zone := range zones {
+		rank := slices.Index(cpuThermalZones, readSysfsString(filepath.Join(zone, "type")))
+		if rank < 0 || rank >= bestRank {
+			continue
+		}
+		if milli, ok := readSysfsInt(filepath.Join(zone, "temp")); ok && milli > 0 {
+			best, bestRank = float64(milli)/1000, rank
+		}
+	}
+	return best
+}
+
+// raplSample is one reading of the RAPL energy counters, keyed by zone.
+type raplSample struct {
+	at     time.Time
+	energy map[string]uint64
+	wrap   map[string]uint64
+}
+
+// readRAPL reads the top-level powercap zones. The platform (psys) zone
+// covers the whole SoC when present; otherwise the packages are summed.
+// energy_uj is root-only on most kernels, in which case nothing is read.
+func readRAPL(root string, now time.Time) raplSample {
+	sample := raplSample{at: now, energy: map[string]uint64{}, wrap: map[string]uint64{}}
+	zones, _ := filepath.Glob(filepath.Join(root, "class", "powercap", "*-rapl:*"))
+	var psys, packages []string
+	for _, zone := range zones {
+		// Subzones (intel-rapl:0:0) are already counted in their package.
+		if strings.Count(filepath.Base(zone), ":") != 1 {
+			continue
+		}
+		switch name := readSysfsString(filepath.Join(zone, "name")); {
+		case name == "psys":
+			psys = append(psys, zone)
+		case strings.HasPrefix(name, "package"):
+			packages = append(packages, zone)
+		}
+	}
+	if len(psys) > 0 {
+		packages = psys
+	}
+	for _, zone := range packages {
+		energy, err := strconv.ParseUint(readSysfsString(filepath.Join(zone, "energy_uj")), 10, 64)
+		if err != nil {
+			continue
+		}
+		key := filepath.Base(zone)
+		sample.energy[key] = energy
+		if wrap, err := strconv.ParseUint(readSysfsString(filepath.Join(zone, "max_energy_range_uj")), 10, 64); err == nil {
+			sample.wrap[key] = wrap
+		}
+	}
+	return sample
+}
+
+// raplPower returns the average power in watts between two samples,
+// allowing for counters that wrapped in between.
+func raplPower(prev, cur raplSample) float64 {
+	elapsed := cur.at.Sub(prev.at).Seconds()
+	if elapsed <= 0 || len(prev.energy) == 0 {
+		return 0
+	}
+	var joules float64
+	for zone, now := range cur.energy {
+		before, ok := prev.energy[zone]
+		if !ok {
+			continue
+		}
+		delta := now - before
+		if now < before {
+			if cur.wrap[zone] < before {
+				continue
+			}
+			delta = cur.wrap[zone] - before + now
+		}
+		joules += float64(delta) / 1e6
+	}
+	return joules / elapsed
+}
+
+// collectLinuxThermal reads temperatures, fans and power from sysfs.
+func (c *Collector) collectLinuxThermal(root string, now time.Time) ThermalStatus {
+	thermal := readHwmonThermal(root)
+	if thermal.CPUTemp == 0 {
+		thermal.CPUTemp = readThermalZoneCPUTemp(root)
+	}
+	thermal.BatteryPower = readLinuxBatteryPower(root)
+	thermal.AdapterPower = readLinuxAdapterPower(root)
+
+	sample := readRAPL(root, now)
+	thermal.SystemPower = raplPower(c.prevRAPL, sample)
+	c.prevRAPL = sample
+	return thermal
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeSysfs builds a fake sysfs tree under root from path → content.
+func writeSysfs(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestReadLinuxBatteries(t *testing.T) {
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		// Energy units, discharging at 10W with 25Wh left.
+		"class/power_supply/BAT0/type":               "Battery",
+		"class/power_supply/BAT0/status":             "Discharging",
+		"class/power_supply/BAT0/capacity":           "50",
+		"class/power_supply/BAT0/energy_now":         "25000000",
+		"class/power_supply/BAT0/energy_full":        "50000000",
+		"class/power_supply/BAT0/energy_full_design": "57000000",
+		"class/power_supply/BAT0/power_now":          "10000000",
+		"class/power_supply/BAT0/cycle_count":        "312",
+		// Charge units and no capacity file.
+		"class/power_supply/BAT1/type":               "Battery",
+		"class/power_supply/BAT1/status":             "Full",
+		"class/power_supply/BAT1/charge_now":         "3000000",
+		"class/power_supply/BAT1/charge_full":        "4000000",
+		"class/power_supply/BAT1/charge_full_design": "4000000",
+		// A wireless mouse is not a system battery.
+		"class/power_supply/hidpp_battery_0/type":     "Battery",
+		"class/power_supply/hidpp_battery_0/scope":    "Device",
+		"class/power_supply/hidpp_battery_0/capacity": "90",
+		"class/power_supply/AC/type":                  "Mains",
+		"class/power_supply/AC/online":                "0",
+	})
+
+	batts := readLinuxBatteries(root)
+	if len(batts) != 2 {
+		t.Fatalf("expected 2 batteries, got %+v", batts)
+	}
+	b := batts[0]
+	if b.Percent != 50 || b.Status != "discharging" || b.Capacity != 88 || b.CycleCount != 312 || b.TimeLeft != "2:30" {
+		t.Errorf("unexpected BAT0 %+v", b)
+	}
+	b = batts[1]
+	if b.Percent != 75 || b.Status != "charged" || b.Capacity != 100 || b.CycleCount != 0 || b.TimeLeft != "" {
+		t.Errorf("unexpected BAT1 %+v", b)
+	}
+}
+
+func TestReadLinuxPower(t *testing.T) {
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		"class/power_supply/BAT0/type":                               "Battery",
+		"class/power_supply/BAT0/status":                             "Charging",
+		"class/power_supply/BAT0/current_now":                        "2000000",
+		"class/power_supply/BAT0/voltage_now":                        "12000000",
+		"class/power_supply/ucsi-source-psy-USBC000:001/type":        "USB",
+		"class/power_supply/ucsi-source-psy-USBC000:001/online":      "1",
+		"class/power_supply/ucsi-source-psy-USBC000:001/current_max": "3250000",
+		"class/power_supply/ucsi-source-psy-USBC000:001/voltage_max": "20000000",
+	})
+	if got := readLinuxBatteryPower(root); got != -24 {
+		t.Errorf("battery power = %v, want -24 while charging", got)
+	}
+	if got := readLinuxAdapterPower(root); got != 65 {
+		t.Errorf("adapter power = %v, want 65", got)
+	}
+}
+
+func TestReadHwmonThermal(t *testing.T) {
+	root := t.TempDir()
+	writeSysfs(t, root, map[string]string{
+		"class/hwmon/hwmon0/name":        "acpitz",
+		"class/hwmon/hwmon0/temp1_input": "99000",
+		"class/hwmon/hwmon1/name":        "k10temp",
+		"class/hwmon/hwmon1/temp1_input": "71000",
+		"class/hwmon/hwmon1/temp1_label": "Tctl",
+		"class/hwmon/hwmon1/temp2_input": "61500",
+		"class/hwmon/hwmon1/temp2_label": "Tdie",
+		"class/hwmon/hwmon1/temp3_input": "80000",
+		"class/hwmon/hwmon1/temp3_label": "Tccd1",
+		"class/hwmon/hwmon2/name":        "amdgpu",
+		"class/hwmon/hwmon2/temp1_input": "48000",
+		"class/hwmon/hwmon2/temp1_label": "edge",
+		"class/hwmon/hwmon2/temp2_input": "55000",
+		"class/hwmon/hwmon2/temp2_label": "junction",
+		"class/hwmon/hwmon3/name":        "thinkpad",
+		"class/hwmon/hwmon3/fan1_input":  "2400",
+		"class/hwmon/hwmon3/fan2_input":  "0",
+	})
+	thermal := readHwmonThermal(root)
+	if thermal.CPUTemp != 61.5 || thermal.GPUTemp != 48 {
+		t.Errorf("temps = %v / %v, want Tdie 61.5 and edge 48", thermal.CPUTemp, thermal.GPUTemp)
+	}
+	if thermal.FanCount != 2 || thermal.FanSpeed != 2400 {
+		t.Errorf("fans = %d @ %d RPM, want 2 @ 2400", thermal.FanCount, thermal.FanSpeed)
+	}
+
+	// Without a known CPU chip, fall back to the best thermal zone.
+	zones := t.TempDir()
+	writeSysfs(t, zones, map[string]string{
+		"class/thermal/thermal_zone0/type": "acpitz",
+		"class/thermal/thermal_zone0/temp": "40000",
+		"class/thermal/thermal_zone1/type": "x86_pkg_temp",
+		"class/thermal/thermal_zone1/temp": "52000",
+		"class/thermal/thermal_zone2/type": "iwlwifi_1",
+		"class/thermal/thermal_zone2/temp": "90000",
+	})
+	if got := readThermalZoneCPUTemp(zones); got != 52 {
+		t.Errorf("thermal zone temp = %v, want 52", got)
+	}
+}
+
+func TestRAPLPower(t *testing.T) {
+	root := t.TempDir()
+	zone := "class/powercap/intel-rapl:0/"
+	writeSysfs(t, root, map[string]string{
+		zone + "name":                "package-0",
+		zone + "energy_uj":           "262000000",
+		zone + "max_energy_range_uj": "262143328850",
+		// Subzones are part of the package and must not be added again.
+		"class/powercap/intel-rapl:0:0/name":      "core",
+		"class/powercap/intel-rapl:0:0/energy_uj": "1000",
+	})
+	c := &Collector{}
+	start := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	if got := c.collectLinuxThermal(root, start).SystemPower; got != 0 {
+		t.Errorf("first sample should report no power, got %v", got)
+	}
+
+	writeSysfs(t, root, map[string]string{zone + "energy_uj": "292000000"})
+	if got := c.collectLinuxThermal(root, start.Add(2*time.Second)).SystemPower; got != 15 {
+		t.Errorf("system power = %v, want 15W", got)
+	}
+
+	// The counter wrapped: 143328850µJ to the top plus 30000000µJ after it.
+	prev := raplSample{at: start, energy: map[string]uint64{"intel-rapl:0": 262000000000}}
+	cur := raplSample{
+		at:     start.Add(time.Second),
+		energy: map[string]uint64{"intel-rapl:0": 30000000},
+		wrap:   map[string]uint64{"intel-rapl:0": 262143328850},
+	}
+	if got := raplPower(prev, cur); math.Abs(got-173.32885) > 1e-6 {
+		t.Errorf("wrapped power = %v, want 173.33", got)
+	}
+}
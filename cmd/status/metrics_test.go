@@ -1,8 +1,13 @@
 package main
 
 import (
+	"os"
+	"path/filepath"
+	"runtime"
 	"slices"
 	"testing"
+
+	"github.com/shirou/gopsutil/v4/sensors"
 )
 
 func TestNewRingBuffer(t *testing.T) {
@@ -179,3 +184,82 @@ func TestRingBuffer_NegativeAndZeroValues(t *testing.T) {
 		t.Errorf("Slice() with negative/zero values = %v, want %v", got, want)
 	}
 }
+
+func TestCollectorUpdateHistory(t *testing.T) {
+	c := NewCollector()
+	c.updateHistory(CPUStatus{Usage: 10, PerCore: []float64{5, 15}}, MemoryStatus{UsedPercent: 40, SwapUsed: 1, SwapTotal: 4}, DiskIOStatus{ReadRate: 1}, []GPUStatus{{Usage: -1}})
+	h := c.updateHistory(CPUStatus{Usage: 20, PerCore: []float64{25, 35}}, MemoryStatus{UsedPercent: 50}, DiskIOStatus{WriteRate: 3}, []GPUStatus{{Usage: 70}})
+
+	if len(h.CPU) != 2 || h.CPU[0] != 10 || h.CPU[1] != 20 {
+		t.Errorf("CPU history = %v", h.CPU)
+	}
+	if len(h.PerCore) != 2 || h.PerCore[1][0] != 15 || h.PerCore[1][1] != 35 {
+		t.Errorf("per-core history = %v", h.PerCore)
+	}
+	if h.Swap[0] != 25 || h.Swap[1] != 0 {
+		t.Errorf("swap history = %v", h.Swap)
+	}
+	if h.DiskRead[0] != 1 || h.DiskWrite[1] != 3 {
+		t.Errorf("disk history = %v / %v", h.DiskRead, h.DiskWrite)
+	}
+	// Unknown GPU usage (-1) is not recorded.
+	if len(h.GPU) != 1 || h.GPU[0] != 70 {
+		t.Errorf("GPU history = %v", h.GPU)
+	}
+
+	// A core count change restarts per-core history.
+	h = c.updateHistory(CPUStatus{Usage: 20, PerCore: []float64{1, 2, 3}}, MemoryStatus{}, DiskIOStatus{}, nil)
+	if len(h.PerCore) != 3 || len(h.PerCore[0]) != 1 {
+		t.Errorf("per-core history after resize = %v", h.PerCore)
+	}
+}
+
+func TestSensorReadings(t *testing.T) {
+	readings := sensorReadings([]sensors.TemperatureStat{
+		{SensorKey: "nvme_composite", Temperature: 38.9, High: 84.8, Critical: 89.8},
+		{SensorKey: "acpitz", Temperature: 0},
+		{SensorKey: "coretemp_core_0", Temperature: 55},
+		{SensorKey: "coretemp_core_0", Temperature: 56},
+		{SensorKey: "bogus", Temperature: 255},
+	})
+	if len(readings) != 2 {
+		t.Fatalf("expected 2 readings, got %+v", readings)
+	}
+	if readings[0].Label != "coretemp_core_0" || readings[0].Value != 55 || readings[0].Note != "" {
+		t.Errorf("unexpected first reading %+v", readings[0])
+	}
+	if readings[1].Note != "high 85°C · crit 90°C" || readings[1].Unit != "°C" {
+		t.Errorf("unexpected limits note %+v", readings[1])
+	}
+}
+
+func TestCollectSensorsHwmon(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("hwmon is Linux only")
+	}
+	sys := t.TempDir()
+	hwmon := filepath.Join(sys, "class", "hwmon", "hwmon0")
+	if err := os.MkdirAll(hwmon, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, value := range map[string]string{
+		"name":        "coretemp\n",
+		"temp1_input": "61000\n",
+		"temp1_label": "Package id 0\n",
+		"temp1_max":   "100000\n",
+		"temp2_input": "0\n",
+	} {
+		if err := os.WriteFile(filepath.Join(hwmon, name), []byte(value), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("HOST_SYS", sys)
+
+	readings, err := collectSensors()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(readings) != 1 || readings[0].Label != "coretemp_package_id_0" || readings[0].Value != 61 || readings[0].Note != "high 100°C" {
+		t.Fatalf("unexpected readings %+v", readings)
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// defaultNoiseInterfaces are interface name prefixes left out of network
+// rates: loopback, tunnels, bridges and Apple's peer-to-peer links.
+var defaultNoiseInterfaces = []string{"lo", "awdl", "utun", "llw", "bridge", "gif", "stf", "xhc", "anpi", "ap"}
+
+// networkConfig controls which interfaces the network card counts.
+type networkConfig struct {
+	noise []string // Lower-case name prefixes
+}
+
+func defaultNetworkConfig() networkConfig {
+	return networkConfig{noise: slices.Clone(defaultNoiseInterfaces)}
+}
+
+// getNetworkConfigPath returns the path to the network config file.
+func getNetworkConfigPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "mole", "status_network")
+}
+
+// loadNetworkConfig reads the network config file:
+//
+//	# Container hosts: hide veth pairs and the docker bridge too.
+//	noise+=veth,docker
+//	# Count the VPN tunnel.
+//	noise-=utun
+//
+// noise= replaces the default prefix list, noise+= adds to it and noise-=
+// removes from it. A missing file yields the defaults. Bad lines are
+// skipped and reported together in the error.
+func loadNetworkConfig(path string) (networkConfig, error) {
+	cfg := defaultNetworkConfig()
+	if path == "" {
+		return cfg, nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return cfg, err
+	}
+	defer file.Close() //nolint:errcheck
+
+	var problems []string
+	scanner := bufio.NewScanner(file)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			problems = append(problems, fmt.Sprintf("line %d: expected key=value", lineNo))
+			continue
+		}
+		var prefixes []string
+		for _, prefix := range strings.Split(value, ",") {
+			if prefix = strings.ToLower(strings.TrimSpace(prefix)); prefix != "" {
+				prefixes = append(prefixes, prefix)
+			}
+		}
+		switch strings.TrimSpace(key) {
+		case "noise":
+			cfg.noise = prefixes
+		case "noise+":
+			for _, prefix := range prefixes {
+				if !slices.Contains(cfg.noise, prefix) {
+					cfg.noise = append(cfg.noise, prefix)
+				}
+			}
+		case "noise-":
+			cfg.noise = slices.DeleteFunc(cfg.noise, func(p string) bool { return slices.Contains(prefixes, p) })
+		default:
+			problems = append(problems, fmt.Sprintf("line %d: unknown key %q", lineNo, strings.TrimSpace(key)))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return cfg, err
+	}
+	if len(problems) > 0 {
+		return cfg, fmt.Errorf("%s: %s", filepath.Base(path), strings.Join(problems, "; "))
+	}
+	return cfg, nil
+}
+
+// isNoise reports whether an interface is left out of network rates.
+func (cfg networkConfig) isNoise(name string) bool {
+	lower := strings.ToLower(name)
+	for _, prefix := range cfg.noise {
+		if strings.HasPrefix(lower, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"compress/gzip"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Recording limits. A segment holds up to recordSegmentSpan of snapshots;
+// whole segments are dropped once they pass recordRetention or the
+// directory grows past recordMaxBytes.
+const (
+	recordSegmentSpan = time.Hour
+	recordRetention   = 7 * 24 * time.Hour
+	recordMaxBytes    = 512 << 20
+	recordSegmentExt  = ".seg"
+	// Segment names sort in time order.
+	recordSegmentLayout = "20060102T150405"
+	// Long pauses in a recording, e.g. a sleeping laptop, replay this fast.
+	maxReplayGap = 5 * time.Second
+)
+
+// getRecordDir returns the directory holding recorded segments.
+func getRecordDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".cache", "mole", "status"), nil
+}
+
+// metricsRecorder appends snapshots to gzip-compressed gob segments. Each
+// write is flushed so a crash loses at most the snapshot in flight.
+// Recordings are private to the user: command lines can carry secrets.
+type metricsRecorder struct {
+	dir       string
+	file      *os.File
+	gz        *gzip.Writer
+	enc       *gob.Encoder
+	openedAt  time.Time
+	retention time.Duration
+	maxBytes  int64
+}
+
+func newMetricsRecorder(dir string) (*metricsRecorder, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	// Directories made by earlier versions were world-readable.
+	if err := os.Chmod(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &metricsRecorder{dir: dir, retention: recordRetention, maxBytes: recordMaxBytes}, nil
+}
+
+// Record writes one snapshot, starting a new segment when the current one
+// is full. See recordable for what is left out.
+func (r *metricsRecorder) Record(m MetricsSnapshot) error {
+	if r.enc == nil || m.CollectedAt.Sub(r.openedAt) >= recordSegmentSpan {
+		if err := r.rotate(m.CollectedAt); err != nil {
+			return err
+		}
+	}
+	m = recordable(m)
+	if err := r.enc.Encode(&m); err != nil {
+		return fmt.Errorf("record snapshot: %w", err)
+	}
+	return r.gz.Flush()
+}
+
+// recordable trims a snapshot to what is worth keeping for a week. The full
+// process list and connections are dropped, top processes keep their name
+// but not their arguments, and the trend buffers are left for replay to
+// rebuild, since each snapshot would otherwise repeat the last few minutes.
+func recordable(m MetricsSnapshot) MetricsSnapshot {
+	m.Processes = nil
+	m.Connections = nil
+	m.History = MetricHistory{}
+	m.NetworkHistory = NetworkHistory{}
+	top := make([]ProcessInfo, len(m.TopProcesses))
+	for i, p := range m.TopProcesses {
+		p.Command = p.Name
+		top[i] = p
+	}
+	m.TopProcesses = top
+	return m
+}
+
+func (r *metricsRecorder) rotate(now time.Time) error {
+	if err := r.Close(); err != nil {
+		return err
+	}
+	name := now.UTC().Format(recordSegmentLayout) + recordSegmentExt
+	file, err := os.OpenFile(filepath.Join(r.dir, name), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("start recording segment: %w", err)
+	}
+	r.file = file
+	r.gz = gzip.NewWriter(file)
+	r.enc = gob.NewEncoder(r.gz)
+	r.openedAt = now
+	return r.prune(now, name)
+}
+
+// prune removes segments past the retention window, then the oldest ones
+// until the directory fits maxBytes. The active segment is kept.
+func (r *metricsRecorder) prune(now time.Time, active string) error {
+	segments, err := listSegments(r.dir)
+	if err != nil {
+		return err
+	}
+	var total int64
+	sizes := make([]int64, len(segments))
+	for i, path := range segments {
+		if info, err := os.Stat(path); err == nil {
+			sizes[i] = info.Size()
+			total += sizes[i]
+		}
+	}
+	for i, path := range segments {
+		if filepath.Base(path) == active {
+			break
+		}
+		started, err := segmentStart(path)
+		expired := err == nil && now.Sub(started) > r.retention
+		if !expired && total <= r.maxBytes {
+			break
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		total -= sizes[i]
+	}
+	return nil
+}
+
+// Close finishes the current segment.
+func (r *metricsRecorder) Close() error {
+	if r.file == nil {
+		return nil
+	}
+	gzErr := r.gz.Close()
+	fileErr := r.file.Close()
+	r.file, r.gz, r.enc = nil, nil, nil
+	return errors.Join(gzErr, fileErr)
+}
+
+// listSegments returns the segment files in dir, oldest first.
+func listSegments(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var segments []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), recordSegmentExt) {
+			segments = append(segments, filepath.Join(dir, e.Name()))
+		}
+	}
+	slices.Sort(segments)
+	return segments, nil
+}
+
+func segmentStart(path string) (time.Time, error) {
+	name := strings.TrimSuffix(filepath.Base(path), recordSegmentExt)
+	return time.Parse(recordSegmentLayout, name)
+}
+
+// metricsReplay reads snapshots back from one segment or a directory of
+// them, in recorded order, rebuilding the trend history as it goes.
+type metricsReplay struct {
+	segments []string
+	next     int // Index of the next segment to open
+	file     *os.File
+	dec      *gob.Decoder
+	pending  *MetricsSnapshot // Read ahead to know the gap to it
+	speed    float64
+	history  *Collector // Trend buffers refilled from replayed snapshots
+}
+
+func newMetricsReplay(path string, speed float64) (*metricsReplay, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	segments := []string{path}
+	if info.IsDir() {
+		if segments, err = listSegments(path); err != nil {
+			return nil, err
+		}
+	}
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("no recorded segments in %s", path)
+	}
+	if speed <= 0 {
+		speed = 1
+	}
+	r := &metricsReplay{segments: segments, speed: speed, history: NewCollector()}
+	first, err := r.read()
+	if err != nil {
+		return nil, err
+	}
+	if first == nil {
+		return nil, fmt.Errorf("no snapshots recorded in %s", path)
+	}
+	r.pending = first
+	return r, nil
+}
+
+// Next returns the next snapshot and how long to show it before asking
+// again. ok is false once the recording is exhausted.
+func (r *metricsReplay) Next() (snapshot MetricsSnapshot, delay time.Duration, ok bool, err error) {
+	if r.pending == nil {
+		return MetricsSnapshot{}, 0, false, nil
+	}
+	current := *r.pending
+	r.pending, err = r.read()
+	if r.pending != nil {
+		gap := min(max(r.pending.CollectedAt.Sub(current.CollectedAt), 0), maxReplayGap)
+		delay = time.Duration(float64(gap) / r.speed)
+	}
+	// Only the top processes are recorded, so they are also the process list.
+	current = r.history.addRemoteHistory(current)
+	current.Processes = current.TopProcesses
+	return current, delay, true, err
+}
+
+// read decodes the next snapshot, moving through segments as each ends. A
+// segment cut short by a crash ends at its last complete snapshot.
+func (r *metricsReplay) read() (*MetricsSnapshot, error) {
+	for {
+		if r.dec == nil {
+			if r.next >= len(r.segments) {
+				return nil, nil
+			}
+			if err := r.open(r.segments[r.next]); err != nil {
+				return nil, err
+			}
+			r.next++
+		}
+		var m MetricsSnapshot
+		err := r.dec.Decode(&m)
+		if err == nil {
+			return &m, nil
+		}
+		r.Close()
+		if !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, fmt.Errorf("read %s: %w", filepath.Base(r.segments[r.next-1]), err)
+		}
+	}
+}
+
+func (r *metricsReplay) open(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("read %s: %w", filepath.Base(path), err)
+	}
+	r.file = file
+	r.dec = gob.NewDecoder(gz)
+	return nil
+}
+
+// Close releases the open segment.
+func (r *metricsReplay) Close() {
+	if r.file != nil {
+		_ = r.file.Close()
+	}
+	r.file, r.dec = nil, nil
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func recordedSnapshot(at time.Time, cpu float64) MetricsSnapshot {
+	return MetricsSnapshot{
+		CollectedAt:     at,
+		Host:            "build-01",
+		HealthScore:     80,
+		HealthPenalties: []HealthPenalty{{Component: "CPU", Penalty: 20}},
+		CPU:             CPUStatus{Usage: cpu, PerCore: []float64{cpu, cpu / 2}},
+		Disks:           []DiskStatus{{Mount: "/", UsedPercent: 50}},
+		TopProcesses:    []ProcessInfo{{PID: 42, Name: "go", Command: "go test -token=secret", CPU: cpu}},
+		Processes:       []ProcessInfo{{PID: 42, Name: "go", CPU: cpu}, {PID: 7, Name: "idle"}},
+		Connections:     []ConnectionInfo{{PID: 42, Process: "go"}},
+		History:         MetricHistory{CPU: []float64{1, 2, 3}},
+	}
+}
+
+func TestRecorderReplayRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	recorder, err := newMetricsRecorder(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	for i := range 3 {
+		if err := recorder.Record(recordedSnapshot(start.Add(time.Duration(i)*time.Second), float64(10*(i+1)))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A later snapshot past the segment span starts a second segment.
+	if err := recorder.Record(recordedSnapshot(start.Add(recordSegmentSpan+time.Minute), 90)); err != nil {
+		t.Fatal(err)
+	}
+	if err := recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	segments, err := listSegments(dir)
+	if err != nil || len(segments) != 2 {
+		t.Fatalf("expected 2 segments, got %v (%v)", segments, err)
+	}
+	for path, want := range map[string]os.FileMode{dir: 0700, segments[0]: 0600} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != want {
+			t.Fatalf("%s should be private (%v), got %v", path, want, info.Mode().Perm())
+		}
+	}
+
+	replay, err := newMetricsReplay(dir, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer replay.Close()
+
+	var got []MetricsSnapshot
+	var delays []time.Duration
+	for {
+		snapshot, delay, ok, err := replay.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		got = append(got, snapshot)
+		delays = append(delays, delay)
+	}
+	if len(got) != 4 {
+		t.Fatalf("expected 4 snapshots, got %d", len(got))
+	}
+	if got[1].CPU.Usage != 20 || got[1].Host != "build-01" || got[1].TopProcesses[0].PID != 42 || got[1].HealthPenalties[0].Component != "CPU" {
+		t.Fatalf("snapshot did not round-trip: %+v", got[1])
+	}
+	// Arguments, the full process list and connections are not recorded.
+	if p := got[1].TopProcesses[0]; p.Command != "go" {
+		t.Fatalf("command line should not be recorded, got %q", p.Command)
+	}
+	if len(got[1].Processes) != 1 || len(got[1].Connections) != 0 {
+		t.Fatalf("expected only top processes and no connections, got %+v %+v", got[1].Processes, got[1].Connections)
+	}
+	// Trend history is rebuilt from the replayed snapshots.
+	if cpu := got[2].History.CPU; len(cpu) != 3 || cpu[0] != 10 || cpu[2] != 30 {
+		t.Fatalf("expected CPU history rebuilt from replay, got %v", cpu)
+	}
+	if !got[3].CollectedAt.Equal(start.Add(recordSegmentSpan + time.Minute)) {
+		t.Fatalf("unexpected last timestamp %v", got[3].CollectedAt)
+	}
+	// 1s gaps at 2x speed, the long pause capped, nothing after the end.
+	want := []time.Duration{500 * time.Millisecond, 500 * time.Millisecond, maxReplayGap / 2, 0}
+	for i := range want {
+		if delays[i] != want[i] {
+			t.Fatalf("delay %d: expected %v, got %v", i, want[i], delays[i])
+		}
+	}
+}
+
+func TestReplayReadsUnclosedSegment(t *testing.T) {
+	dir := t.TempDir()
+	recorder, err := newMetricsRecorder(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	for i := range 2 {
+		if err := recorder.Record(recordedSnapshot(start.Add(time.Duration(i)*time.Second), 10)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Simulate a crash: the gzip stream is never finished.
+	_ = recorder.file.Close()
+
+	segments, _ := listSegments(dir)
+	replay, err := newMetricsReplay(segments[0], 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer replay.Close()
+	count := 0
+	for {
+		_, _, ok, err := replay.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			break
+		}
+		count++
+	}
+	if count != 2 {
+		t.Fatalf("expected 2 flushed snapshots, got %d", count)
+	}
+}
+
+func TestRecorderPrunesOldSegments(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	old := filepath.Join(dir, now.Add(-recordRetention-time.Hour).Format(recordSegmentLayout)+recordSegmentExt)
+	recent := filepath.Join(dir, now.Add(-time.Hour).Format(recordSegmentLayout)+recordSegmentExt)
+	for _, path := range []string{old, recent} {
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	recorder, err := newMetricsRecorder(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := recorder.Record(recordedSnapshot(now, 10)); err != nil {
+		t.Fatal(err)
+	}
+	defer recorder.Close() //nolint:errcheck
+
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Fatalf("expired segment should be removed")
+	}
+	if _, err := os.Stat(recent); err != nil {
+		t.Fatalf("recent segment should be kept: %v", err)
+	}
+
+	// Over the size cap, older segments go even inside the retention window.
+	recorder.maxBytes = 1
+	if err := recorder.prune(now, filepath.Base(now.Format(recordSegmentLayout)+recordSegmentExt)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(recent); !os.IsNotExist(err) {
+		t.Fatalf("segment over the size cap should be removed")
+	}
+	if segments, _ := listSegments(dir); len(segments) != 1 {
+		t.Fatalf("active segment should remain, got %v", segments)
+	}
+}
+
+func TestNewMetricsReplayEmptyDir(t *testing.T) {
+	if _, err := newMetricsReplay(t.TempDir(), 1); err == nil {
+		t.Fatalf("expected error for a directory without segments")
+	}
+}
@@ -0,0 +1,353 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultRemoteCommand runs on each host; it must print --watch NDJSON.
+	defaultRemoteCommand = "mo status"
+	remoteRetryDelay     = 5 * time.Second
+	// A host that has not sent a snapshot for this long is shown as stale.
+	remoteStaleAfter = 5 * refreshInterval
+	// Snapshot lines carry every process in the top list and every disk;
+	// allow well beyond the usual few KB.
+	maxRemoteLine = 4 << 20
+)
+
+// hostList collects repeated or comma-separated --host flags.
+type hostList []string
+
+func (h *hostList) String() string { return strings.Join(*h, ",") }
+
+func (h *hostList) Set(value string) error {
+	for _, host := range strings.Split(value, ",") {
+		host = strings.TrimSpace(host)
+		if strings.HasPrefix(host, "-") {
+			// ssh would read it as an option, e.g. -oProxyCommand=...
+			return fmt.Errorf("host %q must not start with -", host)
+		}
+		if host != "" {
+			*h = append(*h, host)
+		}
+	}
+	return nil
+}
+
+// remoteHost is one host streaming snapshots over SSH. Only the UI
+// goroutine touches latest, err, updated and alerts.
+type remoteHost struct {
+	target  string
+	latest  MetricsSnapshot
+	err     error // Connection error, cleared by the next snapshot
+	updated time.Time
+	alerts  *alertEngine // Rule state for this host alone
+}
+
+// evaluateAlerts runs the host's rules over one of its snapshots and tags
+// the resulting events with the host.
+func (h *remoteHost) evaluateAlerts(m MetricsSnapshot) []alertEvent {
+	events := h.alerts.Evaluate(m)
+	for i := range events {
+		events[i].Host = h.target
+	}
+	return events
+}
+
+// remoteUpdate is one snapshot from a host, or the error that ended its
+// SSH session.
+type remoteUpdate struct {
+	host       int
+	data       MetricsSnapshot
+	collectErr error // Error the remote collector reported with data
+	err        error
+}
+
+// sshArgs builds the ssh command line. BatchMode keeps ssh from prompting
+// for a password underneath the TUI; keys or an agent are required. The
+// "--" keeps a target that starts with "-" from being read as an option.
+func sshArgs(target, command string, interval time.Duration) []string {
+	return []string{
+		"-T",
+		"-o", "BatchMode=yes",
+		"-o", "ServerAliveInterval=5",
+		"-o", "ServerAliveCountMax=2",
+		"--",
+		target,
+		fmt.Sprintf("%s --watch --interval %s", command, interval),
+	}
+}
+
+// runRemote keeps an SSH session to one host open until ctx is done,
+// reconnecting after remoteRetryDelay whenever it drops.
+func runRemote(ctx context.Context, host int, target, command string, out chan<- remoteUpdate) {
+	// Trend buffers for the cards; the export schema does not carry them.
+	history := NewCollector()
+	for {
+		err := streamSSH(ctx, host, target, command, history, out)
+		if ctx.Err() != nil {
+			return
+		}
+		select {
+		case out <- remoteUpdate{host: host, err: err}:
+		case <-ctx.Done():
+			return
+		}
+		select {
+		case <-time.After(remoteRetryDelay):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func streamSSH(ctx context.Context, host int, target, command string, history *Collector, out chan<- remoteUpdate) error {
+	cmd := exec.CommandContext(ctx, "ssh", sshArgs(target, command, refreshInterval)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("ssh %s: %w", target, err)
+	}
+	streamErr := streamRemote(ctx, stdout, host, history, out)
+	waitErr := cmd.Wait()
+
+	// ssh's own message says more than its exit status.
+	if msg := lastLine(stderr.String()); msg != "" {
+		return fmt.Errorf("ssh %s: %s", target, msg)
+	}
+	if streamErr != nil {
+		return fmt.Errorf("ssh %s: %w", target, streamErr)
+	}
+	if waitErr != nil {
+		return fmt.Errorf("ssh %s: %w", target, waitErr)
+	}
+	return fmt.Errorf("ssh %s: connection closed", target)
+}
+
+// streamRemote decodes NDJSON snapshots from r and sends them to out with
+// trend history filled in, until r ends or ctx is done.
+func streamRemote(ctx context.Context, r io.Reader, host int, history *Collector, out chan<- remoteUpdate) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxRemoteLine)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var snap jsonSnapshot
+		if err := json.Unmarshal(line, &snap); err != nil {
+			return fmt.Errorf("unexpected output %q", shorten(string(line), 60))
+		}
+		data, collectErr := fromJSONSnapshot(snap)
+		data = history.addRemoteHistory(data)
+		select {
+		case out <- remoteUpdate{host: host, data: data, collectErr: collectErr}:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+// addRemoteHistory feeds a received or replayed snapshot into the trend
+// buffers, the way Collect does for local collections.
+func (c *Collector) addRemoteHistory(m MetricsSnapshot) MetricsSnapshot {
+	m.History = c.updateHistory(m.CPU, m.Memory, m.DiskIO, m.GPU)
+	c.addNetworkHistory(m.Network)
+	m.NetworkHistory = c.networkHistory()
+	return m
+}
+
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// fromJSONSnapshot converts the export schema back into a snapshot, the
+// inverse of toJSONSnapshot. Only the top processes travel, so they are
+// also the process list.
+func fromJSONSnapshot(j jsonSnapshot) (MetricsSnapshot, error) {
+	m := MetricsSnapshot{
+		CollectedAt:    j.CollectedAt,
+		Host:           j.Host,
+		Platform:       j.Platform,
+		Uptime:         formatUptime(j.UptimeSeconds),
+		UptimeSeconds:  j.UptimeSeconds,
+		Procs:          j.Procs,
+		HealthScore:    j.Health.Score,
+		HealthScoreMsg: j.Health.Message,
+		Hardware: HardwareInfo{
+			Model:       j.Hardware.Model,
+			CPUModel:    j.Hardware.CPUModel,
+			TotalRAM:    j.Hardware.TotalRAM,
+			DiskSize:    j.Hardware.DiskSize,
+			OSVersion:   j.Hardware.OSVersion,
+			RefreshRate: j.Hardware.RefreshRate,
+		},
+		CPU: CPUStatus{
+			Usage:            j.CPU.UsagePercent,
+			PerCore:          j.CPU.PerCorePercent,
+			PerCoreEstimated: j.CPU.PerCoreEstimated,
+			Load1:            j.CPU.Load1,
+			Load5:            j.CPU.Load5,
+			Load15:           j.CPU.Load15,
+			CoreCount:        j.CPU.Cores,
+			LogicalCPU:       j.CPU.LogicalCPUs,
+			PCoreCount:       j.CPU.PerformanceCores,
+			ECoreCount:       j.CPU.EfficiencyCores,
+			CgroupQuota:      j.CPU.CgroupQuotaCores,
+			CgroupUsage:      j.CPU.CgroupUsagePercent,
+			CgroupThrottled:  j.CPU.CgroupThrottledPercent,
+		},
+		Memory: MemoryStatus{
+			Used:        j.Memory.UsedBytes,
+			Total:       j.Memory.TotalBytes,
+			UsedPercent: j.Memory.UsedPercent,
+			SwapUsed:    j.Memory.SwapUsedBytes,
+			SwapTotal:   j.Memory.SwapTotalBytes,
+			Cached:      j.Memory.CachedBytes,
+			Pressure:    j.Memory.Pressure,
+			CgroupLimit: j.Memory.CgroupLimit,
+			CgroupUsed:  j.Memory.CgroupUsed,
+		},
+		DiskIO: DiskIOStatus{
+			ReadRate:  j.DiskIO.ReadBytesPerSec / bytesPerMiB,
+			WriteRate: j.DiskIO.WriteBytesPerSec / bytesPerMiB,
+		},
+		Proxy: ProxyStatus{Enabled: j.Proxy.Enabled, Type: j.Proxy.Type, Host: j.Proxy.Host},
+		Thermal: ThermalStatus{
+			CPUTemp:      j.Thermal.CPUTempCelsius,
+			GPUTemp:      j.Thermal.GPUTempCelsius,
+			FanSpeed:     j.Thermal.FanRPM,
+			FanCount:     j.Thermal.FanCount,
+			SystemPower:  j.Thermal.SystemPowerW,
+			AdapterPower: j.Thermal.AdapterPowerW,
+			BatteryPower: j.Thermal.BatteryPowerW,
+		},
+	}
+	for _, p := range j.Health.Penalties {
+		m.HealthPenalties = append(m.HealthPenalties, HealthPenalty{Component: p.Component, Penalty: p.Points})
+	}
+	for _, g := range j.GPU {
+		m.GPU = append(m.GPU, GPUStatus{
+			Name:        g.Name,
+			Usage:       g.UsagePercent,
+			MemoryUsed:  g.MemoryUsedMiB,
+			MemoryTotal: g.MemoryTotalMiB,
+			CoreCount:   g.Cores,
+			Note:        g.Note,
+		})
+	}
+	for _, d := range j.Disks {
+		m.Disks = append(m.Disks, DiskStatus{
+			Mount:       d.Mount,
+			Device:      d.Device,
+			Fstype:      d.Fstype,
+			External:    d.External,
+			Used:        d.UsedBytes,
+			Total:       d.TotalBytes,
+			UsedPercent: d.UsedPercent,
+		})
+	}
+	for _, d := range j.DiskIO.Devices {
+		m.DiskIO.Devices = append(m.DiskIO.Devices, DiskDeviceIO{
+			Name:      d.Name,
+			ReadRate:  d.ReadBytesPerSec / bytesPerMiB,
+			WriteRate: d.WriteBytesPerSec / bytesPerMiB,
+			ReadIOPS:  d.ReadsPerSec,
+			WriteIOPS: d.WritesPerSec,
+			Await:     d.AwaitMs,
+			Busy:      d.BusyPercent,
+		})
+	}
+	for _, d := range j.DiskHealth {
+		h := DiskHealth{
+			Device:       d.Device,
+			Model:        d.Model,
+			Protocol:     d.Protocol,
+			Passed:       d.SmartPassed,
+			MediaErrors:  d.MediaErrors,
+			Temperature:  d.TempCelsius,
+			PowerOnHours: d.PowerOnHours,
+		}
+		if d.PercentUsed != nil {
+			h.WearKnown, h.PercentUsed = true, *d.PercentUsed
+		}
+		m.DiskHealth = append(m.DiskHealth, h)
+	}
+	for _, n := range j.Network {
+		m.Network = append(m.Network, NetworkStatus{
+			Name:      n.Name,
+			IP:        n.IP,
+			RxRateMBs: n.RxBytesPerSec / bytesPerMiB,
+			TxRateMBs: n.TxBytesPerSec / bytesPerMiB,
+			RxTotal:   n.RxBytesTotal,
+			TxTotal:   n.TxBytesTotal,
+			ErrIn:     n.ErrorsIn,
+			ErrOut:    n.ErrorsOut,
+			DropIn:    n.DropsIn,
+			DropOut:   n.DropsOut,
+		})
+	}
+	for _, b := range j.Batteries {
+		m.Batteries = append(m.Batteries, BatteryStatus{
+			Percent:    b.Percent,
+			Status:     b.Status,
+			TimeLeft:   b.TimeLeft,
+			Health:     b.Health,
+			CycleCount: b.CycleCount,
+			Capacity:   b.CapacityPercent,
+		})
+	}
+	for _, s := range j.Sensors {
+		m.Sensors = append(m.Sensors, SensorReading{Label: s.Label, Value: s.Value, Unit: s.Unit, Note: s.Note})
+	}
+	for _, d := range j.Bluetooth {
+		m.Bluetooth = append(m.Bluetooth, BluetoothDevice{Name: d.Name, Connected: d.Connected, Battery: d.Battery})
+	}
+	for _, ct := range j.Containers {
+		m.Containers = append(m.Containers, ContainerStatus{
+			ID:       ct.ID,
+			Name:     ct.Name,
+			Image:    ct.Image,
+			Engine:   ct.Engine,
+			State:    ct.State,
+			CPU:      ct.CPUPercent,
+			MemUsed:  ct.MemoryUsedBytes,
+			MemLimit: ct.MemoryLimitBytes,
+		})
+	}
+	for _, p := range j.TopProcesses {
+		m.TopProcesses = append(m.TopProcesses, ProcessInfo{
+			PID:     p.PID,
+			Name:    p.Name,
+			User:    p.User,
+			Command: p.Command,
+			CPU:     p.CPUPercent,
+			Memory:  p.MemoryPercent,
+			RSS:     p.RSSBytes,
+			Threads: p.Threads,
+			IORate:  p.IOBytesPerSec / bytesPerMiB,
+		})
+	}
+	m.Processes = m.TopProcesses
+
+	var err error
+	if j.Error != "" {
+		err = errors.New(j.Error)
+	}
+	return m, err
+}
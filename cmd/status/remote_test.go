@@ -0,0 +1,244 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func remoteSnapshot(host string, cpu float64) MetricsSnapshot {
+	return MetricsSnapshot{
+		CollectedAt:     time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+		Host:            host,
+		UptimeSeconds:   90061,
+		HealthScore:     72,
+		HealthPenalties: []HealthPenalty{{Component: "CPU", Penalty: 20}},
+		CPU:             CPUStatus{Usage: cpu, PerCore: []float64{cpu, cpu / 2}, LogicalCPU: 2, CgroupQuota: 2, CgroupUsage: cpu},
+		Memory:          MemoryStatus{Used: 8 << 30, Total: 16 << 30, UsedPercent: 50, CgroupLimit: 12 << 30, CgroupUsed: 6 << 30},
+		Disks:           []DiskStatus{{Mount: "/data", UsedPercent: 10}, {Mount: "/", UsedPercent: 67.5}},
+		DiskIO:          DiskIOStatus{ReadRate: 2, WriteRate: 0.5, Devices: []DiskDeviceIO{{Name: "nvme0n1", ReadRate: 2, WriteRate: 0.5, ReadIOPS: 120, Await: 0.4, Busy: 3}}},
+		DiskHealth:      []DiskHealth{{Device: "/dev/nvme0", Passed: true, WearKnown: true, PercentUsed: 3}, {Device: "/dev/sda", Passed: true}},
+		Network:         []NetworkStatus{{Name: "eth0", RxRateMBs: 1, TxRateMBs: 0.25}},
+		Sensors:         []SensorReading{{Label: "coretemp_package_id_0", Value: 61, Unit: "°C"}},
+		Containers:      []ContainerStatus{{ID: "abc", Name: "ci-runner", Engine: "podman", CPU: 150, MemUsed: 1 << 30}},
+		TopProcesses:    []ProcessInfo{{PID: 42, Name: "cc1", CPU: 95, IORate: 1}},
+	}
+}
+
+func TestFromJSONSnapshotRoundTrip(t *testing.T) {
+	in := remoteSnapshot("builder1", 80)
+	out, err := fromJSONSnapshot(toJSONSnapshot(in, errors.New("gpu: timeout")))
+	if err == nil || err.Error() != "gpu: timeout" {
+		t.Fatalf("remote collection error should be carried, got %v", err)
+	}
+	if out.Host != "builder1" || out.Uptime != formatUptime(90061) || out.HealthScore != 72 || out.HealthPenalties[0].Penalty != 20 {
+		t.Errorf("header fields lost: %+v", out)
+	}
+	if !reflect.DeepEqual(out.DiskIO, in.DiskIO) || !reflect.DeepEqual(out.DiskHealth, in.DiskHealth) || out.Network[0] != in.Network[0] || !slices.Equal(out.CPU.PerCore, in.CPU.PerCore) {
+		t.Errorf("rates, drives or cores changed: %+v %+v %+v %+v", out.DiskIO, out.DiskHealth, out.Network, out.CPU)
+	}
+	if out.CPU.CgroupQuota != 2 || out.Memory.CgroupLimit != 12<<30 || len(out.Containers) != 1 || out.Containers[0] != in.Containers[0] {
+		t.Errorf("cgroup or containers lost: %+v %+v %+v", out.CPU, out.Memory, out.Containers)
+	}
+	if len(out.Processes) != 1 || out.Processes[0].IORate != 1 || out.Sensors[0] != in.Sensors[0] {
+		t.Errorf("processes or sensors lost: %+v %+v", out.Processes, out.Sensors)
+	}
+}
+
+func TestStreamRemoteBuildsHistory(t *testing.T) {
+	var ndjson bytes.Buffer
+	encoder := json.NewEncoder(&ndjson)
+	for _, cpu := range []float64{10, 90} {
+		if err := encoder.Encode(toJSONSnapshot(remoteSnapshot("builder1", cpu), nil)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ndjson.WriteString("\n")
+
+	out := make(chan remoteUpdate, 4)
+	if err := streamRemote(context.Background(), &ndjson, 3, NewCollector(), out); err != nil {
+		t.Fatal(err)
+	}
+	close(out)
+	var updates []remoteUpdate
+	for u := range out {
+		updates = append(updates, u)
+	}
+	if len(updates) != 2 || updates[1].host != 3 {
+		t.Fatalf("expected 2 updates for host 3, got %+v", updates)
+	}
+	last := updates[1].data
+	if !slices.Equal(last.History.CPU, []float64{10, 90}) || !slices.Equal(last.NetworkHistory.RxHistory, []float64{1, 1}) {
+		t.Errorf("history not accumulated: %+v %+v", last.History.CPU, last.NetworkHistory.RxHistory)
+	}
+
+	if err := streamRemote(context.Background(), strings.NewReader("mo: command not found\n"), 0, NewCollector(), out); err == nil {
+		t.Error("non-JSON output should end the stream with an error")
+	}
+}
+
+func TestRunRemoteOverFakeSSH(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a shell script standing in for ssh")
+	}
+	line, err := json.Marshal(toJSONSnapshot(remoteSnapshot("builder1", 40), nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	argsFile := filepath.Join(dir, "args")
+	script := "#!/bin/sh\necho \"$@\" > " + argsFile + "\necho '" + string(line) + "'\necho 'Connection to builder1 closed by remote host.' >&2\nexit 255\n"
+	if err := os.WriteFile(filepath.Join(dir, "ssh"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	out := make(chan remoteUpdate)
+	go runRemote(ctx, 0, "me@builder1", "~/bin/mo status", out)
+
+	first := <-out
+	if first.err != nil || first.data.Host != "builder1" || first.data.CPU.Usage != 40 {
+		t.Fatalf("expected a snapshot first, got %+v", first)
+	}
+	second := <-out
+	if second.err == nil || !strings.Contains(second.err.Error(), "closed by remote host") {
+		t.Fatalf("expected ssh's message as the error, got %v", second.err)
+	}
+	args, _ := os.ReadFile(argsFile)
+	if got := strings.TrimSpace(string(args)); !strings.HasSuffix(got, "-- me@builder1 ~/bin/mo status --watch --interval 1s") || !strings.Contains(got, "BatchMode=yes") {
+		t.Errorf("unexpected ssh arguments %q", got)
+	}
+}
+
+func TestHostListFlag(t *testing.T) {
+	var hosts hostList
+	_ = hosts.Set("a@one, two")
+	_ = hosts.Set("three")
+	if !slices.Equal(hosts, hostList{"a@one", "two", "three"}) {
+		t.Fatalf("hosts = %v", hosts)
+	}
+	if err := hosts.Set("ok,-oProxyCommand=touch /tmp/x"); err == nil {
+		t.Fatal("a host starting with - should be rejected")
+	}
+}
+
+func TestRemoteHostsKeepTheirOwnAlerts(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	m := newModel()
+	m.alerts = newAlertEngine([]alertRule{{Text: "cpu > 80", Metric: "cpu", Threshold: 80, Clear: 80}})
+	sink := &recordingSink{}
+	m.alertSinks = []alertSink{sink}
+	m.remotes = []*remoteHost{
+		{target: "me@builder1", alerts: newAlertEngine(m.alerts.rules)},
+		{target: "me@builder2", alerts: newAlertEngine(m.alerts.rules)},
+	}
+	m.remoteOpen, m.width, m.ready = -1, 120, true
+	// Buffered so the follow-up read of the feed returns instead of blocking.
+	m.remoteFeed = make(chan remoteUpdate, 4)
+	for range 4 {
+		m.remoteFeed <- remoteUpdate{err: errors.New("unused")}
+	}
+
+	update := func(msg remoteUpdate) {
+		t.Helper()
+		next, cmd := m.Update(msg)
+		m = next.(model)
+		if batch, ok := cmd().(tea.BatchMsg); ok {
+			for _, c := range batch {
+				c()
+			}
+		}
+	}
+	// builder1 runs hot while nobody is looking at it; builder2 is calm.
+	update(remoteUpdate{host: 0, data: remoteSnapshot("builder1", 95)})
+	update(remoteUpdate{host: 1, data: remoteSnapshot("builder2", 10)})
+
+	if firing := m.remotes[0].alerts.Firing(); len(firing) != 1 {
+		t.Fatalf("builder1 should fire while closed, got %+v", firing)
+	}
+	if firing := m.remotes[1].alerts.Firing(); len(firing) != 0 {
+		t.Fatalf("builder2 should not inherit builder1's state, got %+v", firing)
+	}
+	if len(sink.events) != 1 || !strings.HasPrefix(sink.events[0].String(), "me@builder1: cpu") {
+		t.Fatalf("expected one event tagged with builder1, got %+v", sink.events)
+	}
+	if view := stripANSI(m.View()); !strings.Contains(view, "1 firing") {
+		t.Fatalf("overview should flag the firing host:\n%s", view)
+	}
+
+	m.remoteOpen = 1
+	if banner := renderAlertBanner(m.firingAlerts(), 0); banner != "" {
+		t.Fatalf("builder2's screen should show no alert, got %q", banner)
+	}
+}
+
+type recordingSink struct{ events []alertEvent }
+
+func (s *recordingSink) Send(e alertEvent) error {
+	s.events = append(s.events, e)
+	return nil
+}
+
+func TestHostOverview(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	m := newModel()
+	m.width = 120
+	m.remotes = []*remoteHost{{target: "me@builder1"}, {target: "me@builder2"}}
+	m.remoteFeed = make(chan remoteUpdate)
+	m.remoteOpen, m.ready = -1, true
+
+	update := func(msg tea.Msg) {
+		t.Helper()
+		next, _ := m.Update(msg)
+		m = next.(model)
+	}
+	key := func(k tea.KeyType) { update(tea.KeyMsg{Type: k}) }
+
+	update(remoteUpdate{host: 1, data: remoteSnapshot("builder2", 40)})
+	update(remoteUpdate{host: 0, err: errors.New("ssh me@builder1: Permission denied (publickey).")})
+
+	view := stripANSI(m.View())
+	if !strings.Contains(view, "2 hosts") || !strings.Contains(view, "ssh me@builder1: Permission") {
+		t.Fatalf("overview should list both hosts:\n%s", view)
+	}
+	row := view[strings.Index(view, "me@builder2"):]
+	row = row[:strings.Index(row, "\n")]
+	for _, want := range []string{"● 72", "40.0%", "50.0%", "67.5%", "1.0 MB/s", "live"} {
+		if !strings.Contains(row, want) {
+			t.Errorf("builder2 row missing %q: %q", want, row)
+		}
+	}
+
+	key(tea.KeyDown)
+	key(tea.KeyEnter)
+	if m.remoteOpen != 1 || m.metrics.Host != "builder2" {
+		t.Fatalf("enter should open builder2, got %d %q", m.remoteOpen, m.metrics.Host)
+	}
+	if view := m.View(); !strings.Contains(view, "⇄ me@builder2") || !strings.Contains(view, iconCPU+" CPU") {
+		t.Fatalf("opened host should show its cards:\n%s", view)
+	}
+
+	// Snapshots from other hosts do not replace the open one.
+	update(remoteUpdate{host: 0, data: remoteSnapshot("builder1", 99)})
+	if m.metrics.Host != "builder2" {
+		t.Fatalf("metrics switched to %q", m.metrics.Host)
+	}
+
+	key(tea.KeyEsc)
+	if m.remoteOpen != -1 {
+		t.Fatal("esc should go back to the overview")
+	}
+}
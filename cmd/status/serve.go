@@ -0,0 +1,306 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	openMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+	prometheusContentType  = "text/plain; version=0.0.4; charset=utf-8"
+	serveShutdownTimeout   = 5 * time.Second
+)
+
+// metricFamily is one OpenMetrics gauge with its samples.
+type metricFamily struct {
+	name    string
+	help    string
+	unit    string // OpenMetrics unit; name must end with it
+	samples []metricSample
+}
+
+type metricSample struct {
+	labels []metricLabel
+	value  float64
+}
+
+type metricLabel struct {
+	name, value string
+}
+
+// metricsServer keeps the latest snapshot from a background collector so
+// scrapes never wait on slow probes.
+type metricsServer struct {
+	collector *Collector
+
+	mu      sync.RWMutex
+	latest  MetricsSnapshot
+	lastErr error
+	ready   bool
+}
+
+func newMetricsServer(c *Collector) *metricsServer {
+	return &metricsServer{collector: c}
+}
+
+// run collects every interval until ctx is cancelled. The first sample
+// only primes rate counters, so the endpoint becomes ready on the second.
+func (s *metricsServer) run(ctx context.Context, interval time.Duration) {
+	_, _ = s.collector.Collect()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		data, err := s.collector.Collect()
+		s.mu.Lock()
+		s.latest = data
+		s.lastErr = err
+		s.ready = true
+		s.mu.Unlock()
+	}
+}
+
+func (s *metricsServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	data, lastErr, ready := s.latest, s.lastErr, s.ready
+	s.mu.RUnlock()
+
+	if !ready {
+		http.Error(w, "metrics not collected yet", http.StatusServiceUnavailable)
+		return
+	}
+
+	openMetrics := strings.Contains(r.Header.Get("Accept"), "application/openmetrics-text")
+	if openMetrics {
+		w.Header().Set("Content-Type", openMetricsContentType)
+	} else {
+		w.Header().Set("Content-Type", prometheusContentType)
+	}
+	// Collection errors are partial (one probe failed), so report them
+	// alongside the values instead of failing the scrape.
+	failed := 0.0
+	if lastErr != nil {
+		failed = 1
+	}
+	families := append(buildMetricFamilies(data), metricFamily{
+		name: "mole_collect_failed", help: "1 when the last collection reported a probe error.",
+		samples: []metricSample{{value: failed}},
+	})
+	writeMetricFamilies(w, families, openMetrics)
+}
+
+// runServe exposes /metrics on addr until ctx is cancelled.
+func runServe(ctx context.Context, c *Collector, addr string, interval time.Duration) error {
+	server := newMetricsServer(c)
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", server)
+
+	httpServer := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go server.run(ctx, interval)
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- httpServer.ListenAndServe() }()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+		defer cancel()
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			return err
+		}
+		if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	}
+}
+
+// buildMetricFamilies maps a snapshot to gauges. Rates are exported in
+// bytes per second rather than the MiB/s the cards display.
+func buildMetricFamilies(m MetricsSnapshot) []metricFamily {
+	gauge := func(name, help, unit string, value float64) metricFamily {
+		return metricFamily{name: name, help: help, unit: unit, samples: []metricSample{{value: value}}}
+	}
+
+	families := []metricFamily{
+		gauge("mole_health_score", "Computed system health score (0-100).", "", float64(m.HealthScore)),
+		gauge("mole_cpu_usage_percent", "Total CPU usage.", "percent", m.CPU.Usage),
+		{
+			name: "mole_cpu_load", help: "Load average.",
+			samples: []metricSample{
+				{labels: []metricLabel{{"period", "1m"}}, value: m.CPU.Load1},
+				{labels: []metricLabel{{"period", "5m"}}, value: m.CPU.Load5},
+				{labels: []metricLabel{{"period", "15m"}}, value: m.CPU.Load15},
+			},
+		},
+		gauge("mole_memory_used_bytes", "Memory in use.", "bytes", float64(m.Memory.Used)),
+		gauge("mole_memory_total_bytes", "Installed memory.", "bytes", float64(m.Memory.Total)),
+		gauge("mole_memory_cached_bytes", "File cache that can be reclaimed.", "bytes", float64(m.Memory.Cached)),
+		gauge("mole_swap_used_bytes", "Swap in use.", "bytes", float64(m.Memory.SwapUsed)),
+		gauge("mole_swap_total_bytes", "Swap configured.", "bytes", float64(m.Memory.SwapTotal)),
+		gauge("mole_disk_read_bytes_per_second", "Disk read throughput.", "bytes_per_second", m.DiskIO.ReadRate*bytesPerMiB),
+		gauge("mole_disk_write_bytes_per_second", "Disk write throughput.", "bytes_per_second", m.DiskIO.WriteRate*bytesPerMiB),
+		gauge("mole_cpu_temperature_celsius", "CPU temperature.", "celsius", m.Thermal.CPUTemp),
+		gauge("mole_gpu_temperature_celsius", "GPU temperature.", "celsius", m.Thermal.GPUTemp),
+		gauge("mole_fan_speed_rpm", "Fan speed.", "rpm", float64(m.Thermal.FanSpeed)),
+		gauge("mole_system_power_watts", "System power draw.", "watts", m.Thermal.SystemPower),
+		gauge("mole_battery_power_watts", "Battery power, positive when discharging.", "watts", m.Thermal.BatteryPower),
+		gauge("mole_collected_timestamp_seconds", "Time of the last collection.", "seconds", float64(m.CollectedAt.UnixNano())/1e9),
+	}
+
+	penalties := metricFamily{name: "mole_health_penalty", help: "Points a component took off the health score."}
+	for _, p := range m.HealthPenalties {
+		penalties.samples = append(penalties.samples, metricSample{labels: []metricLabel{{"component", p.Component}}, value: p.Penalty})
+	}
+
+	cores := metricFamily{name: "mole_cpu_core_usage_percent", help: "Per-core CPU usage.", unit: "percent"}
+	for i, usage := range m.CPU.PerCore {
+		cores.samples = append(cores.samples, metricSample{labels: []metricLabel{{"core", strconv.Itoa(i)}}, value: usage})
+	}
+
+	diskUsed := metricFamily{name: "mole_disk_used_bytes", help: "Space used on a mounted volume.", unit: "bytes"}
+	diskTotal := metricFamily{name: "mole_disk_total_bytes", help: "Size of a mounted volume.", unit: "bytes"}
+	for _, d := range m.Disks {
+		labels := []metricLabel{{"mount", d.Mount}, {"device", d.Device}, {"fstype", d.Fstype}}
+		diskUsed.samples = append(diskUsed.samples, metricSample{labels: labels, value: float64(d.Used)})
+		diskTotal.samples = append(diskTotal.samples, metricSample{labels: labels, value: float64(d.Total)})
+	}
+
+	deviceRead := metricFamily{name: "mole_disk_device_read_bytes_per_second", help: "Read throughput of a whole disk.", unit: "bytes_per_second"}
+	deviceWrite := metricFamily{name: "mole_disk_device_write_bytes_per_second", help: "Write throughput of a whole disk.", unit: "bytes_per_second"}
+	deviceOps := metricFamily{name: "mole_disk_device_operations_per_second", help: "Completed reads or writes per second on a whole disk."}
+	deviceAwait := metricFamily{name: "mole_disk_device_await_seconds", help: "Average time per completed IO, queueing included.", unit: "seconds"}
+	for _, d := range m.DiskIO.Devices {
+		labels := []metricLabel{{"device", d.Name}}
+		deviceRead.samples = append(deviceRead.samples, metricSample{labels: labels, value: d.ReadRate * bytesPerMiB})
+		deviceWrite.samples = append(deviceWrite.samples, metricSample{labels: labels, value: d.WriteRate * bytesPerMiB})
+		deviceOps.samples = append(deviceOps.samples,
+			metricSample{labels: []metricLabel{{"device", d.Name}, {"op", "read"}}, value: d.ReadIOPS},
+			metricSample{labels: []metricLabel{{"device", d.Name}, {"op", "write"}}, value: d.WriteIOPS})
+		deviceAwait.samples = append(deviceAwait.samples, metricSample{labels: labels, value: d.Await / 1000})
+	}
+
+	smartPassed := metricFamily{name: "mole_disk_smart_passed", help: "1 when the drive passes its SMART self-assessment."}
+	wear := metricFamily{name: "mole_disk_wear_percent", help: "Share of rated endurance used, from SMART.", unit: "percent"}
+	mediaErrors := metricFamily{name: "mole_disk_media_errors", help: "NVMe media errors, or bad ATA sectors."}
+	driveTemp := metricFamily{name: "mole_disk_temperature_celsius", help: "Drive temperature from SMART.", unit: "celsius"}
+	for _, d := range m.DiskHealth {
+		labels := []metricLabel{{"device", d.Device}, {"model", d.Model}}
+		passed := 0.0
+		if d.Passed {
+			passed = 1
+		}
+		smartPassed.samples = append(smartPassed.samples, metricSample{labels: labels, value: passed})
+		if d.WearKnown {
+			wear.samples = append(wear.samples, metricSample{labels: labels, value: d.PercentUsed})
+		}
+		mediaErrors.samples = append(mediaErrors.samples, metricSample{labels: labels, value: float64(d.MediaErrors)})
+		if d.Temperature > 0 {
+			driveTemp.samples = append(driveTemp.samples, metricSample{labels: labels, value: d.Temperature})
+		}
+	}
+
+	rx := metricFamily{name: "mole_network_receive_bytes_per_second", help: "Network receive rate.", unit: "bytes_per_second"}
+	tx := metricFamily{name: "mole_network_transmit_bytes_per_second", help: "Network transmit rate.", unit: "bytes_per_second"}
+	for _, n := range m.Network {
+		labels := []metricLabel{{"interface", n.Name}}
+		rx.samples = append(rx.samples, metricSample{labels: labels, value: n.RxRateMBs * bytesPerMiB})
+		tx.samples = append(tx.samples, metricSample{labels: labels, value: n.TxRateMBs * bytesPerMiB})
+	}
+
+	charge := metricFamily{name: "mole_battery_charge_percent", help: "Battery charge level.", unit: "percent"}
+	capacity := metricFamily{name: "mole_battery_capacity_percent", help: "Maximum capacity relative to design.", unit: "percent"}
+	cycles := metricFamily{name: "mole_battery_cycle_count", help: "Battery charge cycles."}
+	for i, b := range m.Batteries {
+		labels := []metricLabel{{"battery", strconv.Itoa(i)}}
+		charge.samples = append(charge.samples, metricSample{labels: labels, value: b.Percent})
+		capacity.samples = append(capacity.samples, metricSample{labels: labels, value: float64(b.Capacity)})
+		cycles.samples = append(cycles.samples, metricSample{labels: labels, value: float64(b.CycleCount)})
+	}
+
+	// cgroup limits only exist inside containers and limited slices.
+	if m.CPU.CgroupQuota > 0 {
+		families = append(families,
+			gauge("mole_cgroup_cpu_quota_cores", "CPU quota of the collector's cgroup.", "", m.CPU.CgroupQuota),
+			gauge("mole_cgroup_cpu_usage_percent", "CPU use as a percent of the cgroup quota.", "percent", m.CPU.CgroupUsage),
+			gauge("mole_cgroup_cpu_throttled_percent", "Share of cgroup scheduler periods throttled.", "percent", m.CPU.CgroupThrottled))
+	}
+	if m.Memory.CgroupLimit > 0 {
+		families = append(families,
+			gauge("mole_cgroup_memory_limit_bytes", "Memory limit of the collector's cgroup.", "bytes", float64(m.Memory.CgroupLimit)),
+			gauge("mole_cgroup_memory_used_bytes", "Memory charged to the collector's cgroup.", "bytes", float64(m.Memory.CgroupUsed)))
+	}
+
+	containerCPU := metricFamily{name: "mole_container_cpu_usage_percent", help: "Container CPU use, percent of one core.", unit: "percent"}
+	containerMem := metricFamily{name: "mole_container_memory_used_bytes", help: "Container memory use, excluding reclaimable cache.", unit: "bytes"}
+	for _, ct := range m.Containers {
+		labels := []metricLabel{{"name", ct.Name}, {"engine", ct.Engine}, {"image", ct.Image}}
+		containerCPU.samples = append(containerCPU.samples, metricSample{labels: labels, value: ct.CPU})
+		containerMem.samples = append(containerMem.samples, metricSample{labels: labels, value: float64(ct.MemUsed)})
+	}
+
+	return append(families, penalties, cores, diskUsed, diskTotal,
+		deviceRead, deviceWrite, deviceOps, deviceAwait, smartPassed, wear, mediaErrors, driveTemp,
+		rx, tx, charge, capacity, cycles, containerCPU, containerMem)
+}
+
+// writeMetricFamilies renders gauges in the OpenMetrics text format, or the
+// Prometheus 0.0.4 text format, which omits UNIT lines and the EOF marker.
+// Families without samples are skipped.
+func writeMetricFamilies(w io.Writer, families []metricFamily, openMetrics bool) {
+	for _, family := range families {
+		if len(family.samples) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "# TYPE %s gauge\n", family.name)
+		if openMetrics && family.unit != "" {
+			fmt.Fprintf(w, "# UNIT %s %s\n", family.name, family.unit)
+		}
+		fmt.Fprintf(w, "# HELP %s %s\n", family.name, family.help)
+		for _, sample := range family.samples {
+			fmt.Fprintf(w, "%s%s %s\n", family.name, formatMetricLabels(sample.labels), strconv.FormatFloat(sample.value, 'g', -1, 64))
+		}
+	}
+	if openMetrics {
+		fmt.Fprintln(w, "# EOF")
+	}
+}
+
+func formatMetricLabels(labels []metricLabel) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, label := range labels {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "%s=\"%s\"", label.name, escapeLabelValue(label.value))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildMetricFamilies(t *testing.T) {
+	snap := MetricsSnapshot{
+		CollectedAt: time.Unix(1700000000, 0),
+		HealthScore: 72,
+		CPU:         CPUStatus{Usage: 12.5, PerCore: []float64{10, 15}},
+		Memory:      MemoryStatus{Used: 1024, Total: 4096},
+		Disks:       []DiskStatus{{Mount: "/", Device: "disk1", Fstype: "apfs", Used: 10, Total: 20}},
+		DiskIO:      DiskIOStatus{ReadRate: 1},
+		Network:     []NetworkStatus{{Name: "en0", RxRateMBs: 2}},
+	}
+
+	var b strings.Builder
+	writeMetricFamilies(&b, buildMetricFamilies(snap), true)
+	out := b.String()
+
+	for _, want := range []string{
+		"mole_health_score 72\n",
+		"mole_cpu_usage_percent 12.5\n",
+		`mole_cpu_core_usage_percent{core="1"} 15` + "\n",
+		"# UNIT mole_memory_used_bytes bytes\n",
+		"mole_memory_used_bytes 1024\n",
+		`mole_disk_used_bytes{mount="/",device="disk1",fstype="apfs"} 10` + "\n",
+		"mole_disk_read_bytes_per_second 1.048576e+06\n",
+		`mole_network_receive_bytes_per_second{interface="en0"} 2.097152e+06` + "\n",
+		"mole_collected_timestamp_seconds 1.7e+09\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in output:\n%s", want, out)
+		}
+	}
+	if !strings.HasSuffix(out, "# EOF\n") {
+		t.Error("OpenMetrics output must end with # EOF")
+	}
+	if strings.Contains(out, "mole_battery_charge_percent") {
+		t.Error("families without samples should be omitted")
+	}
+}
+
+func TestBuildMetricFamiliesCgroup(t *testing.T) {
+	var b strings.Builder
+	writeMetricFamilies(&b, buildMetricFamilies(MetricsSnapshot{}), false)
+	if strings.Contains(b.String(), "mole_cgroup_") || strings.Contains(b.String(), "mole_container_") {
+		t.Errorf("cgroup and container gauges need data:\n%s", b.String())
+	}
+
+	b.Reset()
+	writeMetricFamilies(&b, buildMetricFamilies(MetricsSnapshot{
+		CPU:        CPUStatus{CgroupQuota: 1.5, CgroupUsage: 40, CgroupThrottled: 25},
+		Memory:     MemoryStatus{CgroupLimit: 4096, CgroupUsed: 1024},
+		Containers: []ContainerStatus{{Name: "web", Engine: "docker", Image: "nginx", CPU: 50, MemUsed: 2048}},
+	}), false)
+	for _, want := range []string{
+		"mole_cgroup_cpu_quota_cores 1.5\n",
+		"mole_cgroup_cpu_throttled_percent 25\n",
+		"mole_cgroup_memory_limit_bytes 4096\n",
+		`mole_container_cpu_usage_percent{name="web",engine="docker",image="nginx"} 50` + "\n",
+		`mole_container_memory_used_bytes{name="web",engine="docker",image="nginx"} 2048` + "\n",
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("missing %q in output:\n%s", want, b.String())
+		}
+	}
+}
+
+func TestBuildMetricFamiliesDiskDevices(t *testing.T) {
+	var b strings.Builder
+	writeMetricFamilies(&b, buildMetricFamilies(MetricsSnapshot{
+		DiskIO: DiskIOStatus{Devices: []DiskDeviceIO{{Name: "sda", WriteRate: 1, WriteIOPS: 30, Await: 2.5}}},
+		DiskHealth: []DiskHealth{
+			{Device: "/dev/nvme0", Model: "980 PRO", Passed: true, WearKnown: true, PercentUsed: 7},
+			{Device: "/dev/sda", Model: "WD40EFRX", MediaErrors: 8},
+		},
+	}), false)
+	out := b.String()
+	for _, want := range []string{
+		`mole_disk_device_write_bytes_per_second{device="sda"} 1.048576e+06` + "\n",
+		`mole_disk_device_operations_per_second{device="sda",op="write"} 30` + "\n",
+		`mole_disk_device_await_seconds{device="sda"} 0.0025` + "\n",
+		`mole_disk_smart_passed{device="/dev/sda",model="WD40EFRX"} 0` + "\n",
+		`mole_disk_wear_percent{device="/dev/nvme0",model="980 PRO"} 7` + "\n",
+		`mole_disk_media_errors{device="/dev/sda",model="WD40EFRX"} 8` + "\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in output:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, `mole_disk_wear_percent{device="/dev/sda"`) {
+		t.Error("drives without a wear reading should not report 0%")
+	}
+}
+
+func TestWriteMetricFamiliesPrometheusFormat(t *testing.T) {
+	var b strings.Builder
+	writeMetricFamilies(&b, []metricFamily{{
+		name: "mole_test_bytes", help: "Test.", unit: "bytes",
+		samples: []metricSample{{labels: []metricLabel{{"mount", "/Volumes/\"Quoted\"\\"}}, value: 1}},
+	}}, false)
+	out := b.String()
+	if strings.Contains(out, "# UNIT") || strings.Contains(out, "# EOF") {
+		t.Fatalf("Prometheus text must not carry OpenMetrics-only lines:\n%s", out)
+	}
+	if !strings.Contains(out, `mount="/Volumes/\"Quoted\"\\"`) {
+		t.Fatalf("label value not escaped:\n%s", out)
+	}
+}
+
+func TestMetricsServerServeHTTP(t *testing.T) {
+	server := newMetricsServer(NewCollector())
+
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 before the first collection, got %d", rec.Code)
+	}
+
+	server.latest = MetricsSnapshot{HealthScore: 90}
+	server.ready = true
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Accept", "application/openmetrics-text; version=1.0.0")
+	rec = httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != openMetricsContentType {
+		t.Fatalf("unexpected response %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if body := rec.Body.String(); !strings.Contains(body, "mole_health_score 90") || !strings.Contains(body, "mole_collect_failed 0") {
+		t.Fatalf("unexpected body:\n%s", rec.Body.String())
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 )
@@ -21,15 +22,17 @@ var (
 )
 
 const (
-	colWidth    = 38
-	iconCPU     = "◉"
-	iconMemory  = "◫"
-	iconGPU     = "◧"
-	iconDisk    = "▥"
-	iconNetwork = "⇅"
-	iconBattery = "◪"
-	iconSensors = "◈"
-	iconProcs   = "❊"
+	colWidth        = 38
+	iconCPU         = "◉"
+	iconMemory      = "◫"
+	iconGPU         = "◧"
+	iconDisk        = "▥"
+	iconNetwork     = "⇅"
+	iconBattery     = "◪"
+	iconSensors     = "◈"
+	iconProcs       = "❊"
+	iconPeripherals = "◎"
+	iconContainers  = "▣"
 )
 
 // Mole body frames (facing right).
@@ -125,9 +128,11 @@ func getMoleFrame(animFrame int, termWidth int) string {
 }
 
 type cardData struct {
-	icon  string
-	title string
-	lines []string
+	id      string
+	icon    string
+	title   string
+	lines   []string
+	focused bool
 }
 
 func renderHeader(m MetricsSnapshot, errMsg string, animFrame int, termWidth int, catHidden bool) string {
@@ -170,6 +175,9 @@ func renderHeader(m MetricsSnapshot, errMsg string, animFrame int, termWidth int
 	}
 
 	headerLine := title + "  " + scoreText + "  " + strings.Join(infoParts, " · ")
+	if breakdown := formatHealthBreakdown(m.HealthPenalties); breakdown != "" {
+		headerLine += "\n" + subtleStyle.Render("Score "+breakdown)
+	}
 
 	// Show cat unless hidden
 	var mole string
@@ -189,6 +197,147 @@ func renderHeader(m MetricsSnapshot, errMsg string, animFrame int, termWidth int
 	return headerLine + "\n" + mole
 }
 
+// formatHealthBreakdown lists the points each component cost, largest
+// first, e.g. "CPU −12 · Memory −5". Penalties under half a point are left
+// out so the list matches the rounded score.
+func formatHealthBreakdown(penalties []HealthPenalty) string {
+	sorted := append([]HealthPenalty(nil), penalties...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Penalty > sorted[j].Penalty })
+	var parts []string
+	for _, p := range sorted {
+		if p.Penalty < 0.5 {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s −%.0f", p.Component, p.Penalty))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// renderReplayStatus tells a replayed view apart from live metrics.
+func renderReplayStatus(at time.Time, frame int, done bool) string {
+	text := fmt.Sprintf("▶ Replay %s · snapshot %d", at.Local().Format("2006-01-02 15:04:05"), frame)
+	if done {
+		text = fmt.Sprintf("■ Replay ended at %s · %d snapshots", at.Local().Format("2006-01-02 15:04:05"), frame)
+	}
+	return warnStyle.Render(text)
+}
+
+// renderRemoteStatus names the host whose cards are shown and how fresh
+// its last snapshot is.
+func renderRemoteStatus(host *remoteHost, now time.Time, overview bool) string {
+	text := "⇄ " + host.target
+	if age := now.Sub(host.updated); age > remoteStaleAfter {
+		text += fmt.Sprintf(" · no data for %s", age.Truncate(time.Second))
+	}
+	if overview {
+		text += subtleStyle.Render("  esc Hosts")
+	}
+	if host.err != nil || now.Sub(host.updated) > remoteStaleAfter {
+		return warnStyle.Render(text)
+	}
+	return primaryStyle.Render(text)
+}
+
+// renderRemoteConnecting stands in for the cards until a host sends its
+// first snapshot.
+func renderRemoteConnecting(host *remoteHost, overview bool) string {
+	lines := []string{titleStyle.Render("Status") + "  " + subtleStyle.Render("Connecting to "+host.target+"...")}
+	if host.err != nil {
+		lines = append(lines, "", dangerStyle.Render(host.err.Error()), subtleStyle.Render(fmt.Sprintf("Retrying every %s", remoteRetryDelay)))
+	}
+	footer := "q Quit"
+	if overview {
+		footer = "esc Hosts  q Quit"
+	}
+	return strings.Join(append(lines, "", subtleStyle.Render(footer)), "\n")
+}
+
+// renderHostOverview shows one row per remote host with its health score
+// and headline numbers.
+func renderHostOverview(hosts []*remoteHost, selected int, now time.Time, width int) string {
+	if width <= 0 {
+		width = 100
+	}
+	hostWidth := min(max(width-72, 12), 32)
+	lines := []string{
+		titleStyle.Render("Status") + "  " + subtleStyle.Render(fmt.Sprintf("%d hosts", len(hosts))),
+		"",
+		subtleStyle.Render(fmt.Sprintf("  %-*s %-7s %6s %6s %6s %11s %11s  %s", hostWidth, "HOST", "HEALTH", "CPU", "MEM", "DISK", "DOWN", "UP", "STATE")),
+	}
+	for i, h := range hosts {
+		prefix := "  "
+		name := fmt.Sprintf("%-*s", hostWidth, shorten(h.target, hostWidth))
+		if i == selected {
+			prefix, name = primaryStyle.Render("▶ "), primaryStyle.Render(name)
+		}
+
+		var state string
+		switch {
+		case h.err != nil:
+			state = dangerStyle.Render(shorten(h.err.Error(), max(width-hostWidth-60, 16)))
+		case h.updated.IsZero():
+			state = subtleStyle.Render("connecting")
+		case now.Sub(h.updated) > remoteStaleAfter:
+			state = warnStyle.Render(fmt.Sprintf("stale %s", now.Sub(h.updated).Truncate(time.Second)))
+		case len(h.alerts.Firing()) > 0:
+			state = dangerStyle.Render(fmt.Sprintf("⚠ %d firing", len(h.alerts.Firing())))
+		default:
+			state = okStyle.Render("live")
+		}
+		if h.updated.IsZero() {
+			lines = append(lines, prefix+name+" "+subtleStyle.Render(fmt.Sprintf("%-7s %6s %6s %6s %11s %11s", "-", "-", "-", "-", "-", "-"))+"  "+state)
+			continue
+		}
+
+		m := h.latest
+		var rx, tx, disk float64
+		for _, n := range busiestInterfaces(m.Network) {
+			rx += n.RxRateMBs
+			tx += n.TxRateMBs
+		}
+		if root := rootDisk(m.Disks); root != nil {
+			disk = root.UsedPercent
+		}
+		health := getScoreStyle(m.HealthScore).Render(fmt.Sprintf("● %-5d", m.HealthScore))
+		lines = append(lines, fmt.Sprintf("%s%s %s %s %s %s %11s %11s  %s", prefix, name, health,
+			colorizePercent(m.CPU.Usage, fmt.Sprintf("%5.1f%%", m.CPU.Usage)),
+			colorizePercent(m.Memory.UsedPercent, fmt.Sprintf("%5.1f%%", m.Memory.UsedPercent)),
+			colorizePercent(disk, fmt.Sprintf("%5.1f%%", disk)),
+			formatRate(rx), formatRate(tx), state))
+	}
+	lines = append(lines, "", subtleStyle.Render("↑↓ Select  enter Open  q Quit"))
+	return strings.Join(lines, "\n")
+}
+
+// rootDisk returns the disk mounted at /, or the first one.
+func rootDisk(disks []DiskStatus) *DiskStatus {
+	for i := range disks {
+		if disks[i].Mount == "/" {
+			return &disks[i]
+		}
+	}
+	if len(disks) > 0 {
+		return &disks[0]
+	}
+	return nil
+}
+
+// renderAlertBanner shows firing alert rules on one highlighted line.
+func renderAlertBanner(active []alertEvent, width int) string {
+	if len(active) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(active))
+	for _, e := range active {
+		parts = append(parts, e.Rule.Metric+" "+e.Rule.format(e.Value))
+	}
+	style := dangerStyle
+	if width > 0 {
+		style = style.MaxWidth(width)
+	}
+	return style.Render("⚠ ALERT " + strings.Join(parts, " · "))
+}
+
 func getScoreStyle(score int) lipgloss.Style {
 	switch {
 	case score >= 90:
@@ -204,7 +353,7 @@ func getScoreStyle(score int) lipgloss.Style {
 	}
 }
 
-func renderCPUCard(cpu CPUStatus, thermal ThermalStatus) cardData {
+func renderCPUCard(cpu CPUStatus, thermal ThermalStatus, history MetricHistory, trend int) cardData {
 	var lines []string
 
 	// Line 1: Usage + Temp (Format: 15% @ 30.4°C)
@@ -215,7 +364,7 @@ func renderCPUCard(cpu CPUStatus, thermal ThermalStatus) cardData {
 		headerText += fmt.Sprintf(" @ %s°C", colorizeTemp(thermal.CPUTemp))
 	}
 
-	lines = append(lines, fmt.Sprintf("Total  %s  %s", usageBar, headerText))
+	lines = append(lines, fmt.Sprintf("Total  %s  %s", usageBar, headerText)+percentTrend(history.CPU, trend))
 
 	if cpu.PerCoreEstimated {
 		lines = append(lines, subtleStyle.Render("Per-core data unavailable, using averaged load"))
@@ -233,7 +382,11 @@ func renderCPUCard(cpu CPUStatus, thermal ThermalStatus) cardData {
 		maxCores := min(len(cores), 3)
 		for i := 0; i < maxCores; i++ {
 			c := cores[i]
-			lines = append(lines, fmt.Sprintf("Core%-2d %s  %5.1f%%", c.idx+1, progressBar(c.val), c.val))
+			var coreHistory []float64
+			if c.idx < len(history.PerCore) {
+				coreHistory = history.PerCore[c.idx]
+			}
+			lines = append(lines, fmt.Sprintf("Core%-2d %s  %5.1f%%", c.idx+1, progressBar(c.val), c.val)+percentTrend(coreHistory, trend))
 		}
 	}
 
@@ -245,17 +398,18 @@ func renderCPUCard(cpu CPUStatus, thermal ThermalStatus) cardData {
 		lines = append(lines, fmt.Sprintf("Load   %.2f / %.2f / %.2f, %d cores",
 			cpu.Load1, cpu.Load5, cpu.Load15, cpu.LogicalCPU))
 	}
+	lines = append(lines, cgroupCPULines(cpu)...)
 
-	return cardData{icon: iconCPU, title: "CPU", lines: lines}
+	return cardData{id: cardCPU, icon: iconCPU, title: "CPU", lines: lines}
 }
 
-func renderMemoryCard(mem MemoryStatus) cardData {
+func renderMemoryCard(mem MemoryStatus, history MetricHistory, trend int) cardData {
 	// Check if swap is being used (or at least allocated).
 	hasSwap := mem.SwapTotal > 0 || mem.SwapUsed > 0
 
 	var lines []string
 	// Line 1: Used
-	lines = append(lines, fmt.Sprintf("Used   %s  %5.1f%%", progressBar(mem.UsedPercent), mem.UsedPercent))
+	lines = append(lines, fmt.Sprintf("Used   %s  %5.1f%%", progressBar(mem.UsedPercent), mem.UsedPercent)+percentTrend(history.Memory, trend))
 
 	// Line 2: Free
 	freePercent := 100 - mem.UsedPercent
@@ -271,7 +425,7 @@ func renderMemoryCard(mem MemoryStatus) cardData {
 			swapPercent = (float64(mem.SwapUsed) / float64(mem.SwapTotal)) * 100.0
 		}
 		swapText := fmt.Sprintf("%s/%s", humanBytesCompact(mem.SwapUsed), humanBytesCompact(mem.SwapTotal))
-		lines = append(lines, fmt.Sprintf("Swap   %s  %5.1f%% %s", progressBar(swapPercent), swapPercent, swapText))
+		lines = append(lines, fmt.Sprintf("Swap   %s  %5.1f%% %s", progressBar(swapPercent), swapPercent, swapText)+percentTrend(history.Swap, trend))
 
 		lines = append(lines, fmt.Sprintf("Total  %s / %s", humanBytes(mem.Used), humanBytes(mem.Total)))
 		lines = append(lines, fmt.Sprintf("Avail  %s", humanBytes(mem.Total-mem.Used))) // Simplified avail logic for consistency
@@ -293,6 +447,9 @@ func renderMemoryCard(mem MemoryStatus) cardData {
 		available := mem.Total - mem.Used
 		lines = append(lines, fmt.Sprintf("Avail  %s", humanBytes(available)))
 	}
+	if mem.CgroupLimit > 0 {
+		lines = append(lines, cgroupMemoryLine(mem))
+	}
 	// Memory pressure status.
 	if mem.Pressure != "" {
 		pressureStyle := okStyle
@@ -305,15 +462,42 @@ func renderMemoryCard(mem MemoryStatus) cardData {
 		}
 		lines = append(lines, pressureStyle.Render(pressureText))
 	}
-	return cardData{icon: iconMemory, title: "Memory", lines: lines}
+	return cardData{id: cardMemory, icon: iconMemory, title: "Memory", lines: lines}
+}
+
+// cgroupCPULines shows usage against the cgroup's CPU quota, and how often
+// the quota held it back.
+func cgroupCPULines(cpu CPUStatus) []string {
+	if cpu.CgroupQuota <= 0 {
+		return nil
+	}
+	lines := []string{fmt.Sprintf("Quota  %s  %5.1f%% %s", progressBar(cpu.CgroupUsage), cpu.CgroupUsage, formatCores(cpu.CgroupQuota))}
+	if cpu.CgroupThrottled >= 1 {
+		lines = append(lines, warnStyle.Render(fmt.Sprintf("Throttled %.0f%% of periods", cpu.CgroupThrottled)))
+	}
+	return lines
 }
 
-func renderDiskCard(disks []DiskStatus, io DiskIOStatus) cardData {
+func cgroupMemoryLine(mem MemoryStatus) string {
+	percent := float64(mem.CgroupUsed) / float64(mem.CgroupLimit) * 100
+	return fmt.Sprintf("Limit  %s  %5.1f%% %s/%s", progressBar(percent), percent, humanBytesCompact(mem.CgroupUsed), humanBytesCompact(mem.CgroupLimit))
+}
+
+func formatCores(cores float64) string {
+	text := strings.TrimSuffix(fmt.Sprintf("%.1f", cores), ".0")
+	if text == "1" {
+		return text + " core"
+	}
+	return text + " cores"
+}
+
+func renderDiskCard(disks []DiskStatus, io DiskIOStatus, drives []DiskHealth, history MetricHistory, trend int) cardData {
 	var lines []string
 	if len(disks) == 0 {
 		lines = append(lines, subtleStyle.Render("Collecting..."))
 	} else {
-		internal, external := splitDisks(disks)
+		// The detail view lists every disk; the card keeps the largest.
+		internal, external := splitDisks(disks[:min(len(disks), cardDiskCount)])
 		addGroup := func(prefix string, list []DiskStatus) {
 			if len(list) == 0 {
 				return
@@ -329,11 +513,29 @@ func renderDiskCard(disks []DiskStatus, io DiskIOStatus) cardData {
 			lines = append(lines, subtleStyle.Render("No disks detected"))
 		}
 	}
+	if warning := diskHealthWarning(drives); warning != "" {
+		lines = append(lines, warning)
+	}
 	readBar := ioBar(io.ReadRate)
 	writeBar := ioBar(io.WriteRate)
-	lines = append(lines, fmt.Sprintf("Read   %s  %.1f MB/s", readBar, io.ReadRate))
-	lines = append(lines, fmt.Sprintf("Write  %s  %.1f MB/s", writeBar, io.WriteRate))
-	return cardData{icon: iconDisk, title: "Disk", lines: lines}
+	lines = append(lines, fmt.Sprintf("Read   %s  %-9s", readBar, fmt.Sprintf("%.1f MB/s", io.ReadRate))+ioTrend(history.DiskRead, io.ReadRate, trend))
+	lines = append(lines, fmt.Sprintf("Write  %s  %-9s", writeBar, fmt.Sprintf("%.1f MB/s", io.WriteRate))+ioTrend(history.DiskWrite, io.WriteRate, trend))
+	return cardData{id: cardDisk, icon: iconDisk, title: "Disk", lines: lines}
+}
+
+// diskHealthWarning flags the first drive that failed its SMART check or
+// logged media errors; healthy drives leave the card alone.
+func diskHealthWarning(drives []DiskHealth) string {
+	for _, d := range drives {
+		name := strings.TrimPrefix(d.Device, "/dev/")
+		if !d.Passed {
+			return dangerStyle.Render(fmt.Sprintf("SMART  %s failed self-assessment", name))
+		}
+		if d.MediaErrors > 0 {
+			return warnStyle.Render(fmt.Sprintf("SMART  %s has %d media errors", name, d.MediaErrors))
+		}
+	}
+	return ""
 }
 
 func splitDisks(disks []DiskStatus) (internal, external []DiskStatus) {
@@ -393,25 +595,114 @@ func renderProcessCard(procs []ProcessInfo) cardData {
 	if len(lines) == 0 {
 		lines = append(lines, subtleStyle.Render("No data"))
 	}
-	return cardData{icon: iconProcs, title: "Processes", lines: lines}
+	lines = append(lines, subtleStyle.Render("p for details"))
+	return cardData{id: cardProcesses, icon: iconProcs, title: "Processes", lines: lines}
+}
+
+const (
+	defaultProcessRows  = 20
+	cardDiskCount       = 3
+	cardGPUCount        = 2
+	cardSensorCount     = 4
+	cardPeripheralCount = 4
+	cardContainerCount  = 4
+)
+
+// renderProcessPane lists procs in a table with the row for selectedPID
+// highlighted. height bounds the whole pane; 0 shows defaultProcessRows.
+func renderProcessPane(procs []ProcessInfo, selectedPID int32, sortKey processSort, confirm *ProcessInfo, message string, width, height int) string {
+	if width <= 0 {
+		width = 100
+	}
+	rows := defaultProcessRows
+	if height > 0 {
+		rows = max(height-4, 1)
+	}
+
+	info := fmt.Sprintf("Sort: %s ▼  %d processes", sortKey, len(procs))
+	lines := []string{renderPaneTitle(iconProcs+" Processes", info, width)}
+
+	// Fixed columns take 56 cells; the command line gets the rest.
+	cmdWidth := max(width-56, 12)
+	lines = append(lines, subtleStyle.Render(fmt.Sprintf("  %7s  %-10s %6s %8s %5s %9s  %s", "PID", "USER", "CPU%", "RSS", "THR", "IO", "COMMAND")))
+
+	selected := selectedProcessIndex(procs, selectedPID)
+	start := 0
+	if selected >= rows {
+		start = selected - rows + 1
+	}
+	end := min(start+rows, len(procs))
+	for i := start; i < end; i++ {
+		p := procs[i]
+		io := "-"
+		if p.IORate >= 0.01 {
+			io = humanBytesCompact(uint64(p.IORate*1024*1024)) + "/s"
+		}
+		row := fmt.Sprintf("%7d  %-10s %6.1f %8s %5d %9s  %s",
+			p.PID, shorten(p.User, 10), p.CPU, humanBytesCompact(p.RSS), p.Threads, io, shorten(p.Command, cmdWidth))
+		if i == selected {
+			lines = append(lines, primaryStyle.Render("▶ "+row))
+		} else {
+			lines = append(lines, "  "+row)
+		}
+	}
+	if len(procs) == 0 {
+		lines = append(lines, subtleStyle.Render("  Collecting..."))
+	}
+
+	lines = append(lines, "")
+	switch {
+	case confirm != nil:
+		lines = append(lines, dangerStyle.Render(fmt.Sprintf("Terminate %s (PID %d)? ", confirm.Name, confirm.PID))+subtleStyle.Render("y confirm, any other key cancels"))
+	case message != "":
+		lines = append(lines, warnStyle.Render(message))
+	default:
+		lines = append(lines, subtleStyle.Render("↑↓ Select  s Sort  c/m/i CPU/Mem/IO  x Terminate  esc Back  q Quit"))
+	}
+	return strings.Join(lines, "\n")
 }
 
 func buildCards(m MetricsSnapshot, width int) []cardData {
+	trend := trendWidth(width)
 	cards := []cardData{
-		renderCPUCard(m.CPU, m.Thermal),
-		renderMemoryCard(m.Memory),
-		renderDiskCard(m.Disks, m.DiskIO),
+		renderCPUCard(m.CPU, m.Thermal, m.History, trend),
+		renderMemoryCard(m.Memory, m.History, trend),
+		renderDiskCard(m.Disks, m.DiskIO, m.DiskHealth, m.History, trend),
 		renderBatteryCard(m.Batteries, m.Thermal),
 		renderProcessCard(m.TopProcesses),
 		renderNetworkCard(m.Network, m.NetworkHistory, m.Proxy, width),
 	}
-	// Sensors card disabled - redundant with CPU temp
-	// if hasSensorData(m.Sensors) {
-	// 	cards = append(cards, renderSensorsCard(m.Sensors))
-	// }
+	if cardPresent(cardGPU, m) {
+		cards = append(cards, renderGPUCard(m.GPU, m.Thermal, m.History, trend))
+	}
+	if cardPresent(cardSensors, m) {
+		cards = append(cards, renderSensorsCard(m.Sensors))
+	}
+	if cardPresent(cardPeripherals, m) {
+		cards = append(cards, renderPeripheralsCard(m.Bluetooth))
+	}
+	if cardPresent(cardContainers, m) {
+		cards = append(cards, renderContainersCard(m.Containers))
+	}
 	return cards
 }
 
+// cardPresent reports whether a card has data to show. The core cards are
+// always drawn; the hardware-specific ones only when the machine has it.
+func cardPresent(id string, m MetricsSnapshot) bool {
+	switch id {
+	case cardGPU:
+		return len(m.GPU) > 0
+	case cardSensors:
+		return len(m.Sensors) > 0
+	case cardPeripherals:
+		return len(m.Bluetooth) > 0
+	case cardContainers:
+		return len(m.Containers) > 0
+	}
+	return true
+}
+
 func miniBar(percent float64) string {
 	filled := min(int(percent/20), 5)
 	if filled < 0 {
@@ -425,6 +716,7 @@ func renderNetworkCard(netStats []NetworkStatus, history NetworkHistory, proxy P
 	var totalRx, totalTx float64
 	var primaryIP string
 
+	netStats = busiestInterfaces(netStats)
 	for _, n := range netStats {
 		totalRx += n.RxRateMBs
 		totalTx += n.TxRateMBs
@@ -465,11 +757,24 @@ func renderNetworkCard(netStats []NetworkStatus, history NetworkHistory, proxy P
 			lines = append(lines, strings.Join(infoParts, " · "))
 		}
 	}
-	return cardData{icon: iconNetwork, title: "Network", lines: lines}
+	return cardData{id: cardNetwork, icon: iconNetwork, title: "Network", lines: lines}
 }
 
 // 8 levels: ▁▂▃▄▅▆▇█
 func sparkline(history []float64, current float64, width int) string {
+	result := sparkBlocks(history, width, 0)
+	if current > 8 {
+		return dangerStyle.Render(result)
+	}
+	if current > 3 {
+		return warnStyle.Render(result)
+	}
+	return okStyle.Render(result)
+}
+
+// sparkBlocks draws the newest width points of history, left-padded with
+// zeros. scale is the value of a full block; 0 scales to the largest point.
+func sparkBlocks(history []float64, width int, scale float64) string {
 	blocks := []rune{'▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
 
 	data := make([]float64, 0, width)
@@ -489,10 +794,13 @@ func sparkline(history []float64, current float64, width int) string {
 		data = data[len(data)-width:]
 	}
 
-	maxVal := 0.1
-	for _, v := range data {
-		if v > maxVal {
-			maxVal = v
+	maxVal := scale
+	if maxVal <= 0 {
+		maxVal = 0.1
+		for _, v := range data {
+			if v > maxVal {
+				maxVal = v
+			}
 		}
 	}
 
@@ -507,15 +815,46 @@ func sparkline(history []float64, current float64, width int) string {
 		}
 		builder.WriteRune(blocks[level])
 	}
+	return builder.String()
+}
 
-	result := builder.String()
-	if current > 8 {
-		return dangerStyle.Render(result)
+// trendWidth returns how many cells card trend lines get, or 0 when the
+// card has no room next to its values. Narrow layouts pass cardWidth 0
+// and stack cards in one full-width column.
+func trendWidth(cardWidth int) int {
+	const valueWidth = 42 // Label, bar and value before the trend
+	if cardWidth <= 0 {
+		return 10
 	}
-	if current > 3 {
-		return warnStyle.Render(result)
+	width := min(cardWidth-valueWidth, 16)
+	if width < 6 {
+		return 0
 	}
-	return okStyle.Render(result)
+	return width
+}
+
+// percentTrend is a 0-100% trend line to append after a value, coloured
+// by its newest point.
+func percentTrend(history []float64, width int) string {
+	if width <= 0 || len(history) == 0 {
+		return ""
+	}
+	return "  " + colorizePercent(history[len(history)-1], sparkBlocks(history, width, 100))
+}
+
+// ioTrend is an autoscaled MB/s trend line coloured like ioBar.
+func ioTrend(history []float64, current float64, width int) string {
+	if width <= 0 || len(history) == 0 {
+		return ""
+	}
+	line := sparkBlocks(history, width, 0)
+	switch {
+	case current > 80:
+		return "  " + dangerStyle.Render(line)
+	case current > 30:
+		return "  " + warnStyle.Render(line)
+	}
+	return "  " + okStyle.Render(line)
 }
 
 func renderBatteryCard(batts []BatteryStatus, thermal ThermalStatus) cardData {
@@ -592,13 +931,111 @@ func renderBatteryCard(batts []BatteryStatus, thermal ThermalStatus) cardData {
 		}
 	}
 
-	return cardData{icon: iconBattery, title: "Power", lines: lines}
+	return cardData{id: cardPower, icon: iconBattery, title: "Power", lines: lines}
+}
+
+func renderGPUCard(gpus []GPUStatus, thermal ThermalStatus, history MetricHistory, trend int) cardData {
+	var lines []string
+	for i, g := range gpus {
+		if i == cardGPUCount {
+			lines = append(lines, subtleStyle.Render(fmt.Sprintf("+%d more", len(gpus)-i)))
+			break
+		}
+		name := g.Name
+		if g.CoreCount > 0 {
+			name += fmt.Sprintf(", %d cores", g.CoreCount)
+		}
+		lines = append(lines, subtleStyle.Render(shorten(name, 34)))
+
+		if g.Usage >= 0 {
+			usageText := fmt.Sprintf("%5.1f%%", g.Usage)
+			if i == 0 && thermal.GPUTemp > 0 {
+				usageText += fmt.Sprintf(" @ %s°C", colorizeTemp(thermal.GPUTemp))
+			}
+			line := fmt.Sprintf("Usage  %s  %s", progressBar(g.Usage), usageText)
+			// History follows the first GPU only.
+			if i == 0 {
+				line += percentTrend(history.GPU, trend)
+			}
+			lines = append(lines, line)
+		} else {
+			lines = append(lines, "Usage  "+subtleStyle.Render("unavailable"))
+		}
+		if g.MemoryTotal > 0 {
+			memPercent := g.MemoryUsed / g.MemoryTotal * 100
+			lines = append(lines, fmt.Sprintf("VRAM   %s  %5.1f%%", progressBar(memPercent), memPercent))
+		}
+	}
+	return cardData{id: cardGPU, icon: iconGPU, title: "GPU", lines: lines}
+}
+
+// renderSensorsCard lists the hottest sensors first.
+func renderSensorsCard(readings []SensorReading) cardData {
+	sorted := make([]SensorReading, len(readings))
+	copy(sorted, readings)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Value > sorted[j].Value })
+
+	var lines []string
+	for i, r := range sorted {
+		if i == cardSensorCount {
+			lines = append(lines, subtleStyle.Render(fmt.Sprintf("+%d more", len(sorted)-i)))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("%-24s %s%s", shorten(r.Label, 24), colorizeTemp(r.Value), r.Unit))
+	}
+	return cardData{id: cardSensors, icon: iconSensors, title: "Sensors", lines: lines}
+}
+
+// renderPeripheralsCard lists Bluetooth devices, connected ones first.
+func renderPeripheralsCard(devices []BluetoothDevice) cardData {
+	sorted := make([]BluetoothDevice, len(devices))
+	copy(sorted, devices)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Connected && !sorted[j].Connected })
+
+	var lines []string
+	for i, d := range sorted {
+		if i == cardPeripheralCount {
+			lines = append(lines, subtleStyle.Render(fmt.Sprintf("+%d more", len(sorted)-i)))
+			break
+		}
+		lines = append(lines, formatPeripheral(d, 24))
+	}
+	return cardData{id: cardPeripherals, icon: iconPeripherals, title: "Peripherals", lines: lines}
+}
+
+// renderContainersCard lists the busiest running containers.
+func renderContainersCard(containers []ContainerStatus) cardData {
+	var lines []string
+	for i, ct := range containers {
+		if i == cardContainerCount {
+			lines = append(lines, subtleStyle.Render(fmt.Sprintf("+%d more", len(containers)-i)))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("%-14s  %s  %5.1f%%  %s", shorten(ct.Name, 14), miniBar(ct.CPU), ct.CPU, humanBytesCompact(ct.MemUsed)))
+	}
+	return cardData{id: cardContainers, icon: iconContainers, title: "Containers", lines: lines}
+}
+
+func formatPeripheral(d BluetoothDevice, nameWidth int) string {
+	line := fmt.Sprintf("%-*s", nameWidth, shorten(d.Name, nameWidth))
+	if d.Battery != "" {
+		line += "  " + d.Battery
+	}
+	if d.Connected {
+		return okStyle.Render("●") + " " + line
+	}
+	return subtleStyle.Render("○ " + line)
 }
 
 func renderCard(data cardData, width int, height int) string {
 	titleText := data.icon + " " + data.title
+	style, rule := titleStyle, lineStyle
+	if data.focused {
+		titleText = "▶ " + titleText
+		style, rule = primaryStyle.Bold(true), primaryStyle
+	}
 	lineLen := max(width-lipgloss.Width(titleText)-2, 4)
-	header := titleStyle.Render(titleText) + "  " + lineStyle.Render(strings.Repeat("╌", lineLen))
+	header := style.Render(titleText) + "  " + rule.Render(strings.Repeat("╌", lineLen))
 	content := header + "\n" + strings.Join(data.lines, "\n")
 
 	lines := strings.Split(content, "\n")
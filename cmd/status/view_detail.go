@@ -0,0 +1,413 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// detailTrendWidth is the trend line width in detail views, which have
+// the whole terminal to themselves.
+const detailTrendWidth = 24
+
+// renderPaneTitle draws a full-width pane title with info on the right.
+func renderPaneTitle(titleText, info string, width int) string {
+	lineLen := max(width-lipgloss.Width(titleText)-lipgloss.Width(info)-4, 4)
+	return titleStyle.Render(titleText) + "  " + lineStyle.Render(strings.Repeat("╌", lineLen)) + "  " + subtleStyle.Render(info)
+}
+
+// renderCardDetail expands one card to the full screen. height bounds the
+// pane; 0 means unbounded.
+func renderCardDetail(id string, m MetricsSnapshot, width, height int) string {
+	if width <= 0 {
+		width = 100
+	}
+	var title, info string
+	var body []string
+	switch id {
+	case cardCPU:
+		title, info = iconCPU+" CPU", fmt.Sprintf("%d cores", len(m.CPU.PerCore))
+		body = cpuDetailLines(m.CPU, m.Thermal, m.History, width, height-4)
+	case cardMemory:
+		title = iconMemory + " Memory"
+		body = memoryDetailLines(m.Memory, m.History)
+	case cardDisk:
+		title, info = iconDisk+" Disk", fmt.Sprintf("%d disks", len(m.Disks))
+		body = diskDetailLines(m.Disks, m.DiskIO, m.DiskHealth, m.History, width)
+	case cardNetwork:
+		title, info = iconNetwork+" Network", fmt.Sprintf("%d interfaces", len(m.Network))
+		body = networkDetailLines(m.Network, m.NetworkHistory, m.Proxy, m.Connections, width)
+	case cardPower:
+		title = iconBattery + " Power"
+		body = powerDetailLines(m.Batteries, m.Thermal)
+	case cardGPU:
+		title, info = iconGPU+" GPU", fmt.Sprintf("%d GPUs", len(m.GPU))
+		body = gpuDetailLines(m.GPU, m.Thermal, m.History)
+	case cardSensors:
+		title, info = iconSensors+" Sensors", fmt.Sprintf("%d sensors", len(m.Sensors))
+		body = sensorDetailLines(m.Sensors)
+	case cardPeripherals:
+		title, info = iconPeripherals+" Peripherals", fmt.Sprintf("%d devices", len(m.Bluetooth))
+		for _, d := range m.Bluetooth {
+			body = append(body, formatPeripheral(d, 40))
+		}
+	case cardContainers:
+		title, info = iconContainers+" Containers", fmt.Sprintf("%d running", len(m.Containers))
+		body = containerDetailLines(m.Containers, width)
+	}
+	if len(body) == 0 {
+		body = []string{subtleStyle.Render("No data")}
+	}
+
+	lines := []string{renderPaneTitle(title, info, width)}
+	lines = append(lines, body...)
+	if height > 0 && len(lines) > height-2 {
+		lines = lines[:max(height-2, 1)]
+	}
+	lines = append(lines, "", subtleStyle.Render("tab Next card  esc Back  q Quit"))
+	return strings.Join(lines, "\n")
+}
+
+// cpuDetailLines lists every core, spreading them over columns when they
+// do not fit in rows.
+func cpuDetailLines(cpu CPUStatus, thermal ThermalStatus, history MetricHistory, width, rows int) []string {
+	total := fmt.Sprintf("Total  %s  %5.1f%%", progressBar(cpu.Usage), cpu.Usage)
+	if thermal.CPUTemp > 0 {
+		total += fmt.Sprintf(" @ %s°C", colorizeTemp(thermal.CPUTemp))
+	}
+	lines := []string{total + percentTrend(history.CPU, detailTrendWidth)}
+	if cpu.PCoreCount > 0 && cpu.ECoreCount > 0 {
+		lines = append(lines, fmt.Sprintf("Load   %.2f / %.2f / %.2f, %dP+%dE", cpu.Load1, cpu.Load5, cpu.Load15, cpu.PCoreCount, cpu.ECoreCount))
+	} else {
+		lines = append(lines, fmt.Sprintf("Load   %.2f / %.2f / %.2f, %d cores", cpu.Load1, cpu.Load5, cpu.Load15, cpu.LogicalCPU))
+	}
+	lines = append(lines, cgroupCPULines(cpu)...)
+	lines = append(lines, "")
+
+	if cpu.PerCoreEstimated || len(cpu.PerCore) == 0 {
+		return append(lines, subtleStyle.Render("Per-core data unavailable, using averaged load"))
+	}
+
+	cells := make([]string, len(cpu.PerCore))
+	for i, usage := range cpu.PerCore {
+		var coreHistory []float64
+		if i < len(history.PerCore) {
+			coreHistory = history.PerCore[i]
+		}
+		cells[i] = fmt.Sprintf("Core%-3d %s  %5.1f%%", i+1, progressBar(usage), usage) + percentTrend(coreHistory, 10)
+	}
+	cellWidth := lipgloss.Width(cells[0]) + 4
+	maxCols := max(width/cellWidth, 1)
+	perCol := len(cells)
+	if rows > 3 {
+		perCol = max(rows-3, 1)
+	}
+	cols := min((len(cells)+perCol-1)/perCol, maxCols)
+	perCol = (len(cells) + cols - 1) / cols
+	for r := range perCol {
+		var row []string
+		for c := range cols {
+			if i := c*perCol + r; i < len(cells) {
+				row = append(row, lipgloss.NewStyle().Width(cellWidth).Render(cells[i]))
+			}
+		}
+		lines = append(lines, strings.Join(row, ""))
+	}
+	return lines
+}
+
+func memoryDetailLines(mem MemoryStatus, history MetricHistory) []string {
+	lines := []string{
+		fmt.Sprintf("Used    %s  %5.1f%%  %s", progressBar(mem.UsedPercent), mem.UsedPercent, humanBytes(mem.Used)) + percentTrend(history.Memory, detailTrendWidth),
+		fmt.Sprintf("Free    %s  %5.1f%%  %s", progressBar(100-mem.UsedPercent), 100-mem.UsedPercent, humanBytes(mem.Total-mem.Used)),
+	}
+	if mem.SwapTotal > 0 || mem.SwapUsed > 0 {
+		swapPercent := 0.0
+		if mem.SwapTotal > 0 {
+			swapPercent = float64(mem.SwapUsed) / float64(mem.SwapTotal) * 100
+		}
+		lines = append(lines, fmt.Sprintf("Swap    %s  %5.1f%%  %s / %s", progressBar(swapPercent), swapPercent, humanBytes(mem.SwapUsed), humanBytes(mem.SwapTotal))+percentTrend(history.Swap, detailTrendWidth))
+	}
+	if mem.CgroupLimit > 0 {
+		percent := float64(mem.CgroupUsed) / float64(mem.CgroupLimit) * 100
+		lines = append(lines, fmt.Sprintf("Limit   %s  %5.1f%%  %s / %s", progressBar(percent), percent, humanBytes(mem.CgroupUsed), humanBytes(mem.CgroupLimit)))
+	}
+	lines = append(lines, "", fmt.Sprintf("Total   %s", humanBytes(mem.Total)))
+	if mem.Cached > 0 {
+		lines = append(lines, fmt.Sprintf("Cached  %s", humanBytes(mem.Cached)))
+	}
+	if mem.Pressure != "" {
+		lines = append(lines, "Status  "+mem.Pressure)
+	}
+	return lines
+}
+
+// diskDetailLines lists every volume, then the activity of each whole disk
+// and the SMART health of each drive.
+func diskDetailLines(disks []DiskStatus, io DiskIOStatus, drives []DiskHealth, history MetricHistory, width int) []string {
+	mountWidth := min(max(width-74, 12), 32)
+	lines := []string{subtleStyle.Render(fmt.Sprintf("%-*s %-14s %-8s %8s %8s %6s  %s", mountWidth, "MOUNT", "DEVICE", "TYPE", "USED", "TOTAL", "USE%", "USAGE"))}
+	for _, d := range disks {
+		mount := shorten(d.Mount, mountWidth)
+		if d.External {
+			mount = shorten(d.Mount, mountWidth-2) + " ⏏"
+		}
+		lines = append(lines, fmt.Sprintf("%-*s %-14s %-8s %8s %8s %5.1f%%  %s",
+			mountWidth, mount, shorten(d.Device, 14), shorten(d.Fstype, 8),
+			humanBytesShort(d.Used), humanBytesShort(d.Total), d.UsedPercent, progressBar(d.UsedPercent)))
+	}
+	if len(disks) == 0 {
+		lines = append(lines, subtleStyle.Render("No disks detected"))
+	}
+	lines = append(lines, "",
+		fmt.Sprintf("Read   %s  %-9s", ioBar(io.ReadRate), fmt.Sprintf("%.1f MB/s", io.ReadRate))+ioTrend(history.DiskRead, io.ReadRate, detailTrendWidth),
+		fmt.Sprintf("Write  %s  %-9s", ioBar(io.WriteRate), fmt.Sprintf("%.1f MB/s", io.WriteRate))+ioTrend(history.DiskWrite, io.WriteRate, detailTrendWidth),
+	)
+
+	if len(io.Devices) > 0 {
+		lines = append(lines, "", subtleStyle.Render(fmt.Sprintf("%-14s %10s %10s %8s %8s %8s %6s", "DEVICE", "READ", "WRITE", "READS/s", "WRITES/s", "AWAIT", "BUSY")))
+		for _, d := range io.Devices {
+			lines = append(lines, fmt.Sprintf("%-14s %10s %10s %8.0f %8.0f %6.1fms %5.0f%%",
+				shorten(d.Name, 14), formatRate(d.ReadRate), formatRate(d.WriteRate), d.ReadIOPS, d.WriteIOPS, d.Await, d.Busy))
+		}
+	}
+
+	lines = append(lines, "")
+	if len(drives) == 0 {
+		return append(lines, subtleStyle.Render("No SMART data (needs smartctl, usually as root)"))
+	}
+	modelWidth := min(max(width-68, 12), 32)
+	lines = append(lines, subtleStyle.Render(fmt.Sprintf("%-14s %-*s %-6s %6s %6s %7s %9s", "DRIVE", modelWidth, "MODEL", "SMART", "WEAR", "TEMP", "ERRORS", "POWER-ON")))
+	for _, d := range drives {
+		status := okStyle.Render(fmt.Sprintf("%-6s", "OK"))
+		if !d.Passed {
+			status = dangerStyle.Render(fmt.Sprintf("%-6s", "FAILED"))
+		}
+		wear := fmt.Sprintf("%6s", "-")
+		if d.WearKnown {
+			wear = colorizePercent(d.PercentUsed, fmt.Sprintf("%5.0f%%", d.PercentUsed))
+		}
+		temp := fmt.Sprintf("%6s", "-")
+		if d.Temperature > 0 {
+			temp = fmt.Sprintf("%4.0f°C", d.Temperature)
+		}
+		errs := fmt.Sprintf("%7d", d.MediaErrors)
+		if d.MediaErrors > 0 {
+			errs = warnStyle.Render(errs)
+		}
+		lines = append(lines, fmt.Sprintf("%-14s %-*s %s %s %s %s %8dh",
+			shorten(d.Device, 14), modelWidth, shorten(d.Model, modelWidth), status, wear, temp, errs, d.PowerOnHours))
+	}
+	return lines
+}
+
+// networkDetailLines lists every interface with its own trend and counters
+// since start, then the active connections grouped by process.
+func networkDetailLines(netStats []NetworkStatus, history NetworkHistory, proxy ProxyStatus, conns []ConnectionInfo, width int) []string {
+	const ifaceTrendWidth = 12
+	lines := []string{subtleStyle.Render(fmt.Sprintf("%-14s %-15s %10s %10s  %-*s %9s %9s %9s",
+		"INTERFACE", "IP", "DOWN", "UP", ifaceTrendWidth, "TRAFFIC", "RECEIVED", "SENT", "ERR/DROP"))}
+	var totalRx, totalTx float64
+	for _, n := range netStats {
+		ip := n.IP
+		if ip == "" {
+			ip = "-"
+		}
+		ifaceHistory := history.Interfaces[n.Name]
+		traffic := make([]float64, len(ifaceHistory.Rx))
+		for i := range traffic {
+			traffic[i] = ifaceHistory.Rx[i]
+			if i < len(ifaceHistory.Tx) {
+				traffic[i] += ifaceHistory.Tx[i]
+			}
+		}
+		faults := fmt.Sprintf("%d/%d", n.ErrIn+n.ErrOut, n.DropIn+n.DropOut)
+		if n.ErrIn+n.ErrOut+n.DropIn+n.DropOut > 0 {
+			faults = warnStyle.Render(fmt.Sprintf("%9s", faults))
+		} else {
+			faults = fmt.Sprintf("%9s", faults)
+		}
+		lines = append(lines, fmt.Sprintf("%-14s %-15s %10s %10s  %s %9s %9s %s",
+			shorten(n.Name, 14), shorten(ip, 15), formatRate(n.RxRateMBs), formatRate(n.TxRateMBs),
+			sparkline(traffic, n.RxRateMBs+n.TxRateMBs, ifaceTrendWidth),
+			humanBytesShort(n.RxTotal), humanBytesShort(n.TxTotal), faults))
+	}
+	for _, n := range busiestInterfaces(netStats) {
+		totalRx += n.RxRateMBs
+		totalTx += n.TxRateMBs
+	}
+	if len(netStats) == 0 {
+		lines = append(lines, subtleStyle.Render("Collecting..."))
+	}
+	lines = append(lines, "",
+		fmt.Sprintf("Down   %s  %s", sparkline(history.RxHistory, totalRx, detailTrendWidth), formatRate(totalRx)),
+		fmt.Sprintf("Up     %s  %s", sparkline(history.TxHistory, totalTx, detailTrendWidth), formatRate(totalTx)),
+	)
+	if proxy.Enabled {
+		text := "Proxy  " + proxy.Type
+		if proxy.Host != "" {
+			text += " · " + proxy.Host
+		}
+		lines = append(lines, text)
+	}
+	return append(lines, connectionLines(conns, width)...)
+}
+
+// connectionLines summarises active sockets per process with the first
+// remote addresses each talks to.
+func connectionLines(conns []ConnectionInfo, width int) []string {
+	if len(conns) == 0 {
+		return []string{"", subtleStyle.Render("No active connections")}
+	}
+	groups := groupConnections(conns)
+	var tcp, udp int
+	for _, g := range groups {
+		tcp += g.TCP
+		udp += g.UDP
+	}
+	remoteWidth := max(width-44, 16)
+	lines := []string{
+		"",
+		titleStyle.Render("Connections") + subtleStyle.Render(fmt.Sprintf("  %d TCP · %d UDP · %d processes", tcp, udp, len(groups))),
+		subtleStyle.Render(fmt.Sprintf("%-20s %7s %5s %5s  %s", "PROCESS", "PID", "TCP", "UDP", "REMOTE")),
+	}
+	for _, g := range groups {
+		remote := "-"
+		if len(g.Remotes) > 0 {
+			remote = g.Remotes[0]
+			if len(g.Remotes) > 1 {
+				remote += fmt.Sprintf(" +%d", len(g.Remotes)-1)
+			}
+		}
+		lines = append(lines, fmt.Sprintf("%-20s %7d %5d %5d  %s", shorten(g.Name, 20), g.PID, g.TCP, g.UDP, shorten(remote, remoteWidth)))
+	}
+	return lines
+}
+
+func powerDetailLines(batts []BatteryStatus, thermal ThermalStatus) []string {
+	var lines []string
+	for i, b := range batts {
+		if len(batts) > 1 {
+			lines = append(lines, subtleStyle.Render(fmt.Sprintf("Battery %d", i+1)))
+		}
+		lines = append(lines, fmt.Sprintf("Level    %s  %5.1f%%", batteryProgressBar(b.Percent), b.Percent))
+		if b.Capacity > 0 {
+			lines = append(lines, fmt.Sprintf("Capacity %s  %5d%%", batteryProgressBar(float64(b.Capacity)), b.Capacity))
+		}
+		if b.Status != "" {
+			status := b.Status
+			if b.TimeLeft != "" {
+				status += " · " + b.TimeLeft
+			}
+			lines = append(lines, "Status   "+status)
+		}
+		if b.Health != "" {
+			lines = append(lines, "Health   "+b.Health)
+		}
+		if b.CycleCount > 0 {
+			lines = append(lines, fmt.Sprintf("Cycles   %d", b.CycleCount))
+		}
+		lines = append(lines, "")
+	}
+	if len(batts) == 0 {
+		lines = append(lines, subtleStyle.Render("No battery"), "")
+	}
+
+	var thermalLines []string
+	if thermal.CPUTemp > 0 {
+		thermalLines = append(thermalLines, fmt.Sprintf("CPU temp   %s°C", colorizeTemp(thermal.CPUTemp)))
+	}
+	if thermal.GPUTemp > 0 {
+		thermalLines = append(thermalLines, fmt.Sprintf("GPU temp   %s°C", colorizeTemp(thermal.GPUTemp)))
+	}
+	if thermal.FanCount > 0 || thermal.FanSpeed > 0 {
+		thermalLines = append(thermalLines, fmt.Sprintf("Fans       %d · %d RPM", thermal.FanCount, thermal.FanSpeed))
+	}
+	if thermal.SystemPower > 0 {
+		thermalLines = append(thermalLines, fmt.Sprintf("System     %.1fW", thermal.SystemPower))
+	}
+	if thermal.AdapterPower > 0 {
+		thermalLines = append(thermalLines, fmt.Sprintf("Adapter    %.0fW", thermal.AdapterPower))
+	}
+	if thermal.BatteryPower != 0 {
+		direction := "discharging"
+		if thermal.BatteryPower < 0 {
+			direction = "charging"
+		}
+		thermalLines = append(thermalLines, fmt.Sprintf("Battery    %.1fW %s", math.Abs(thermal.BatteryPower), direction))
+	}
+	if len(thermalLines) == 0 {
+		thermalLines = append(thermalLines, subtleStyle.Render("No thermal data"))
+	}
+	return append(lines, thermalLines...)
+}
+
+func gpuDetailLines(gpus []GPUStatus, thermal ThermalStatus, history MetricHistory) []string {
+	var lines []string
+	for i, g := range gpus {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, titleStyle.Render(g.Name))
+		if g.Usage >= 0 {
+			line := fmt.Sprintf("Usage   %s  %5.1f%%", progressBar(g.Usage), g.Usage)
+			if i == 0 {
+				line += percentTrend(history.GPU, detailTrendWidth)
+			}
+			lines = append(lines, line)
+		} else {
+			lines = append(lines, "Usage   "+subtleStyle.Render("unavailable"))
+		}
+		if g.MemoryTotal > 0 {
+			memPercent := g.MemoryUsed / g.MemoryTotal * 100
+			// nvidia-smi reports memory in MiB.
+			lines = append(lines, fmt.Sprintf("VRAM    %s  %5.1f%%  %s / %s", progressBar(memPercent), memPercent,
+				humanBytes(uint64(g.MemoryUsed*1024*1024)), humanBytes(uint64(g.MemoryTotal*1024*1024))))
+		}
+		if g.CoreCount > 0 {
+			lines = append(lines, fmt.Sprintf("Cores   %d", g.CoreCount))
+		}
+		if i == 0 && thermal.GPUTemp > 0 {
+			lines = append(lines, fmt.Sprintf("Temp    %s°C", colorizeTemp(thermal.GPUTemp)))
+		}
+		if g.Note != "" {
+			lines = append(lines, subtleStyle.Render(g.Note))
+		}
+	}
+	return lines
+}
+
+func sensorDetailLines(readings []SensorReading) []string {
+	lines := []string{subtleStyle.Render(fmt.Sprintf("%-32s %9s  %s", "SENSOR", "VALUE", "LIMITS"))}
+	for _, r := range readings {
+		value := fmt.Sprintf("%7.1f%s", r.Value, r.Unit)
+		if r.Unit == "°C" {
+			value = fmt.Sprintf("%s%s", colorizeTemp(r.Value), r.Unit)
+			value = strings.Repeat(" ", max(9-lipgloss.Width(value), 0)) + value
+		}
+		lines = append(lines, fmt.Sprintf("%-32s %s  %s", shorten(r.Label, 32), value, subtleStyle.Render(r.Note)))
+	}
+	return lines
+}
+
+func containerDetailLines(containers []ContainerStatus, width int) []string {
+	imageWidth := min(max(width-70, 12), 40)
+	lines := []string{subtleStyle.Render(fmt.Sprintf("%-24s %-7s %-*s %7s %8s %8s  %s", "NAME", "ENGINE", imageWidth, "IMAGE", "CPU", "MEM", "LIMIT", "STATE"))}
+	var totalCPU float64
+	var totalMem uint64
+	for _, ct := range containers {
+		limit := "-"
+		if ct.MemLimit > 0 {
+			limit = humanBytesShort(ct.MemLimit)
+		}
+		cpu := fmt.Sprintf("%6.1f%%", ct.CPU)
+		lines = append(lines, fmt.Sprintf("%-24s %-7s %-*s %s %8s %8s  %s",
+			shorten(ct.Name, 24), ct.Engine, imageWidth, shorten(ct.Image, imageWidth),
+			colorizePercent(ct.CPU, cpu), humanBytesShort(ct.MemUsed), limit, ct.State))
+		totalCPU += ct.CPU
+		totalMem += ct.MemUsed
+	}
+	return append(lines, "", fmt.Sprintf("Total   %.1f%% CPU · %s memory", totalCPU, humanBytes(totalMem)))
+}
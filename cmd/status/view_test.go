@@ -1,6 +1,8 @@
 package main
 
 import (
+	"fmt"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -486,9 +488,9 @@ func TestIsNoiseInterface(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := isNoiseInterface(tt.input)
+			got := defaultNetworkConfig().isNoise(tt.input)
 			if got != tt.want {
-				t.Errorf("isNoiseInterface(%q) = %v, want %v", tt.input, got, tt.want)
+				t.Errorf("isNoise(%q) = %v, want %v", tt.input, got, tt.want)
 			}
 		})
 	}
@@ -946,6 +948,236 @@ func TestSparkline(t *testing.T) {
 	}
 }
 
+func TestSparkBlocksFixedScale(t *testing.T) {
+	if got := sparkBlocks([]float64{0, 50, 100}, 3, 100); got != "▁▄█" {
+		t.Errorf("sparkBlocks() = %q, want %q", got, "▁▄█")
+	}
+	// Autoscale makes the largest point a full block.
+	if got := sparkBlocks([]float64{1, 2}, 4, 0); got != "▁▁▄█" {
+		t.Errorf("sparkBlocks() autoscale = %q, want %q", got, "▁▁▄█")
+	}
+}
+
+func TestTrendWidth(t *testing.T) {
+	tests := []struct {
+		cardWidth int
+		want      int
+	}{
+		{0, 10},
+		{36, 0},
+		{50, 8},
+		{80, 16},
+	}
+	for _, tt := range tests {
+		if got := trendWidth(tt.cardWidth); got != tt.want {
+			t.Errorf("trendWidth(%d) = %d, want %d", tt.cardWidth, got, tt.want)
+		}
+	}
+}
+
+func TestCardsShowTrends(t *testing.T) {
+	history := MetricHistory{
+		CPU:       []float64{10, 90},
+		PerCore:   [][]float64{{5, 95}},
+		Memory:    []float64{40, 60},
+		DiskRead:  []float64{1, 2},
+		DiskWrite: []float64{0, 0},
+	}
+	cpu := renderCPUCard(CPUStatus{Usage: 90, PerCore: []float64{95}, LogicalCPU: 1}, ThermalStatus{}, history, 4)
+	if !strings.HasSuffix(stripANSI(cpu.lines[0]), "  ▁▁▁▇") || !strings.HasSuffix(stripANSI(cpu.lines[1]), "  ▁▁▁▇") {
+		t.Errorf("CPU card lines missing trend: %q", cpu.lines[:2])
+	}
+	mem := renderMemoryCard(MemoryStatus{UsedPercent: 60, Total: 100, Used: 60}, history, 4)
+	if !strings.HasSuffix(stripANSI(mem.lines[0]), "  ▁▁▃▅") {
+		t.Errorf("memory Used line missing trend: %q", mem.lines[0])
+	}
+	disk := renderDiskCard(nil, DiskIOStatus{ReadRate: 2}, nil, history, 4)
+	if !strings.HasSuffix(stripANSI(disk.lines[1]), "  ▁▁▄█") {
+		t.Errorf("disk Read line missing trend: %q", disk.lines[1])
+	}
+
+	// No room: lines stay as before.
+	cpu = renderCPUCard(CPUStatus{Usage: 90, LogicalCPU: 1}, ThermalStatus{}, history, 0)
+	if strings.ContainsAny(stripANSI(cpu.lines[0]), "▁▇") {
+		t.Errorf("trend should be hidden without room: %q", cpu.lines[0])
+	}
+}
+
+func TestOptionalCardsNeedData(t *testing.T) {
+	ids := func(cards []cardData) []string {
+		var out []string
+		for _, c := range cards {
+			out = append(out, c.id)
+		}
+		return out
+	}
+
+	base := ids(buildCards(MetricsSnapshot{}, 0))
+	for _, id := range []string{cardGPU, cardSensors, cardPeripherals, cardContainers} {
+		if slices.Contains(base, id) {
+			t.Errorf("%s card should be hidden without data, got %v", id, base)
+		}
+	}
+
+	m := MetricsSnapshot{
+		GPU:       []GPUStatus{{Name: "RTX 4090", Usage: 70, MemoryUsed: 6144, MemoryTotal: 24576}},
+		Sensors:   []SensorReading{{Label: "nvme_composite", Value: 41, Unit: "°C"}, {Label: "coretemp_package_id_0", Value: 82, Unit: "°C"}},
+		Bluetooth: []BluetoothDevice{{Name: "Keyboard"}, {Name: "Headphones", Connected: true, Battery: "80%"}},
+		History:   MetricHistory{GPU: []float64{10, 70}},
+	}
+	cards := buildCards(m, 0)
+	if got := ids(cards); !slices.Equal(got[len(got)-3:], []string{cardGPU, cardSensors, cardPeripherals}) {
+		t.Fatalf("cards = %v, want GPU, sensors and peripherals at the end", got)
+	}
+	gpu, sensors, peripherals := cards[len(cards)-3], cards[len(cards)-2], cards[len(cards)-1]
+	if !strings.Contains(stripANSI(gpu.lines[1]), "70.0%") || !strings.Contains(stripANSI(gpu.lines[2]), "25.0%") {
+		t.Errorf("GPU card should show usage and VRAM: %q", gpu.lines)
+	}
+	if !strings.HasPrefix(stripANSI(sensors.lines[0]), "coretemp_package_id_0") {
+		t.Errorf("sensors card should list the hottest first: %q", sensors.lines)
+	}
+	if !strings.Contains(stripANSI(peripherals.lines[0]), "Headphones") || !strings.Contains(stripANSI(peripherals.lines[0]), "80%") {
+		t.Errorf("peripherals card should list connected devices first: %q", peripherals.lines)
+	}
+
+	// The optional cards fill the two-column grid like the rest.
+	out := renderTwoColumns(cards, 120)
+	for _, title := range []string{iconGPU + " GPU", iconSensors + " Sensors", iconPeripherals + " Peripherals"} {
+		if !strings.Contains(out, title) {
+			t.Errorf("two-column layout missing %q", title)
+		}
+	}
+}
+
+func TestGPUCardUnknownUsage(t *testing.T) {
+	card := renderGPUCard([]GPUStatus{{Name: "Apple M3", Usage: -1, CoreCount: 10}}, ThermalStatus{}, MetricHistory{}, 4)
+	if len(card.lines) != 2 || !strings.Contains(stripANSI(card.lines[0]), "10 cores") || !strings.Contains(stripANSI(card.lines[1]), "unavailable") {
+		t.Errorf("unexpected GPU card lines: %q", card.lines)
+	}
+}
+
+func TestCgroupAndContainerCards(t *testing.T) {
+	cpu := renderCPUCard(CPUStatus{Usage: 20, CgroupQuota: 2, CgroupUsage: 95, CgroupThrottled: 30}, ThermalStatus{}, MetricHistory{}, 0)
+	tail := stripANSI(strings.Join(cpu.lines[len(cpu.lines)-2:], "\n"))
+	if !strings.Contains(tail, "95.0% 2 cores") || !strings.Contains(tail, "Throttled 30% of periods") {
+		t.Errorf("CPU card should show the quota and throttling: %q", cpu.lines)
+	}
+	if plain := renderCPUCard(CPUStatus{Usage: 20}, ThermalStatus{}, MetricHistory{}, 0); strings.Contains(stripANSI(strings.Join(plain.lines, "\n")), "Quota") {
+		t.Errorf("quota line without a cgroup limit: %q", plain.lines)
+	}
+
+	mem := renderMemoryCard(MemoryStatus{Used: 8 << 30, Total: 64 << 30, UsedPercent: 12.5, CgroupLimit: 4 << 30, CgroupUsed: 3 << 30}, MetricHistory{}, 0)
+	if !strings.Contains(stripANSI(strings.Join(mem.lines, "\n")), "Limit") || !strings.Contains(stripANSI(strings.Join(mem.lines, "\n")), "75.0%") {
+		t.Errorf("memory card should show the cgroup limit: %q", mem.lines)
+	}
+
+	var containers []ContainerStatus
+	for i := range 6 {
+		containers = append(containers, ContainerStatus{Name: fmt.Sprintf("svc-%d", i), CPU: float64(60 - i*10), MemUsed: 256 << 20})
+	}
+	card := renderContainersCard(containers)
+	if len(card.lines) != cardContainerCount+1 || !strings.HasPrefix(stripANSI(card.lines[0]), "svc-0") || stripANSI(card.lines[cardContainerCount]) != "+2 more" {
+		t.Errorf("unexpected containers card: %q", card.lines)
+	}
+	if !slices.Contains(cardIDs(buildCards(MetricsSnapshot{Containers: containers}, 0)), cardContainers) {
+		t.Error("containers card should be shown when containers run")
+	}
+	detail := stripANSI(renderCardDetail(cardContainers, MetricsSnapshot{Containers: containers}, 120, 0))
+	if !strings.Contains(detail, "6 running") || !strings.Contains(detail, "Total   210.0% CPU") {
+		t.Errorf("unexpected containers detail:\n%s", detail)
+	}
+}
+
+func TestNetworkDetail(t *testing.T) {
+	m := MetricsSnapshot{
+		Network: []NetworkStatus{
+			{Name: "en0", IP: "10.0.0.2", RxRateMBs: 2, TxRateMBs: 0.5, RxTotal: 3 << 30, TxTotal: 200 << 20, ErrIn: 1, DropOut: 4},
+			{Name: "en5", RxRateMBs: 0.1},
+		},
+		NetworkHistory: NetworkHistory{Interfaces: map[string]InterfaceHistory{"en0": {Rx: []float64{0, 1, 2}, Tx: []float64{0, 0, 0.5}}}},
+		Connections: []ConnectionInfo{
+			{PID: 20, Process: "Safari", Proto: "tcp", Remote: "17.253.1.1:443"},
+			{PID: 20, Process: "Safari", Proto: "tcp", Remote: "140.82.112.3:443"},
+			{PID: 10, Process: "mDNSResponder", Proto: "udp"},
+		},
+	}
+	detail := stripANSI(renderCardDetail(cardNetwork, m, 120, 0))
+	lines := strings.Split(detail, "\n")
+	var en0 string
+	for _, line := range lines {
+		if strings.HasPrefix(line, "en0") {
+			en0 = line
+		}
+	}
+	for _, want := range []string{"10.0.0.2", "2.0 MB/s", "▁", " 3G", "200M", "1/4"} {
+		if !strings.Contains(en0, want) {
+			t.Errorf("en0 row missing %q: %q", want, en0)
+		}
+	}
+	for _, want := range []string{"Connections  2 TCP · 1 UDP · 2 processes", "17.253.1.1:443 +1", "mDNSResponder"} {
+		if !strings.Contains(detail, want) {
+			t.Errorf("detail missing %q:\n%s", want, detail)
+		}
+	}
+	if !strings.Contains(stripANSI(renderCardDetail(cardNetwork, MetricsSnapshot{}, 120, 0)), "No active connections") {
+		t.Error("empty connection list should say so")
+	}
+}
+
+func TestDiskDetailDevicesAndHealth(t *testing.T) {
+	m := MetricsSnapshot{
+		Disks: []DiskStatus{{Mount: "/", Device: "/dev/nvme0n1p2", Used: 400 << 30, Total: 1000 << 30, UsedPercent: 40}},
+		DiskIO: DiskIOStatus{ReadRate: 12, Devices: []DiskDeviceIO{
+			{Name: "nvme0n1", ReadRate: 12, ReadIOPS: 340, Await: 0.4, Busy: 21},
+		}},
+		DiskHealth: []DiskHealth{
+			{Device: "/dev/nvme0", Model: "Samsung SSD 980 PRO 1TB", Passed: true, WearKnown: true, PercentUsed: 7, Temperature: 41, PowerOnHours: 6342},
+			{Device: "/dev/sda", Model: "WDC WD40EFRX", Passed: false, MediaErrors: 8},
+		},
+	}
+	detail := stripANSI(renderCardDetail(cardDisk, m, 120, 0))
+	row := func(prefix string) string {
+		for _, line := range strings.Split(detail, "\n") {
+			if strings.HasPrefix(line, prefix) {
+				return line
+			}
+		}
+		t.Fatalf("no %q row:\n%s", prefix, detail)
+		return ""
+	}
+	for prefix, wants := range map[string][]string{
+		"nvme0n1 ":   {"12 MB/s", "340", "0.4ms", "21%"},
+		"/dev/nvme0": {"Samsung SSD 980 PRO", "OK", "7%", "41°C", "6342h"},
+		"/dev/sda":   {"FAILED", "-", "8"},
+	} {
+		line := row(prefix)
+		for _, want := range wants {
+			if !strings.Contains(line, want) {
+				t.Errorf("%s row missing %q: %q", prefix, want, line)
+			}
+		}
+	}
+
+	card := stripANSI(strings.Join(renderDiskCard(m.Disks, m.DiskIO, m.DiskHealth, MetricHistory{}, 0).lines, "\n"))
+	if !strings.Contains(card, "SMART  sda failed self-assessment") {
+		t.Errorf("card should flag the failing drive:\n%s", card)
+	}
+	if card := renderDiskCard(m.Disks, m.DiskIO, m.DiskHealth[:1], MetricHistory{}, 0); len(card.lines) != 3 {
+		t.Errorf("healthy drives should not add card lines, got %q", card.lines)
+	}
+	if !strings.Contains(stripANSI(renderCardDetail(cardDisk, MetricsSnapshot{}, 120, 0)), "No SMART data") {
+		t.Error("missing smartctl data should say so")
+	}
+}
+
+func cardIDs(cards []cardData) []string {
+	var ids []string
+	for _, c := range cards {
+		ids = append(ids, c.id)
+	}
+	return ids
+}
+
 func stripANSI(s string) string {
 	var result strings.Builder
 	i := 0
@@ -85,6 +85,32 @@ get_uptime_days() {
     echo "$uptime_days"
 }
 
+# Get memory, disk and uptime from the bundled status binary, so the numbers
+# match what mo status shows. --static skips sampling and the slow probes.
+# Fails when the binary, its --static mode or jq is unavailable.
+# Output: mem_used_gb mem_total_gb disk_used_gb disk_total_gb disk_percent uptime_days
+get_status_go_info() {
+    local status_bin="${SCRIPT_DIR:-}/bin/status-go"
+    [[ -x "$status_bin" ]] || return 1
+    command -v jq > /dev/null 2>&1 || return 1
+
+    local status_json
+    status_json=$("$status_bin" --json --static 2> /dev/null) || return 1
+
+    echo "$status_json" | jq -r '
+        def gb: . / 1073741824 * 100 | round / 100;
+        ((.disks | map(select(.mount == "/")) | first) // .disks[0] // {}) as $disk
+        | [
+            (.memory.used_bytes | gb),
+            (.memory.total_bytes | gb),
+            (($disk.used_bytes // 0) | gb),
+            (($disk.total_bytes // 0) | gb),
+            (($disk.used_percent // 0) * 10 | round / 10),
+            (.uptime_seconds / 86400 * 10 | round / 10)
+          ]
+        | map(tostring) | join(" ")' 2> /dev/null
+}
+
 # JSON escape helper
 json_escape() {
     # Escape backslash, double quote, tab, and newline
@@ -95,10 +121,16 @@ json_escape() {
 
 # Generate JSON output
 generate_health_json() {
-    # System info
-    read -r mem_used mem_total <<< "$(get_memory_info)"
-    read -r disk_used disk_total disk_percent <<< "$(get_disk_info)"
-    local uptime=$(get_uptime_days)
+    # System info (prefer the status binary, fall back to shell probes)
+    local mem_used mem_total disk_used disk_total disk_percent uptime
+    local status_info=""
+    if status_info=$(get_status_go_info) && [[ -n "$status_info" ]]; then
+        read -r mem_used mem_total disk_used disk_total disk_percent uptime <<< "$status_info"
+    else
+        read -r mem_used mem_total <<< "$(get_memory_info)"
+        read -r disk_used disk_total disk_percent <<< "$(get_disk_info)"
+        uptime=$(get_uptime_days)
+    fi
 
     # Ensure all values are valid numbers (fallback to 0)
     mem_used=${mem_used:-0}